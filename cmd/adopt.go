@@ -1,15 +1,25 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
+	"time"
 
+	"github.com/drpedapati/irl-template/pkg/analyze"
 	"github.com/drpedapati/irl-template/pkg/config"
+	"github.com/drpedapati/irl-template/pkg/fsutil"
+	"github.com/drpedapati/irl-template/pkg/gitutil"
 	"github.com/drpedapati/irl-template/pkg/naming"
+	"github.com/drpedapati/irl-template/pkg/projects"
 	"github.com/drpedapati/irl-template/pkg/scaffold"
+	"github.com/drpedapati/irl-template/pkg/status"
 	"github.com/drpedapati/irl-template/pkg/templates"
 	"github.com/drpedapati/irl-template/pkg/theme"
 	"github.com/spf13/cobra"
@@ -19,21 +29,54 @@ var (
 	adoptRenameFlag   bool
 	adoptTemplateFlag string
 	adoptDirFlag      string
+	adoptVarFlags     []string
+	adoptPurposeFlag  string
+	adoptExcludeFlag  []string
+	adoptSymlinksFlag string
+	adoptLinkFlag     string
+	adoptModeFlag     string
+	adoptDryRunFlag   bool
+	adoptNoAnalyze    bool
 )
 
 var adoptCmd = &cobra.Command{
 	Use:   "adopt <folder-path>",
 	Short: "Adopt an existing folder as an IRL project",
-	Long: `Copy an existing folder into the IRL workspace and add IRL scaffolding.
+	Long: `Bring an existing folder into the IRL workspace and add IRL scaffolding.
 
 The folder is copied to your IRL workspace directory and given a plans/main-plan.md
-file so it appears in the project list.
+file so it appears in the project list. --mode chooses how it gets there:
+
+  copy      Copy the folder into the workspace (default)
+  move      Move the folder into the workspace
+  in-place  Leave the folder where it is and list it as a project
+  symlink   Leave the folder where it is and link to it from the workspace
+
+In-place and symlink modes suit multi-GB datasets: nothing is copied, and
+the scaffolding is written into the original folder. --dry-run shows what
+would be created and committed without changing anything.
+
+The folder is inspected first: R, Python and Quarto projects, notebooks,
+data files and existing docs are noted in the new plan's First Time Setup
+and Instruction Loop sections, and folders such as data/ or results/ are
+mapped to the 02-data and 03-outputs conventions. --no-analyze skips this.
+
+Virtual environments, node_modules and caches are left out of the copy
+(see --exclude). For large data folders, --link hard shares files of 1 MB
+or more with the source instead of copying them, and --link reflink makes
+copy-on-write clones on filesystems that support them (APFS, Btrfs, XFS).
 
 Examples:
   irl adopt ~/Downloads/my-research       Copy to workspace, keep name
   irl adopt ./experiment-data --rename     Copy with YYMMDD prefix
   irl adopt ~/paper -t irl-basic           Use specific template
-  irl adopt ~/analysis -d ~/Research       Specify workspace directory`,
+  irl adopt ~/analysis -d ~/Research       Specify workspace directory
+  irl adopt ~/grant --var sponsor=NIH      Set a template variable
+  irl adopt ~/eeg --purpose "EEG pilot"    Record the project's purpose
+  irl adopt ~/eeg --exclude '*.tmp'        Leave out matching files
+  irl adopt ~/eeg --link reflink           Clone files instead of copying
+  irl adopt /data/cohort --mode in-place   Adopt without moving anything
+  irl adopt ~/eeg --mode move --dry-run    Show what a move would do`,
 	Args: cobra.ExactArgs(1),
 	RunE: runAdopt,
 }
@@ -46,6 +89,22 @@ func init() {
 		"Template to use for main-plan.md")
 	adoptCmd.Flags().StringVarP(&adoptDirFlag, "dir", "d", "",
 		"Workspace directory (overrides default)")
+	adoptCmd.Flags().StringArrayVar(&adoptVarFlags, "var", nil,
+		"Template variable as name=value (repeatable)")
+	adoptCmd.Flags().StringVar(&adoptPurposeFlag, "purpose", "",
+		"Project purpose recorded in .irl/project.json")
+	adoptCmd.Flags().StringSliceVar(&adoptExcludeFlag, "exclude", fsutil.DefaultExcludes,
+		"Names or paths to leave out (globs; --exclude= copies everything)")
+	adoptCmd.Flags().StringVar(&adoptSymlinksFlag, "symlinks", string(fsutil.SymlinksKeep),
+		"Symlinks: keep, follow (copy their targets) or skip")
+	adoptCmd.Flags().StringVar(&adoptLinkFlag, "link", "",
+		"Link files instead of copying: hard or reflink")
+	adoptCmd.Flags().StringVar(&adoptModeFlag, "mode", projects.AdoptCopy,
+		"How to adopt: copy, move, in-place or symlink")
+	adoptCmd.Flags().BoolVar(&adoptDryRunFlag, "dry-run", false,
+		"Show what would be created and committed, without changing anything")
+	adoptCmd.Flags().BoolVar(&adoptNoAnalyze, "no-analyze", false,
+		"Use the template as is instead of tailoring the plan to the folder")
 }
 
 func runAdopt(cmd *cobra.Command, args []string) error {
@@ -62,48 +121,133 @@ func runAdopt(cmd *cobra.Command, args []string) error {
 	if !info.IsDir() {
 		return fmt.Errorf("not a directory: %s", sourcePath)
 	}
+	absSource, _ := filepath.Abs(sourcePath)
 
-	// Determine workspace directory
+	mode := adoptModeFlag
+	if !projects.ValidAdoptMode(mode) {
+		return fmt.Errorf("unknown mode %q (use copy, move, in-place or symlink)", mode)
+	}
+	if mode == projects.AdoptInPlace && adoptRenameFlag {
+		return fmt.Errorf("--rename can't be used with --mode in-place; the folder keeps its name")
+	}
+
+	// Determine workspace directory. In-place projects don't need one.
 	var baseDir string
 	if adoptDirFlag != "" {
 		baseDir = expandPath(adoptDirFlag)
 	} else {
 		baseDir = config.GetDefaultDirectory()
-		if baseDir == "" {
+		if baseDir == "" && mode != projects.AdoptInPlace {
 			return fmt.Errorf("no default directory configured (run 'irl init' to set one)")
 		}
 	}
 
-	if err := os.MkdirAll(baseDir, 0755); err != nil {
-		return fmt.Errorf("cannot create workspace directory %s: %w", baseDir, err)
-	}
-
 	// Determine target name
 	folderName := filepath.Base(sourcePath)
 	if adoptRenameFlag {
 		folderName = naming.Timestamp() + "-" + naming.Slugify(folderName)
 	}
 	destPath := filepath.Join(baseDir, folderName)
-
-	// Check destination doesn't already exist
-	if _, err := os.Stat(destPath); !os.IsNotExist(err) {
+	if mode == projects.AdoptInPlace {
+		destPath = absSource
+	} else if _, err := os.Lstat(destPath); !os.IsNotExist(err) {
 		return fmt.Errorf("destination already exists: %s", destPath)
 	}
 
 	// Check source isn't already inside workspace
-	absSource, _ := filepath.Abs(sourcePath)
-	absBase, _ := filepath.Abs(baseDir)
-	if strings.HasPrefix(absSource, absBase+string(filepath.Separator)) {
-		return fmt.Errorf("folder is already inside the workspace: %s", absSource)
+	if baseDir != "" {
+		absBase, _ := filepath.Abs(baseDir)
+		if absSource == absBase || strings.HasPrefix(absSource, absBase+string(filepath.Separator)) {
+			return fmt.Errorf("folder is already inside the workspace: %s", absSource)
+		}
 	}
 
-	// Copy folder to workspace
+	// Render the plan up front so undefined variables fail before copying
+	tmpl := templates.EmbeddedTemplates["irl-basic"]
+	if adoptTemplateFlag != "" {
+		tmpl, err = templates.GetTemplate(adoptTemplateFlag)
+		if err != nil {
+			fmt.Println(theme.Note(fmt.Sprintf("%v, using basic template", err)))
+			tmpl = templates.EmbeddedTemplates["irl-basic"]
+		}
+	}
+
+	varValues, err := templates.ParseVarFlags(adoptVarFlags)
+	if err != nil {
+		return err
+	}
+	planContent, err := templates.Apply(tmpl.Content, folderName, adoptPurposeFlag, varValues)
+	if err != nil {
+		return err
+	}
+	renderValues := templates.Values(tmpl.Content, folderName, adoptPurposeFlag, varValues)
+	templateFiles, err := tmpl.Files(renderValues)
+	if err != nil {
+		return err
+	}
+	renderedTemplate := planContent
+
+	// Tailor the plan to what the folder already holds. The lock keeps the
+	// plain template, so upgrades treat the findings as the author's.
+	var report *analyze.Report
+	if !adoptNoAnalyze {
+		planContent, report, err = analyze.TailorPlan(planContent, sourcePath)
+		if err != nil {
+			fmt.Println(theme.Note(fmt.Sprintf("couldn't analyze folder: %v", err)))
+		}
+	}
+
+	opts := fsutil.Options{
+		Exclude:  adoptExcludeFlag,
+		Symlinks: fsutil.SymlinkPolicy(adoptSymlinksFlag),
+		Link:     fsutil.LinkMode(adoptLinkFlag),
+		Progress: copyProgress(),
+	}
+	if opts.Link == fsutil.LinkHard {
+		opts.LinkMin = fsutil.DefaultLinkMin
+	}
+
+	if adoptDryRunFlag {
+		return printAdoptDryRun(mode, absSource, destPath, opts, templateFiles, report)
+	}
+
+	// Bring the folder into the workspace
 	fmt.Println()
-	fmt.Println(theme.Faint("Copying folder..."))
+	switch mode {
+	case projects.AdoptCopy:
+		if err := os.MkdirAll(baseDir, 0755); err != nil {
+			return fmt.Errorf("cannot create workspace directory %s: %w", baseDir, err)
+		}
+		fmt.Println(theme.Faint("Copying folder..."))
+		copied, err := fsutil.Copy(sourcePath, destPath, opts)
+		clearProgress()
+		if err != nil {
+			return fmt.Errorf("failed to copy folder: %w", err)
+		}
+		fmt.Println(theme.Faint(copySummary(copied)))
+	case projects.AdoptMove:
+		fmt.Println(theme.Faint("Moving folder..."))
+		moved, err := fsutil.Move(sourcePath, destPath, opts.Progress)
+		clearProgress()
+		if err != nil {
+			return fmt.Errorf("failed to move folder: %w", err)
+		}
+		if moved != nil {
+			fmt.Println(theme.Faint(copySummary(moved)))
+		}
+	case projects.AdoptSymlink:
+		if err := os.MkdirAll(baseDir, 0755); err != nil {
+			return fmt.Errorf("cannot create workspace directory %s: %w", baseDir, err)
+		}
+		if err := os.Symlink(absSource, destPath); err != nil {
+			return fmt.Errorf("failed to link folder: %w", err)
+		}
+	}
 
-	cpCmd := exec.Command("cp", "-r", sourcePath, destPath)
-	if err := cpCmd.Run(); err != nil {
-		return fmt.Errorf("failed to copy folder: %w", err)
+	// Add directory template files, keeping anything the folder already has
+	created := adoptCreatedFiles(destPath, templateFiles)
+	if _, err := templates.WriteFiles(destPath, templateFiles, false); err != nil {
+		fmt.Println(theme.Note(fmt.Sprintf("couldn't add template files: %v", err)))
 	}
 
 	// Add .gitignore if not present
@@ -115,29 +259,33 @@ func runAdopt(cmd *cobra.Command, args []string) error {
 	}
 
 	// Add plans/main-plan.md if no plan file exists
-	hasPlan := fileExists(filepath.Join(destPath, "plans", "main-plan.md")) ||
-		fileExists(filepath.Join(destPath, "main-plan.md")) ||
-		fileExists(filepath.Join(destPath, "01-plans", "main-plan.md"))
+	hasPlan := adoptHasPlan(destPath)
 
 	if !hasPlan {
-		var planContent string
-		if adoptTemplateFlag != "" {
-			tmpl, err := templates.GetTemplate(adoptTemplateFlag)
-			if err != nil {
-				fmt.Println(theme.Note(fmt.Sprintf("%v, using basic template", err)))
-				tmpl = templates.EmbeddedTemplates["irl-basic"]
-			}
-			planContent = tmpl.Content
-		} else {
-			planContent = templates.EmbeddedTemplates["irl-basic"].Content
-		}
-
 		// Inject profile information
 		planContent = scaffold.InjectProfile(planContent)
 
 		if err := scaffold.WritePlan(destPath, planContent); err != nil {
 			fmt.Println(theme.Note(fmt.Sprintf("couldn't create plan: %v", err)))
 		}
+
+	}
+
+	// Record project metadata, and the template when it wrote the plan.
+	// Folders that are already IRL projects keep their metadata.
+	if !fileExists(projects.MetaPath(destPath)) {
+		meta := projects.NewMeta(adoptPurposeFlag)
+		meta.IRLVersion = Version
+		if !hasPlan {
+			meta.Template = tmpl.Name
+			meta.Source = tmpl.Source
+			meta.Hash = projects.HashContent(tmpl.Content)
+			meta.Vars = renderValues
+			meta.PlanHash = projects.HashPlan(planContent)
+		}
+		if err := projects.Lock(destPath, meta, renderedTemplate); err != nil {
+			fmt.Println(theme.Note(err.Error()))
+		}
 	}
 
 	// Git init if not already a repo, otherwise commit IRL files
@@ -148,7 +296,14 @@ func runAdopt(cmd *cobra.Command, args []string) error {
 				"couldn't set up git: %v (no worries, you can do it later)", err)))
 		}
 	} else if !hasPlan {
-		adoptCommitIRLFiles(destPath)
+		adoptCommitIRLFiles(destPath, created)
+	}
+
+	// In-place projects are listed from where they are
+	if mode == projects.AdoptInPlace {
+		if err := projects.RegisterInPlace(absSource); err != nil {
+			return fmt.Errorf("failed to register project: %w", err)
+		}
 	}
 
 	// Success output
@@ -156,8 +311,16 @@ func runAdopt(cmd *cobra.Command, args []string) error {
 		theme.OK("You're all set!"),
 		theme.Cmd(folderName))
 
-	fmt.Printf("  %s %s\n", theme.Faint("Source:"), sourcePath)
-	fmt.Printf("  %s %s\n", theme.Faint("Path:"), destPath)
+	switch mode {
+	case projects.AdoptInPlace:
+		fmt.Printf("  %s %s %s\n", theme.Faint("Path:"), destPath, theme.Faint("(in place)"))
+	case projects.AdoptSymlink:
+		fmt.Printf("  %s %s\n", theme.Faint("Path:"), absSource)
+		fmt.Printf("  %s %s\n", theme.Faint("Link:"), destPath)
+	default:
+		fmt.Printf("  %s %s\n", theme.Faint("Source:"), sourcePath)
+		fmt.Printf("  %s %s\n", theme.Faint("Path:"), destPath)
+	}
 
 	if adoptRenameFlag {
 		fmt.Printf("  %s %s\n", theme.Faint("Renamed:"), folderName)
@@ -169,6 +332,7 @@ func runAdopt(cmd *cobra.Command, args []string) error {
 			tmplName = adoptTemplateFlag
 		}
 		fmt.Printf("  %s %s\n", theme.Faint("Template:"), tmplName)
+		printAdoptFindings(report)
 	}
 
 	fmt.Printf("\n%s\n", theme.B("Next steps:"))
@@ -177,30 +341,260 @@ func runAdopt(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// printAdoptDryRun shows where the folder would go and which files
+// adopting would create and commit. The source stands in for the
+// project, since its contents are what the project starts with.
+func printAdoptDryRun(mode, source, dest string, opts fsutil.Options, files []templates.File, report *analyze.Report) error {
+	// Only copies leave anything out
+	countOpts := fsutil.Options{}
+	if mode == projects.AdoptCopy {
+		countOpts = opts
+	}
+	res, err := fsutil.Count(source, countOpts)
+	if err != nil {
+		return fmt.Errorf("cannot read folder: %w", err)
+	}
+
+	fmt.Println()
+	fmt.Println(theme.B("Dry run:") + " " + theme.Faint("nothing will be changed"))
+	fmt.Println()
+	size := fmt.Sprintf("%d files, %s", res.Files, status.FormatSize(res.Bytes))
+	switch mode {
+	case projects.AdoptCopy:
+		fmt.Printf("  %s %s → %s\n", theme.Faint("Copy"), source, dest)
+		fmt.Printf("       %s\n", theme.Faint(strings.TrimPrefix(copySummary(res), "Copied ")))
+	case projects.AdoptMove:
+		fmt.Printf("  %s %s → %s %s\n", theme.Faint("Move"), source, dest, theme.Faint("("+size+")"))
+	case projects.AdoptSymlink:
+		fmt.Printf("  %s %s → %s\n", theme.Faint("Link"), dest, source)
+	case projects.AdoptInPlace:
+		fmt.Printf("  %s %s %s\n", theme.Faint("Register"), source, theme.Faint("(in place, "+size+")"))
+	}
+
+	created := adoptCreatedFiles(source, files)
+	if !adoptHasPlan(source) {
+		printAdoptFindings(report)
+	}
+	fmt.Println()
+	fmt.Println(theme.B("Files created:"))
+	if len(created) == 0 {
+		fmt.Printf("  %s\n", theme.Faint("none, the folder is already an IRL project"))
+	}
+	for _, f := range created {
+		fmt.Printf("  %s\n", f)
+	}
+
+	fmt.Println()
+	fmt.Println(theme.B("Git:"))
+	switch {
+	case !fileExists(filepath.Join(source, ".git")):
+		committed, err := adoptInitialCommit(source, countOpts, created)
+		if err != nil {
+			fmt.Printf("  %s\n", "git init, then commit every file .gitignore doesn't exclude")
+			fmt.Printf("  %s\n", theme.Faint(fmt.Sprintf("(couldn't list them: %v)", err)))
+			break
+		}
+		fmt.Printf("  %s\n", fmt.Sprintf("git init, then commit \"Initial commit from IRL\" with %d files:", len(committed)))
+		for _, f := range committed {
+			fmt.Printf("    %s\n", f)
+		}
+	case !adoptHasPlan(source) && len(created) > 0:
+		fmt.Printf("  %s\n", "Commit \"Add IRL scaffolding (adopted project)\" with:")
+		for _, f := range created {
+			fmt.Printf("    %s\n", f)
+		}
+	default:
+		fmt.Printf("  %s\n", theme.Faint("nothing committed"))
+	}
+	return nil
+}
+
+// adoptInitialCommit lists the files the initial commit of a folder without
+// git would hold: its files and the created ones, less what its .gitignore
+// files exclude. The folder's own .gitignore is the one adopting writes
+// when it has none.
+func adoptInitialCommit(source string, opts fsutil.Options, created []string) ([]string, error) {
+	files, err := fsutil.List(source, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Match against the .gitignore files in a scratch repository, so
+	// nothing is written to the folder
+	scratch, err := os.MkdirTemp("", "irl-adopt-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(scratch)
+	if _, err := gitutil.Run(scratch, "init", "-q"); err != nil {
+		return nil, err
+	}
+	if err := scaffold.Create(scratch); err != nil {
+		return nil, err
+	}
+	for _, f := range files {
+		if path.Base(f) != ".gitignore" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(source, filepath.FromSlash(f)))
+		if err != nil {
+			return nil, err
+		}
+		dest := filepath.Join(scratch, filepath.FromSlash(f))
+		if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(dest, data, 0644); err != nil {
+			return nil, err
+		}
+	}
+
+	var candidates []string
+	seen := map[string]bool{}
+	for _, f := range append(files, created...) {
+		if !seen[f] && !slices.Contains(strings.Split(f, "/"), ".git") {
+			seen[f] = true
+			candidates = append(candidates, f)
+		}
+	}
+	cmd := exec.Command("git", "check-ignore", "--no-index", "--stdin", "-z")
+	cmd.Dir = scratch
+	cmd.Stdin = strings.NewReader(strings.Join(candidates, "\x00"))
+	out, err := cmd.Output()
+	var exitErr *exec.ExitError
+	if err != nil && !(errors.As(err, &exitErr) && exitErr.ExitCode() == 1) { // 1: nothing ignored
+		return nil, fmt.Errorf("git check-ignore: %w", err)
+	}
+	ignored := map[string]bool{}
+	for _, f := range strings.Split(string(out), "\x00") {
+		ignored[f] = true
+	}
+
+	var committed []string
+	for _, f := range candidates {
+		if !ignored[f] {
+			committed = append(committed, f)
+		}
+	}
+	sort.Strings(committed)
+	return committed, nil
+}
+
+// printAdoptFindings lists what the analysis noted in the plan
+func printAdoptFindings(report *analyze.Report) {
+	if report == nil || report.Empty() {
+		return
+	}
+	fmt.Printf("\n%s\n", theme.B("Detected:"))
+	for _, line := range append(report.Facts(), report.FolderLines()...) {
+		fmt.Printf("  %s\n", theme.Faint(strings.ReplaceAll(line, "`", "")))
+	}
+}
+
+// adoptCreatedFiles lists the files adopting adds to a folder, in the
+// order runAdopt writes them
+func adoptCreatedFiles(dir string, files []templates.File) []string {
+	var created []string
+	seen := map[string]bool{}
+	add := func(rel string) {
+		if !seen[rel] && !fileExists(filepath.Join(dir, filepath.FromSlash(rel))) {
+			created = append(created, rel)
+			seen[rel] = true
+		}
+	}
+
+	for _, f := range files {
+		if !f.IsDir {
+			add(f.Path)
+		}
+	}
+	add(".gitignore")
+	hasPlan := adoptHasPlan(dir)
+	if !hasPlan {
+		add("plans/main-plan.md")
+	}
+	if !fileExists(projects.MetaPath(dir)) {
+		add(relSlash(dir, projects.MetaPath(dir)))
+		if !hasPlan {
+			add(relSlash(dir, projects.BasePath(dir)))
+		}
+	}
+	return created
+}
+
+// relSlash returns target relative to dir with forward slashes
+func relSlash(dir, target string) string {
+	rel, err := filepath.Rel(dir, target)
+	if err != nil {
+		return target
+	}
+	return filepath.ToSlash(rel)
+}
+
+// adoptHasPlan reports whether a folder already has a plan file
+func adoptHasPlan(dir string) bool {
+	return fileExists(filepath.Join(dir, "plans", "main-plan.md")) ||
+		fileExists(filepath.Join(dir, "main-plan.md")) ||
+		fileExists(filepath.Join(dir, "01-plans", "main-plan.md"))
+}
+
+// copyProgress returns a progress callback that redraws one line on a
+// terminal, at most ten times a second
+func copyProgress() func(fsutil.Progress) {
+	if fileInfo, _ := os.Stdout.Stat(); (fileInfo.Mode() & os.ModeCharDevice) == 0 {
+		return nil
+	}
+	var last time.Time
+	return func(p fsutil.Progress) {
+		if time.Since(last) < 100*time.Millisecond && p.Files < p.TotalFiles {
+			return
+		}
+		last = time.Now()
+		fmt.Printf("\r\033[K  %s %d/%d files, %s of %s",
+			theme.Faint("Copying"), p.Files, p.TotalFiles,
+			status.FormatSize(p.Bytes), status.FormatSize(p.TotalBytes))
+	}
+}
+
+// clearProgress erases the line drawn by copyProgress
+func clearProgress() {
+	if fileInfo, _ := os.Stdout.Stat(); (fileInfo.Mode() & os.ModeCharDevice) != 0 {
+		fmt.Print("\r\033[K")
+	}
+}
+
+func copySummary(r *fsutil.Result) string {
+	s := fmt.Sprintf("Copied %d files (%s)", r.Files, status.FormatSize(r.Bytes))
+	if r.Linked > 0 {
+		s += fmt.Sprintf(", %d linked", r.Linked)
+	}
+	if r.Excluded > 0 {
+		s += fmt.Sprintf(", %d excluded", r.Excluded)
+	}
+	if r.Skipped > 0 {
+		s += fmt.Sprintf(", %d skipped", r.Skipped)
+	}
+	return s
+}
+
 func fileExists(path string) bool {
 	_, err := os.Stat(path)
 	return err == nil
 }
 
-func adoptCommitIRLFiles(projectPath string) {
-	var filesToAdd []string
-	for _, f := range []string{"plans/main-plan.md", ".gitignore"} {
-		if fileExists(filepath.Join(projectPath, f)) {
-			filesToAdd = append(filesToAdd, f)
+// adoptCommitIRLFiles commits the files adopting created, and only those,
+// so whatever the repository already had staged stays staged
+func adoptCommitIRLFiles(projectPath string, created []string) {
+	var files []string
+	for _, f := range created {
+		if fileExists(filepath.Join(projectPath, filepath.FromSlash(f))) {
+			files = append(files, f)
 		}
 	}
-	if len(filesToAdd) == 0 {
+	if len(files) == 0 {
 		return
 	}
-
-	addArgs := append([]string{"add"}, filesToAdd...)
-	addCmd := exec.Command("git", addArgs...)
-	addCmd.Dir = projectPath
-	if err := addCmd.Run(); err != nil {
-		return
+	if _, err := gitutil.CommitFiles(projectPath, "Add IRL scaffolding (adopted project)", files); err != nil {
+		fmt.Println(theme.Note(fmt.Sprintf("couldn't commit the IRL files: %v", err)))
 	}
-
-	commitCmd := exec.Command("git", "commit", "-q", "-m", "Add IRL scaffolding (adopted project)")
-	commitCmd.Dir = projectPath
-	_ = commitCmd.Run()
 }
@@ -0,0 +1,273 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/drpedapati/irl-template/pkg/archive"
+	"github.com/drpedapati/irl-template/pkg/gitutil"
+	"github.com/drpedapati/irl-template/pkg/status"
+	"github.com/drpedapati/irl-template/pkg/theme"
+	"github.com/spf13/cobra"
+)
+
+var (
+	archiveKeepFlag bool
+	archiveJSONFlag bool
+
+	restoreToFlag    string
+	restoreCheckFlag bool
+	restoreKeepFlag  bool
+	restoreJSONFlag  bool
+)
+
+var archiveCmd = &cobra.Command{
+	Use:   "archive [project]",
+	Short: "Archive a project into _archive/",
+	Long: `Pack a project into a zip in the _archive/ folder of its workspace root
+and remove it from the workspace.
+
+The archive holds every file, including git history, plus a manifest
+(irl-manifest.json) with each file's SHA-256 sum and the git HEAD. The
+archive is verified against the manifest before the project is removed.
+Bring it back with 'irl restore'.
+
+Examples:
+  irl archive my-project          # Archive and remove
+  irl archive my-project --keep   # Archive but leave the project in place`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runArchive,
+}
+
+var restoreCmd = &cobra.Command{
+	Use:   "restore [archive]",
+	Short: "Restore an archived project",
+	Long: `Restore a project archived with 'irl archive'.
+
+The archive can be given as a path to the zip or as a project name, which
+picks its newest archive in the workspace roots' _archive/ folders. Every
+file is checked against the manifest's SHA-256 sums before anything is
+written; the project goes back where it was archived from unless --to
+names another folder. The archive is deleted after a successful restore
+unless --keep is given.
+
+With no argument, list the archives.
+
+Examples:
+  irl restore                         # List archives
+  irl restore my-project              # Restore the newest archive of my-project
+  irl restore my-project --check      # Only verify the archive
+  irl restore file.zip --to ~/Research  # Restore into another folder`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runRestore,
+}
+
+func init() {
+	rootCmd.AddCommand(archiveCmd)
+	rootCmd.AddCommand(restoreCmd)
+	archiveCmd.Flags().BoolVar(&archiveKeepFlag, "keep", false, "Leave the project in place")
+	archiveCmd.Flags().BoolVar(&archiveJSONFlag, "json", false, "Output as JSON")
+	restoreCmd.Flags().StringVar(&restoreToFlag, "to", "", "Folder to restore into (default: where it was archived from)")
+	restoreCmd.Flags().BoolVar(&restoreCheckFlag, "check", false, "Verify the archive without restoring")
+	restoreCmd.Flags().BoolVar(&restoreKeepFlag, "keep", false, "Keep the archive after restoring")
+	restoreCmd.Flags().BoolVar(&restoreJSONFlag, "json", false, "Output as JSON")
+}
+
+// archiveResponse is the JSON schema for irl archive/restore --json
+type archiveResponse struct {
+	Archive string `json:"archive"`
+	Project string `json:"project"`
+	Path    string `json:"path"`
+	Files   int    `json:"files"`
+	Bytes   int64  `json:"bytes"`
+	GitHead string `json:"git_head,omitempty"`
+}
+
+// archiveListResponse is the JSON schema for irl restore --json with no archive
+type archiveListResponse struct {
+	Archives []archiveResponse `json:"archives"`
+}
+
+func runArchive(cmd *cobra.Command, args []string) error {
+	projectDir, err := projectFromArgs(args)
+	if err != nil {
+		return err
+	}
+	projectDir, err = filepath.Abs(projectDir)
+	if err != nil {
+		return err
+	}
+	if !fileExists(projectDir) {
+		return fmt.Errorf("%s not found", projectDir)
+	}
+
+	// Uncommitted work is archived too, but worth pointing out
+	if !archiveJSONFlag && gitutil.IsRepo(projectDir) {
+		if clean, err := gitutil.IsClean(projectDir); err == nil && !clean {
+			fmt.Println(theme.Note("archiving uncommitted changes"))
+		}
+	}
+
+	var (
+		dest string
+		m    *archive.Manifest
+	)
+	if archiveKeepFlag {
+		dest = filepath.Join(archive.DirFor(projectDir), archive.FileName(filepath.Base(projectDir), time.Now()))
+		m, err = archive.Create(projectDir, dest)
+	} else {
+		dest, m, err = archive.Archive(projectDir, archive.DirFor(projectDir))
+	}
+	if err != nil {
+		return err
+	}
+
+	if archiveJSONFlag {
+		return printArchiveJSON(dest, m, projectDir)
+	}
+	if archiveKeepFlag {
+		fmt.Println(theme.OK("Archived " + m.Project))
+	} else {
+		fmt.Println(theme.OK("Archived and removed " + m.Project))
+	}
+	fmt.Printf("  %s %s\n", theme.Faint("Archive:"), dest)
+	fmt.Printf("  %s %d files, %s\n", theme.Faint("Content:"), len(m.Files), status.FormatSize(m.TotalSize()))
+	if m.GitHead != "" {
+		fmt.Printf("  %s %s\n", theme.Faint("Git HEAD:"), gitutil.Short(m.GitHead))
+	}
+	if !archiveKeepFlag {
+		fmt.Println()
+		fmt.Printf("%s irl restore %s\n", theme.Faint("Restore with:"), m.Project)
+	}
+	return nil
+}
+
+func runRestore(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		return listArchives()
+	}
+
+	zipPath, err := findArchive(args[0])
+	if err != nil {
+		return err
+	}
+
+	if restoreCheckFlag {
+		m, err := archive.Verify(zipPath)
+		if err != nil {
+			return fmt.Errorf("%s: %w", filepath.Base(zipPath), err)
+		}
+		if restoreJSONFlag {
+			return printArchiveJSON(zipPath, m, m.Path)
+		}
+		fmt.Println(theme.OK(fmt.Sprintf("%s verified: %d files match the manifest", filepath.Base(zipPath), len(m.Files))))
+		return nil
+	}
+
+	m, err := archive.ReadManifest(zipPath)
+	if err != nil {
+		return err
+	}
+	dest := m.Path
+	if restoreToFlag != "" {
+		dest = filepath.Join(expandPath(restoreToFlag), m.Project)
+	}
+	if dest == "" {
+		return fmt.Errorf("archive doesn't record its origin; pass --to")
+	}
+
+	if _, err := archive.Restore(zipPath, dest); err != nil {
+		return err
+	}
+	if !restoreKeepFlag {
+		if err := os.Remove(zipPath); err != nil {
+			fmt.Println(theme.Note(fmt.Sprintf("restored, but couldn't remove the archive: %v", err)))
+		}
+	}
+
+	if restoreJSONFlag {
+		return printArchiveJSON(zipPath, m, dest)
+	}
+	fmt.Println(theme.OK(fmt.Sprintf("Restored %s (%d files verified)", m.Project, len(m.Files))))
+	fmt.Printf("  %s %s\n", theme.Faint("Path:"), dest)
+	if m.GitHead != "" {
+		if head, err := gitutil.Head(dest); err == nil && head != m.GitHead {
+			fmt.Println(theme.Note(fmt.Sprintf("git HEAD is %s, archive recorded %s", gitutil.Short(head), gitutil.Short(m.GitHead))))
+		}
+	}
+	return nil
+}
+
+// findArchive turns a restore argument into an archive path: a zip file,
+// or the newest archive of a project name
+func findArchive(arg string) (string, error) {
+	if path := expandPath(arg); strings.HasSuffix(path, ".zip") && fileExists(path) {
+		return path, nil
+	}
+	for _, e := range archive.List(archive.Dirs()) {
+		if e.Manifest.Project == arg || filepath.Base(e.Path) == arg {
+			return e.Path, nil
+		}
+	}
+	return "", fmt.Errorf("no archive named %q (run 'irl restore' to list them)", arg)
+}
+
+func listArchives() error {
+	entries := archive.List(archive.Dirs())
+
+	if restoreJSONFlag {
+		resp := archiveListResponse{Archives: []archiveResponse{}}
+		for _, e := range entries {
+			resp.Archives = append(resp.Archives, archiveResponse{
+				Archive: e.Path,
+				Project: e.Manifest.Project,
+				Path:    e.Manifest.Path,
+				Files:   len(e.Manifest.Files),
+				Bytes:   e.Manifest.TotalSize(),
+				GitHead: e.Manifest.GitHead,
+			})
+		}
+		data, err := json.MarshalIndent(resp, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(entries) == 0 {
+		fmt.Println(theme.Faint("No archived projects"))
+		return nil
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", theme.Faint("PROJECT"), theme.Faint("ARCHIVED"), theme.Faint("SIZE"), theme.Faint("ARCHIVE"))
+	for _, e := range entries {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n",
+			e.Manifest.Project, smartDate(e.Manifest.Created), status.FormatSize(e.Size), theme.Faint(e.Path))
+	}
+	w.Flush()
+	fmt.Printf("\n%s irl restore <project>\n", theme.Faint("Restore with:"))
+	return nil
+}
+
+func printArchiveJSON(zipPath string, m *archive.Manifest, path string) error {
+	resp := archiveResponse{
+		Archive: zipPath,
+		Project: m.Project,
+		Path:    path,
+		Files:   len(m.Files),
+		Bytes:   m.TotalSize(),
+		GitHead: m.GitHead,
+	}
+	data, err := json.MarshalIndent(resp, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(data))
+	return nil
+}
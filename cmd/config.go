@@ -5,8 +5,11 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/drpedapati/irl-template/pkg/config"
+	"github.com/drpedapati/irl-template/pkg/render"
 	"github.com/drpedapati/irl-template/pkg/theme"
 	"github.com/spf13/cobra"
 )
@@ -20,7 +23,17 @@ Examples:
   irl config                        # Show current config
   irl config --json                 # JSON output
   irl config --dir ~/Research       # Set default directory
-  irl config --editor cursor        # Set preferred editor`,
+  irl config --editor cursor        # Set preferred editor
+  irl config --agent claude="claude -p {prompt}"  # Set agent command for irl run
+  irl config --agent stub=          # Remove a configured agent
+  irl config --renderer "pandoc {input} -s -o {dir}/{name}.html"  # Renderer for irl render
+  irl config --renderer ""          # Back to quarto render
+  irl config --add-root /Volumes/Lab --label lab --depth 4 --ignore "raw-*"
+  irl config --remove-root lab      # Remove a workspace root by path or label
+
+Projects are listed from the default directory and any added workspace
+roots. Each root is searched up to --depth folders deep (default 3) so
+projects can be grouped by grant or year; --ignore skips matching folders.`,
 	RunE: runConfig,
 }
 
@@ -28,6 +41,14 @@ var (
 	configDirFlag    string
 	configEditorFlag string
 	configJSONFlag   bool
+	configAgentFlags []string
+	configRenderFlag string
+
+	configAddRootFlag    string
+	configRemoveRootFlag string
+	configRootLabelFlag  string
+	configRootDepthFlag  int
+	configRootIgnoreFlag []string
 )
 
 func init() {
@@ -35,6 +56,13 @@ func init() {
 	configCmd.Flags().StringVar(&configDirFlag, "dir", "", "Set default directory for new projects")
 	configCmd.Flags().StringVar(&configEditorFlag, "editor", "", "Set preferred editor (e.g., cursor, code, vim)")
 	configCmd.Flags().BoolVar(&configJSONFlag, "json", false, "Output as JSON")
+	configCmd.Flags().StringArrayVar(&configAgentFlags, "agent", nil, "Set agent command template as name=\"cmd {prompt}\" (repeatable)")
+	configCmd.Flags().StringVar(&configRenderFlag, "renderer", "", "Set renderer command template for irl render (placeholders: {input}, {name}, {dir}, {project})")
+	configCmd.Flags().StringVar(&configAddRootFlag, "add-root", "", "Add a workspace root to scan for projects")
+	configCmd.Flags().StringVar(&configRemoveRootFlag, "remove-root", "", "Remove a workspace root by path or label")
+	configCmd.Flags().StringVar(&configRootLabelFlag, "label", "", "Label for --add-root (default: folder name)")
+	configCmd.Flags().IntVar(&configRootDepthFlag, "depth", 0, "Folder levels --add-root searches for projects (default 3)")
+	configCmd.Flags().StringArrayVar(&configRootIgnoreFlag, "ignore", nil, "Folder glob --add-root skips (repeatable)")
 }
 
 func runConfig(cmd *cobra.Command, args []string) error {
@@ -70,6 +98,77 @@ func runConfig(cmd *cobra.Command, args []string) error {
 		changed = true
 	}
 
+	// Set agent commands
+	for _, a := range configAgentFlags {
+		name, command, ok := strings.Cut(a, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return fmt.Errorf("invalid --agent %q (expected name=\"command {prompt}\")", a)
+		}
+		command = strings.TrimSpace(command)
+		if err := config.SetAgentCommand(name, command); err != nil {
+			return fmt.Errorf("failed to set agent: %w", err)
+		}
+		if command == "" {
+			fmt.Printf("%s Removed agent: %s\n", theme.OK(""), theme.Cmd(name))
+		} else {
+			fmt.Printf("%s Set agent %s: %s\n", theme.OK(""), theme.Cmd(name), command)
+		}
+		changed = true
+	}
+
+	// Set renderer; an empty value restores the default
+	if cmd.Flags().Changed("renderer") {
+		command := strings.TrimSpace(configRenderFlag)
+		if err := config.SetRenderer(command); err != nil {
+			return fmt.Errorf("failed to set renderer: %w", err)
+		}
+		if command == "" {
+			fmt.Printf("%s Reset renderer: %s\n", theme.OK(""), render.DefaultCommand)
+		} else {
+			fmt.Printf("%s Set renderer: %s\n", theme.OK(""), command)
+		}
+		changed = true
+	}
+
+	// Workspace roots
+	if configAddRootFlag != "" {
+		root := config.Workspace{
+			Path:     expandPath(configAddRootFlag),
+			Label:    configRootLabelFlag,
+			MaxDepth: configRootDepthFlag,
+			Ignore:   configRootIgnoreFlag,
+		}
+		if abs, err := filepath.Abs(root.Path); err == nil {
+			root.Path = abs
+		}
+		if err := config.AddWorkspace(root); err != nil {
+			return fmt.Errorf("failed to add workspace root: %w", err)
+		}
+		status := theme.StatusTag("exists", true)
+		if _, err := os.Stat(root.Path); err != nil {
+			status = theme.StatusTag("not found", false)
+		}
+		fmt.Printf("%s Added workspace root %s: %s (%s)\n",
+			theme.OK(""), theme.Cmd(root.Name()), root.Path, status)
+		changed = true
+	}
+
+	if configRemoveRootFlag != "" {
+		removed, err := config.RemoveWorkspace(configRemoveRootFlag)
+		if abs, absErr := filepath.Abs(expandPath(configRemoveRootFlag)); err == nil && !removed && absErr == nil {
+			removed, err = config.RemoveWorkspace(abs)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to remove workspace root: %w", err)
+		}
+		if !removed {
+			return fmt.Errorf("workspace root %q not found", configRemoveRootFlag)
+		}
+		fmt.Printf("%s Removed workspace root: %s\n", theme.OK(""), configRemoveRootFlag)
+		changed = true
+	}
+
 	if changed && !configJSONFlag {
 		return nil
 	}
@@ -111,12 +210,46 @@ func runConfig(cmd *cobra.Command, args []string) error {
 			theme.Faint("(will use "+defaultDir+")"))
 	}
 
+	for i, w := range cfg.Workspaces {
+		label := "                "
+		if i == 0 {
+			label = "Workspace roots "
+		}
+		detail := fmt.Sprintf("depth %d", w.Depth())
+		if w.Project {
+			detail = "adopted in place"
+		}
+		if len(w.Ignore) > 0 {
+			detail += ", ignore " + strings.Join(w.Ignore, " ")
+		}
+		fmt.Println(theme.KeyValue(label, theme.Cmd(w.Name())+" "+w.Path+" "+theme.Faint("("+detail+")")))
+	}
+
 	if cfg.PlanEditor != "" {
 		fmt.Println(theme.KeyValue("Editor          ", cfg.PlanEditor+" ("+cfg.PlanEditorType+")"))
 	} else {
 		fmt.Printf("%s\n", theme.KeyValue("Editor          ", theme.Faint("auto-detect")))
 	}
 
+	if len(cfg.Agents) > 0 {
+		names := make([]string, 0, len(cfg.Agents))
+		for name := range cfg.Agents {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for i, name := range names {
+			label := "                "
+			if i == 0 {
+				label = "Agents          "
+			}
+			fmt.Println(theme.KeyValue(label, theme.Cmd(name)+" "+theme.Faint(cfg.Agents[name])))
+		}
+	}
+
+	if cfg.Renderer != "" {
+		fmt.Println(theme.KeyValue("Renderer        ", cfg.Renderer))
+	}
+
 	if config.HasProfile() {
 		p := cfg.Profile
 		label := p.Name
@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/drpedapati/irl-template/pkg/gitutil"
+	"github.com/drpedapati/irl-template/pkg/provenance"
+	"github.com/drpedapati/irl-template/pkg/status"
+	"github.com/drpedapati/irl-template/pkg/theme"
+	"github.com/spf13/cobra"
+)
+
+var (
+	dataNoCommitFlag bool
+	dataQuickFlag    bool
+	dataJSONFlag     bool
+)
+
+var dataCmd = &cobra.Command{
+	Use:   "data",
+	Short: "Record and verify raw data provenance",
+	Long: `Protect 02-data/raw from silent modification.
+
+'snapshot' records the SHA-256, size and modification time of every file
+under 02-data/raw in .irl/raw-data.json and commits the manifest, so it
+travels with the project even when the data itself is kept out of git.
+'verify' rehashes the files and reports those added, removed or changed
+since the snapshot, exiting with status 1 if there are any.
+
+Examples:
+  irl data snapshot                 # Record the raw data in the current project
+  irl data verify my-project        # Check a workspace project by name
+  irl data verify --quick           # Only rehash files whose size or time moved
+  irl data verify --json            # JSON for agents and CI`,
+}
+
+var dataSnapshotCmd = &cobra.Command{
+	Use:   "snapshot [project]",
+	Short: "Record hashes of every file in 02-data/raw",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runDataSnapshot,
+}
+
+var dataVerifyCmd = &cobra.Command{
+	Use:   "verify [project]",
+	Short: "Report raw files added, removed or changed since the snapshot",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runDataVerify,
+}
+
+func init() {
+	rootCmd.AddCommand(dataCmd)
+	dataCmd.AddCommand(dataSnapshotCmd)
+	dataCmd.AddCommand(dataVerifyCmd)
+	dataSnapshotCmd.Flags().BoolVar(&dataNoCommitFlag, "no-commit", false, "Write the manifest without committing it")
+	dataVerifyCmd.Flags().BoolVar(&dataQuickFlag, "quick", false, "Trust files whose size and modification time match")
+	dataVerifyCmd.Flags().BoolVar(&dataJSONFlag, "json", false, "Output as JSON")
+}
+
+func runDataSnapshot(cmd *cobra.Command, args []string) error {
+	projectDir, err := projectFromArgs(args)
+	if err != nil {
+		return err
+	}
+	if !fileExists(filepath.Join(projectDir, filepath.FromSlash(provenance.RawDir))) {
+		return fmt.Errorf("no %s folder in %s", provenance.RawDir, projectDir)
+	}
+
+	m, err := provenance.Snapshot(projectDir)
+	if err != nil {
+		return err
+	}
+	// An unchanged snapshot keeps its original date
+	if old, err := provenance.Load(projectDir); err == nil && old.SameFiles(m) {
+		fmt.Println(theme.OK(fmt.Sprintf("Raw data unchanged since %s (%d files)",
+			old.Created.Local().Format("2006-01-02 15:04"), len(old.Files))))
+		return nil
+	}
+	if err := m.Save(projectDir); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+
+	var size int64
+	for _, f := range m.Files {
+		size += f.Size
+	}
+	fmt.Println(theme.OK(fmt.Sprintf("Recorded %d raw files (%s)", len(m.Files), status.FormatSize(size))))
+	fmt.Printf("  %s %s\n", theme.Faint("Manifest:"), provenance.ManifestFile)
+
+	if dataNoCommitFlag {
+		return nil
+	}
+	if !gitutil.IsRepo(projectDir) {
+		fmt.Println(theme.Note("not a git repository; the manifest wasn't committed"))
+		return nil
+	}
+	hash, err := gitutil.CommitFiles(projectDir, "Snapshot raw data manifest", []string{provenance.ManifestFile})
+	if err != nil {
+		return fmt.Errorf("failed to commit manifest: %w", err)
+	}
+	fmt.Printf("  %s %s\n", theme.Faint("Commit:"), gitutil.Short(hash))
+	return nil
+}
+
+func runDataVerify(cmd *cobra.Command, args []string) error {
+	projectDir, err := projectFromArgs(args)
+	if err != nil {
+		return err
+	}
+
+	d, err := provenance.Verify(projectDir, dataQuickFlag)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("no raw data manifest in %s (run 'irl data snapshot' first)", projectDir)
+		}
+		return err
+	}
+
+	if dataJSONFlag {
+		data, err := json.MarshalIndent(d, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+	} else {
+		printDataDiff(filepath.Base(projectDir), d)
+	}
+
+	if !d.Clean() {
+		os.Exit(1)
+	}
+	return nil
+}
+
+func printDataDiff(project string, d *provenance.Diff) {
+	theme.Section("Raw data " + project)
+	fmt.Printf("  %s\n\n", theme.Faint("Snapshot "+d.Snapshot.Local().Format("2006-01-02 15:04")))
+
+	for _, f := range d.Added {
+		fmt.Printf("  %s %s\n", theme.Warn("+"), f)
+	}
+	for _, f := range d.Removed {
+		fmt.Printf("  %s %s\n", theme.Err("-"), f)
+	}
+	for _, f := range d.Changed {
+		fmt.Printf("  %s %s\n", theme.Err("~"), f)
+	}
+
+	if d.Clean() {
+		fmt.Printf("  %s\n", theme.OK(fmt.Sprintf("%d files match the snapshot", d.Unchanged)))
+	} else {
+		fmt.Printf("\n%s %d added, %d removed, %d changed, %d unchanged\n", theme.Faint("Total:"),
+			len(d.Added), len(d.Removed), len(d.Changed), d.Unchanged)
+	}
+	if len(d.Touched) > 0 {
+		fmt.Printf("  %s\n", theme.Faint(fmt.Sprintf("%d files have new modification times but the same content", len(d.Touched))))
+	}
+}
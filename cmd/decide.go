@@ -0,0 +1,193 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/drpedapati/irl-template/pkg/decisions"
+	"github.com/drpedapati/irl-template/pkg/gitutil"
+	"github.com/drpedapati/irl-template/pkg/theme"
+	"github.com/spf13/cobra"
+)
+
+var (
+	decideRationaleFlag  string
+	decideEvidenceFlag   string
+	decideValidationFlag string
+	decideProjectFlag    string
+	decideCommitFlag     bool
+
+	decisionsGrepFlag     string
+	decisionsSinceFlag    string
+	decisionsMarkdownFlag bool
+	decisionsOutputFlag   string
+	decisionsJSONFlag     bool
+)
+
+var decideCmd = &cobra.Command{
+	Use:   "decide <decision>",
+	Short: "Record a research decision in the decision log",
+	Long: `Record a research decision in 04-logs/decision_log.csv.
+
+Each row holds the time, the decision, its rationale, the evidence behind it,
+how it will be validated, and the project's current git commit. The log is
+created on first use; logs from the IRL template gain the git_hash column.
+
+Examples:
+  irl decide "Drop subject 12" --rationale "Impedance above 50 kOhm"
+  irl decide "Use 1-40 Hz bandpass" --rationale "Matches prior work" \
+    --evidence "Smith 2021" --validation "Compare ERP with 0.1 Hz"
+  irl decide "Switch to mixed models" -r "Unbalanced groups" --commit
+  irl decide "Freeze analysis plan" -r "Preregistered" --project my-project`,
+	Args: cobra.ExactArgs(1),
+	RunE: runDecide,
+}
+
+var decisionsCmd = &cobra.Command{
+	Use:   "decisions [project]",
+	Short: "List, filter and export the decision log",
+	Long: `List the decisions recorded in a project's 04-logs/decision_log.csv.
+
+irl init doesn't create the log; the first irl decide does. Until then the
+list is empty.
+
+Examples:
+  irl decisions                          # All decisions in the current project
+  irl decisions my-project --grep filter # Decisions mentioning "filter"
+  irl decisions --since 2026-01-01       # Decisions logged this year
+  irl decisions --markdown               # Markdown to stdout
+  irl decisions -o decisions.md          # Markdown to a file
+  irl decisions --json                   # JSON for agents and scripts`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runDecisions,
+}
+
+func init() {
+	rootCmd.AddCommand(decideCmd)
+	decideCmd.Flags().StringVarP(&decideRationaleFlag, "rationale", "r", "", "Why the decision was made (required)")
+	decideCmd.Flags().StringVarP(&decideEvidenceFlag, "evidence", "e", "", "Evidence supporting it")
+	decideCmd.Flags().StringVar(&decideValidationFlag, "validation", "", "How the decision will be checked")
+	decideCmd.Flags().StringVarP(&decideProjectFlag, "project", "p", "", "Project name or path (default: current directory)")
+	decideCmd.Flags().BoolVar(&decideCommitFlag, "commit", false, "Commit the decision log")
+
+	rootCmd.AddCommand(decisionsCmd)
+	decisionsCmd.Flags().StringVarP(&decisionsGrepFlag, "grep", "g", "", "Only decisions containing text")
+	decisionsCmd.Flags().StringVar(&decisionsSinceFlag, "since", "", "Only decisions logged on or after a date (YYYY-MM-DD)")
+	decisionsCmd.Flags().BoolVar(&decisionsMarkdownFlag, "markdown", false, "Output as markdown")
+	decisionsCmd.Flags().StringVarP(&decisionsOutputFlag, "output", "o", "", "Write markdown to a file")
+	decisionsCmd.Flags().BoolVar(&decisionsJSONFlag, "json", false, "Output as JSON")
+}
+
+func runDecide(cmd *cobra.Command, args []string) error {
+	projectDir, err := resolveProject(decideProjectFlag)
+	if err != nil {
+		return err
+	}
+
+	d, err := decisions.Append(projectDir, decisions.Decision{
+		Decision:   args[0],
+		Rationale:  decideRationaleFlag,
+		Evidence:   decideEvidenceFlag,
+		Validation: decideValidationFlag,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(theme.OK("Logged decision: " + d.Decision))
+	fmt.Printf("  %s %s\n", theme.Faint("Log:"), decisions.File)
+	if d.GitHash != "" {
+		fmt.Printf("  %s %s\n", theme.Faint("At commit:"), d.GitHash)
+	}
+
+	if !decideCommitFlag {
+		return nil
+	}
+	if !gitutil.IsRepo(projectDir) {
+		fmt.Println(theme.Note("not a git repository; the log wasn't committed"))
+		return nil
+	}
+	hash, err := gitutil.CommitFiles(projectDir, "Log decision: "+d.Decision, []string{decisions.File})
+	if err != nil {
+		return fmt.Errorf("failed to commit decision log: %w", err)
+	}
+	fmt.Printf("  %s %s\n", theme.Faint("Commit:"), gitutil.Short(hash))
+	return nil
+}
+
+func runDecisions(cmd *cobra.Command, args []string) error {
+	projectDir, err := projectFromArgs(args)
+	if err != nil {
+		return err
+	}
+
+	var since time.Time
+	if decisionsSinceFlag != "" {
+		since, err = time.ParseInLocation("2006-01-02", decisionsSinceFlag, time.Local)
+		if err != nil {
+			return fmt.Errorf("invalid --since %q (use YYYY-MM-DD)", decisionsSinceFlag)
+		}
+	}
+
+	list, err := decisions.Load(projectDir)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	list = decisions.Filter(list, decisionsGrepFlag, since)
+
+	project := filepath.Base(projectDir)
+	switch {
+	case decisionsJSONFlag:
+		if list == nil {
+			list = []decisions.Decision{}
+		}
+		data, err := json.MarshalIndent(list, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+	case decisionsOutputFlag != "":
+		md := decisions.Markdown("Decisions: "+project, list)
+		if err := os.WriteFile(expandPath(decisionsOutputFlag), []byte(md), 0644); err != nil {
+			return err
+		}
+		fmt.Println(theme.OK(fmt.Sprintf("Wrote %d decisions to %s", len(list), decisionsOutputFlag)))
+	case decisionsMarkdownFlag:
+		fmt.Print(decisions.Markdown("Decisions: "+project, list))
+	default:
+		printDecisions(project, list)
+	}
+	return nil
+}
+
+func printDecisions(project string, list []decisions.Decision) {
+	theme.Section("Decisions " + project)
+	if len(list) == 0 {
+		fmt.Printf("  %s\n", theme.Faint("No decisions found. Record one with:"))
+		fmt.Printf("  %s\n", theme.Cmd(`irl decide "..." --rationale "..."`))
+		return
+	}
+
+	for i, d := range list {
+		if i > 0 {
+			fmt.Println()
+		}
+		when := d.Timestamp
+		if d.GitHash != "" {
+			when += " " + d.GitHash
+		}
+		fmt.Printf("  %s %s\n", theme.B(d.Decision), theme.Faint(strings.TrimSpace(when)))
+		fmt.Printf("    %s %s\n", theme.Faint("Rationale:"), d.Rationale)
+		if d.Evidence != "" {
+			fmt.Printf("    %s %s\n", theme.Faint("Evidence:"), d.Evidence)
+		}
+		if d.Validation != "" {
+			fmt.Printf("    %s %s\n", theme.Faint("Validation:"), d.Validation)
+		}
+	}
+	fmt.Printf("\n%s %d\n", theme.Faint("Total:"), len(list))
+}
@@ -1,130 +1,187 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
-	"os/exec"
-	"path/filepath"
-	"runtime"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/drpedapati/irl-template/pkg/doctor"
+	"github.com/drpedapati/irl-template/pkg/provenance"
 	"github.com/drpedapati/irl-template/pkg/theme"
 	"github.com/spf13/cobra"
 )
 
-type tool struct {
-	name    string
-	cmd     string
-	install string
-}
+var (
+	doctorDataFlag    bool
+	doctorJSONFlag    bool
+	doctorTimeoutFlag time.Duration
+)
 
 var doctorCmd = &cobra.Command{
-	Use:   "doctor",
+	Use:   "doctor [project]",
 	Short: "Check environment and show recommendations",
-	Long:  "Check for required tools, AI assistants, IDEs, and system info",
-	Run:   runDoctor,
+	Long: `Check for required tools, AI assistants, IDEs, and system info.
+
+Tools are checked concurrently. Core tools (git, quarto, R, python3) are
+asked for their version, which is compared with the oldest supported one;
+--timeout bounds each check.
+
+With --data, also verify a project's 02-data/raw against the manifest
+recorded by 'irl data snapshot' and exit with status 1 if raw files were
+added, removed or changed, or the project has no manifest.
+
+--json prints the whole report, including each tool's path and version
+and "ok" (every core tool is installed at its minimum version), and exits
+with status 1 when a core tool is missing or too old, so agents and CI
+can gate on it.`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runDoctor,
 }
 
 func init() {
 	rootCmd.AddCommand(doctorCmd)
+	doctorCmd.Flags().BoolVar(&doctorDataFlag, "data", false, "Verify the project's raw data against its manifest")
+	doctorCmd.Flags().BoolVar(&doctorJSONFlag, "json", false, "Output as JSON")
+	doctorCmd.Flags().DurationVar(&doctorTimeoutFlag, "timeout", doctor.DefaultTimeout, "Time allowed for each tool check")
+}
+
+// doctorResponse is the JSON schema for irl doctor --json
+type doctorResponse struct {
+	IRLVersion string `json:"irl_version"`
+	doctor.Report
+	Data *dataCheck `json:"data,omitempty"` // with --data
+}
+
+// dataCheck is the --data result
+type dataCheck struct {
+	OK    bool             `json:"ok"`
+	Error string           `json:"error,omitempty"`
+	Diff  *provenance.Diff `json:"diff,omitempty"`
 }
 
 func runDoctor(cmd *cobra.Command, args []string) {
+	report := doctor.Run(doctorTimeoutFlag)
+	var data *dataCheck
+	if doctorDataFlag {
+		data = checkRawData(args)
+	}
+
+	if doctorJSONFlag {
+		out, err := json.MarshalIndent(doctorResponse{IRLVersion: Version, Report: report, Data: data}, "", "  ")
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		fmt.Println(string(out))
+		if !report.OK || (data != nil && !data.OK) {
+			os.Exit(1)
+		}
+		return
+	}
+
 	theme.Section("Environment")
 
 	// System info - single line
-	printSystemInfoCompact()
+	printSystemInfoCompact(report.System)
 
 	// Docs link
 	fmt.Printf("  %s %s\n", theme.Faint("Docs:"), theme.Cmd("https://www.irloop.org"))
 
 	// Two-column layout for tools
-	coreTools := []tool{
-		{"Git", "git", "brew install git"},
-		{"Quarto", "quarto", "brew install --cask quarto"},
-		{"R", "R", "brew install r"},
-		{"Python", "python3", "brew install python"},
-	}
-
-	aiTools := []tool{
-		{"Claude Code", "claude", "npm i -g @anthropic-ai/claude-code"},
-		{"Aider", "aider", "pip install aider-chat"},
-		{"Copilot CLI", "gh copilot", "gh extension install github/gh-copilot"},
-		{"Ollama", "ollama", "brew install ollama"},
-	}
-
-	ideTools := []tool{
-		{"Positron", "positron", "brew install --cask positron"},
-		{"VS Code", "code", "brew install --cask visual-studio-code"},
-		{"Cursor", "cursor", "brew install --cask cursor"},
-		{"RStudio", "rstudio", "brew install --cask rstudio"},
-	}
-
-	sandboxTools := []tool{
-		{"Docker", "docker", "brew install --cask docker"},
+	grouped := map[string][]doctor.ToolResult{}
+	for _, r := range report.Tools {
+		grouped[r.Tool.Category] = append(grouped[r.Tool.Category], r)
 	}
 
 	// Print two columns: Core Tools | AI Assistants
 	fmt.Println()
 	printColumnHeaders("Core Tools", "AI Assistants")
-	printTwoColumns(coreTools, aiTools)
+	printTwoColumns(grouped[doctor.CoreCategory], grouped["AI Assistants"])
 
 	// Print two columns: IDEs | Sandbox
 	fmt.Println()
 	printColumnHeaders("IDEs", "Sandbox")
-	printTwoColumns(ideTools, sandboxTools)
+	printTwoColumns(grouped["IDEs"], grouped["Sandbox"])
+
+	// Core tools that are too old or didn't report a version
+	var notes []string
+	for _, r := range grouped[doctor.CoreCategory] {
+		switch {
+		case !r.Found || r.OK:
+		case r.Version != "":
+			notes = append(notes, fmt.Sprintf("%s %s is older than %s (%s)", r.Tool.Name, r.Version, r.Tool.MinVersion, r.Tool.Install))
+		default:
+			notes = append(notes, fmt.Sprintf("%s: %s", r.Tool.Name, r.Error))
+		}
+	}
+	if len(notes) > 0 {
+		fmt.Println()
+		for _, n := range notes {
+			fmt.Printf("  %s\n", theme.Note(n))
+		}
+	}
 
 	// Sandbox hint
 	fmt.Println()
-	if checkCmd("docker") {
-		fmt.Printf("  %s %s\n",
-			theme.Faint("Tip:"),
-			theme.Cmd("docker sandbox run claude"))
+	for _, r := range grouped["Sandbox"] {
+		if r.Tool.Cmd == "docker" && r.Found {
+			fmt.Printf("  %s %s\n",
+				theme.Faint("Tip:"),
+				theme.Cmd("docker sandbox run claude"))
+		}
 	}
 	fmt.Println()
-}
 
-func printSystemInfoCompact() {
-	var parts []string
-
-	// Platform
-	parts = append(parts, fmt.Sprintf("%s/%s", runtime.GOOS, runtime.GOARCH))
-
-	// CPU
-	parts = append(parts, fmt.Sprintf("%d cores", runtime.NumCPU()))
+	if data != nil {
+		printRawDataCheck(data)
+		if !data.OK {
+			os.Exit(1)
+		}
+	}
+}
 
-	// Memory
-	if runtime.GOOS == "darwin" {
-		if out, err := exec.Command("sysctl", "-n", "hw.memsize").Output(); err == nil {
-			memBytes := strings.TrimSpace(string(out))
-			if len(memBytes) > 9 {
-				parts = append(parts, memBytes[:len(memBytes)-9]+" GB")
-			}
+// checkRawData verifies the project's raw data against its manifest
+func checkRawData(args []string) *dataCheck {
+	projectDir, err := projectFromArgs(args)
+	if err == nil {
+		var d *provenance.Diff
+		d, err = provenance.Verify(projectDir, false)
+		if err == nil {
+			return &dataCheck{OK: d.Clean(), Diff: d}
 		}
-	} else if runtime.GOOS == "linux" {
-		if out, err := exec.Command("free", "-g").Output(); err == nil {
-			lines := strings.Split(string(out), "\n")
-			if len(lines) > 1 {
-				fields := strings.Fields(lines[1])
-				if len(fields) > 1 {
-					parts = append(parts, fields[1]+" GB")
-				}
-			}
+		if os.IsNotExist(err) {
+			err = fmt.Errorf("no manifest (run 'irl data snapshot')")
 		}
 	}
+	return &dataCheck{Error: err.Error()}
+}
 
-	// Disk
-	if out, err := exec.Command("df", "-h", ".").Output(); err == nil {
-		lines := strings.Split(string(out), "\n")
-		if len(lines) > 1 {
-			fields := strings.Fields(lines[1])
-			if len(fields) >= 4 {
-				parts = append(parts, fields[3]+" free")
-			}
-		}
+func printRawDataCheck(c *dataCheck) {
+	theme.Section("Raw data")
+	switch {
+	case c.Diff == nil:
+		fmt.Printf("  %s\n\n", theme.Fail(c.Error))
+	case c.OK:
+		fmt.Printf("  %s\n\n", theme.OK(fmt.Sprintf("%d files match the snapshot", c.Diff.Unchanged)))
+	default:
+		d := c.Diff
+		fmt.Printf("  %s\n", theme.Fail(fmt.Sprintf("%d added, %d removed, %d changed since the snapshot",
+			len(d.Added), len(d.Removed), len(d.Changed))))
+		fmt.Printf("  %s %s\n\n", theme.Faint("Details:"), theme.Cmd("irl data verify"))
 	}
+}
 
+func printSystemInfoCompact(info doctor.SystemInfo) {
+	parts := []string{info.Platform, fmt.Sprintf("%d cores", info.Cores)}
+	if info.Memory != "" {
+		parts = append(parts, info.Memory)
+	}
+	if info.Disk != "" {
+		parts = append(parts, info.Disk)
+	}
 	fmt.Printf("  %s\n", theme.Faint(strings.Join(parts, " · ")))
 }
 
@@ -136,7 +193,7 @@ func printColumnHeaders(left, right string) {
 	fmt.Printf("  %s  %s\n", leftStyle.Render(left), rightStyle.Render(right))
 }
 
-func printTwoColumns(left, right []tool) {
+func printTwoColumns(left, right []doctor.ToolResult) {
 	maxRows := len(left)
 	if len(right) > maxRows {
 		maxRows = len(right)
@@ -161,50 +218,15 @@ func printTwoColumns(left, right []tool) {
 	}
 }
 
-func formatToolCheck(t tool) string {
-	return theme.ToolCheck(t.name, checkTool(t))
-}
-
-func checkTool(t tool) bool {
-	switch t.cmd {
-	case "gh copilot":
-		return checkGhCopilot()
-	case "positron":
-		return checkApp("Positron")
-	case "cursor":
-		return checkApp("Cursor")
-	case "rstudio":
-		return checkApp("RStudio")
-	default:
-		return checkCmd(t.cmd)
-	}
-}
-
-func checkCmd(name string) bool {
-	_, err := exec.LookPath(name)
-	return err == nil
-}
-
-func checkGhCopilot() bool {
-	out, err := exec.Command("gh", "extension", "list").Output()
-	if err != nil {
-		return false
-	}
-	return strings.Contains(string(out), "copilot")
-}
-
-func checkApp(name string) bool {
-	if runtime.GOOS != "darwin" {
-		return checkCmd(strings.ToLower(name))
-	}
-	paths := []string{
-		filepath.Join("/Applications", name+".app"),
-		filepath.Join(os.Getenv("HOME"), "Applications", name+".app"),
+// formatToolCheck shows the tool with its version, flagging one older
+// than supported
+func formatToolCheck(r doctor.ToolResult) string {
+	if !r.Found || r.Version == "" {
+		return theme.ToolCheck(r.Tool.Name, r.Found)
 	}
-	for _, p := range paths {
-		if _, err := os.Stat(p); err == nil {
-			return true
-		}
+	s := theme.ToolCheck(r.Tool.Name, true) + " " + theme.Faint(r.Version)
+	if !r.OK {
+		s += " " + theme.Warn("old")
 	}
-	return false
+	return s
 }
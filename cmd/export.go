@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/drpedapati/irl-template/pkg/export"
+	"github.com/drpedapati/irl-template/pkg/gitutil"
+	"github.com/drpedapati/irl-template/pkg/status"
+	"github.com/drpedapati/irl-template/pkg/theme"
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportFormatFlag       string
+	exportOutputFlag       string
+	exportHistoryFlag      string
+	exportIncludeFlag      []string
+	exportExcludeFlag      []string
+	exportNoRawFlag        bool
+	exportForceHistoryFlag bool
+	exportJSONFlag         bool
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export [project]",
+	Short: "Bundle a project for a collaborator or journal",
+	Long: `Bundle a project with its provenance for sharing or submission.
+
+The bundle holds the project's files (without .git), its git history as a
+git bundle or a patch series under history/, a generated EXPORT-README.md
+describing the loop history and decisions, and irl-manifest.json with the
+SHA-256 of every file. The ro-crate format is a zip that also carries an
+RO-Crate ro-crate-metadata.json built from your profile and the project
+metadata.
+
+Formats: zip (default), tar (gzipped), ro-crate
+History: bundle (default), patches, none
+
+--exclude and --include take globs matched against project paths, their
+folders and file names. --no-raw leaves out 02-data/raw for restricted
+data; its SHA-256 manifest from 'irl data snapshot' still goes along.
+When an excluded file was ever committed, the history would carry it, so
+the export stops unless you pass --history none (or --force-history).
+
+Examples:
+  irl export                                # Zip the current project
+  irl export my-project --format ro-crate   # RO-Crate for a repository or journal
+  irl export --no-raw --format tar          # Keep restricted raw data out
+  irl export --exclude '*.html' --history patches
+  irl export --include plans --include 04-logs --history none -o ~/Desktop`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runExport,
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+	exportCmd.Flags().StringVarP(&exportFormatFlag, "format", "f", export.FormatZip, "Bundle format: "+strings.Join(export.Formats, ", "))
+	exportCmd.Flags().StringVarP(&exportOutputFlag, "output", "o", "", "Bundle file or folder (default: next to the project)")
+	exportCmd.Flags().StringVar(&exportHistoryFlag, "history", export.HistoryBundle, "Git history as: "+strings.Join(export.HistoryModes, ", "))
+	exportCmd.Flags().StringArrayVar(&exportIncludeFlag, "include", nil, "Only bundle paths matching a glob (repeatable)")
+	exportCmd.Flags().StringArrayVar(&exportExcludeFlag, "exclude", nil, "Leave out paths matching a glob (repeatable)")
+	exportCmd.Flags().BoolVar(&exportNoRawFlag, "no-raw", false, "Leave out 02-data/raw")
+	exportCmd.Flags().BoolVar(&exportForceHistoryFlag, "force-history", false, "Export the git history even when it holds excluded files")
+	exportCmd.Flags().BoolVar(&exportJSONFlag, "json", false, "Output as JSON")
+}
+
+func runExport(cmd *cobra.Command, args []string) error {
+	projectDir, err := projectFromArgs(args)
+	if err != nil {
+		return err
+	}
+	projectDir, err = filepath.Abs(projectDir)
+	if err != nil {
+		return err
+	}
+	if !export.ValidFormat(exportFormatFlag) {
+		return fmt.Errorf("unknown format %q (use %s)", exportFormatFlag, strings.Join(export.Formats, ", "))
+	}
+	if !export.ValidHistory(exportHistoryFlag) {
+		return fmt.Errorf("unknown history %q (use %s)", exportHistoryFlag, strings.Join(export.HistoryModes, ", "))
+	}
+
+	dest := exportDest(projectDir)
+	res, err := export.Export(projectDir, dest, export.Options{
+		Format:  exportFormatFlag,
+		History: exportHistoryFlag,
+		Include: exportIncludeFlag,
+		Exclude: exportExcludeFlag,
+		NoRaw:   exportNoRawFlag,
+
+		ForceHistory: exportForceHistoryFlag,
+	})
+	if err != nil {
+		return err
+	}
+
+	if exportJSONFlag {
+		data, err := json.MarshalIndent(res, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	for _, w := range res.Warnings {
+		fmt.Println(theme.Note(w))
+	}
+	fmt.Println(theme.OK(fmt.Sprintf("Exported %s as %s", res.Project, res.Format)))
+	fmt.Printf("  %s %s\n", theme.Faint("Bundle:"), res.Path)
+	fmt.Printf("  %s %d files, %s\n", theme.Faint("Content:"), res.Files, status.FormatSize(res.Bytes))
+	if res.Excluded > 0 {
+		fmt.Printf("  %s %d files\n", theme.Faint("Excluded:"), res.Excluded)
+	}
+	if res.History != "" {
+		fmt.Printf("  %s %s at %s\n", theme.Faint("History:"), res.History, gitutil.Short(res.GitHead))
+	}
+	return nil
+}
+
+// exportDest picks the bundle path: --output as a file, or a dated name in
+// the --output folder, the current folder, or next to the project when
+// run from inside it
+func exportDest(projectDir string) string {
+	name := export.FileName(filepath.Base(projectDir), exportFormatFlag, time.Now())
+	if exportOutputFlag != "" {
+		out := expandPath(exportOutputFlag)
+		if info, err := os.Stat(out); err == nil && info.IsDir() {
+			return filepath.Join(out, name)
+		}
+		return out
+	}
+	cwd, err := os.Getwd()
+	if err != nil || cwd == projectDir || strings.HasPrefix(cwd, projectDir+string(filepath.Separator)) {
+		return filepath.Join(filepath.Dir(projectDir), name)
+	}
+	return filepath.Join(cwd, name)
+}
@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/drpedapati/irl-template/pkg/config"
+	"github.com/drpedapati/irl-template/pkg/importer"
+	"github.com/drpedapati/irl-template/pkg/naming"
+	"github.com/drpedapati/irl-template/pkg/projects"
+	"github.com/drpedapati/irl-template/pkg/theme"
+	"github.com/spf13/cobra"
+)
+
+var (
+	importDirFlag    string
+	importNameFlag   string
+	importRenameFlag bool
+	importJSONFlag   bool
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import <bundle|git-url|path>",
+	Short: "Import a shared project into the workspace",
+	Long: `Bring a project someone shared into the workspace.
+
+The source can be a bundle from 'irl export' or 'irl archive' (zip or
+tar.gz), a file made with 'git bundle create', a git URL or local
+repository, which is cloned, or a project folder, which is copied.
+Bundles are checked against their SHA-256 manifest, and the git history
+an export carries is rebuilt into .git. The source must hold an IRL plan
+(plans/main-plan.md or a legacy location) before anything is placed in
+the workspace.
+
+A project imported outside the workspace roots (with --dir) is registered
+where it is, so it's listed with the others.
+
+Examples:
+  irl import ~/Downloads/eeg-study_export_20260202.zip
+  irl import https://github.com/lab/eeg-study.git --rename
+  irl import /srv/git/eeg-study.git --name eeg-replication
+  irl import ~/shared/eeg-study -d ~/Research`,
+	Args: cobra.ExactArgs(1),
+	RunE: runImport,
+}
+
+func init() {
+	rootCmd.AddCommand(importCmd)
+	importCmd.Flags().StringVarP(&importDirFlag, "dir", "d", "", "Workspace directory (overrides default)")
+	importCmd.Flags().StringVar(&importNameFlag, "name", "", "Project folder name (default: the source's)")
+	importCmd.Flags().BoolVar(&importRenameFlag, "rename", false, "Rename with today's YYMMDD prefix (e.g., 260210-my-project)")
+	importCmd.Flags().BoolVar(&importJSONFlag, "json", false, "Output as JSON")
+}
+
+// importResponse is the JSON schema for irl import --json
+type importResponse struct {
+	Project    string   `json:"project"`
+	Path       string   `json:"path"`
+	Kind       string   `json:"kind"`
+	Verified   int      `json:"verified"` // files checked against a bundle manifest
+	History    bool     `json:"history_restored"`
+	Registered bool     `json:"registered"` // added as its own workspace root
+	Warnings   []string `json:"warnings"`
+}
+
+// datePrefix matches a YYMMDD- name prefix
+var datePrefix = regexp.MustCompile(`^\d{6}-`)
+
+func runImport(cmd *cobra.Command, args []string) error {
+	src := args[0]
+	if !importer.IsRemote(src) {
+		src = expandPath(src)
+	}
+
+	baseDir := config.GetDefaultDirectory()
+	if importDirFlag != "" {
+		baseDir = expandPath(importDirFlag)
+	}
+	if baseDir == "" {
+		return fmt.Errorf("no default directory configured (run 'irl init' to set one, or pass --dir)")
+	}
+	baseDir, err := filepath.Abs(baseDir)
+	if err != nil {
+		return err
+	}
+
+	if !importJSONFlag {
+		fmt.Println(theme.Faint("Fetching " + args[0] + "..."))
+	}
+	staged, err := importer.Stage(src, baseDir)
+	if err != nil {
+		return err
+	}
+
+	name := staged.Name
+	if importNameFlag != "" {
+		name = importNameFlag
+	}
+	if importRenameFlag {
+		// A shared project usually carries its own date; today's replaces it
+		name = naming.Timestamp() + "-" + naming.Slugify(datePrefix.ReplaceAllString(name, ""))
+	}
+	if err := importer.ValidName(name); err != nil {
+		staged.Discard()
+		return fmt.Errorf("invalid project name: %w (choose another with --name)", err)
+	}
+	dest := filepath.Join(baseDir, name)
+	if _, err := os.Lstat(dest); err == nil {
+		staged.Discard()
+		return fmt.Errorf("%s already exists (choose another name with --name)", dest)
+	}
+	if err := staged.Commit(dest); err != nil {
+		return err
+	}
+
+	registered := false
+	if !projects.InWorkspace(dest) {
+		if err := projects.RegisterInPlace(dest); err != nil {
+			staged.Warnings = append(staged.Warnings, fmt.Sprintf("couldn't register the project: %v", err))
+		} else {
+			registered = true
+		}
+	}
+	projects.Scan() // Refresh the workspace index
+
+	if importJSONFlag {
+		data, err := json.MarshalIndent(importResponse{
+			Project:    name,
+			Path:       dest,
+			Kind:       staged.Kind,
+			Verified:   staged.Files,
+			History:    staged.History,
+			Registered: registered,
+			Warnings:   staged.Warnings,
+		}, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	for _, w := range staged.Warnings {
+		fmt.Println(theme.Note(w))
+	}
+	fmt.Println(theme.OK("Imported " + name))
+	fmt.Printf("  %s %s\n", theme.Faint("Path:"), dest)
+	if staged.Files > 0 {
+		fmt.Printf("  %s %d files match the bundle manifest\n", theme.Faint("Verified:"), staged.Files)
+	}
+	if staged.History {
+		fmt.Printf("  %s rebuilt from the bundle\n", theme.Faint("History:"))
+	}
+	if registered {
+		fmt.Printf("  %s added as its own workspace root\n", theme.Faint("Listed:"))
+	}
+	fmt.Println()
+	fmt.Printf("%s irl status %s\n", theme.Faint("Next:"), name)
+	return nil
+}
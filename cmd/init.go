@@ -9,6 +9,7 @@ import (
 	"github.com/charmbracelet/huh"
 	"github.com/drpedapati/irl-template/pkg/config"
 	"github.com/drpedapati/irl-template/pkg/naming"
+	"github.com/drpedapati/irl-template/pkg/projects"
 	"github.com/drpedapati/irl-template/pkg/scaffold"
 	"github.com/drpedapati/irl-template/pkg/templates"
 	"github.com/drpedapati/irl-template/pkg/theme"
@@ -19,6 +20,7 @@ var (
 	templateFlag string
 	nameFlag     string
 	dirFlag      string
+	initVarFlags []string
 )
 
 var initCmd = &cobra.Command{
@@ -31,7 +33,13 @@ Examples:
   irl init "ERP analysis study"         # Auto-generates: 260129-erp-analysis-study
   irl init -n my-project                # Use exact name: my-project
   irl init -t irl-basic                 # With specific template
-  irl init -d ~/Research "APA poster"   # Create in specific directory`,
+  irl init -d ~/Research "APA poster"   # Create in specific directory
+  irl init --var sponsor=NIH "R01 aim 2" # Set a template variable
+
+Templates can reference {{project.name}}, {{project.purpose}}, {{date}},
+{{profile.name}}, {{profile.email}}, ... and custom variables declared in
+their front matter (var.<name>: Prompt, var.<name>.default: value).
+Interactive mode prompts for custom variables; undefined variables fail.`,
 	RunE: runInit,
 }
 
@@ -40,6 +48,7 @@ func init() {
 	initCmd.Flags().StringVarP(&templateFlag, "template", "t", "", "Template to use")
 	initCmd.Flags().StringVarP(&nameFlag, "name", "n", "", "Exact project name (skip auto-naming)")
 	initCmd.Flags().StringVarP(&dirFlag, "dir", "d", "", "Directory to create project in (overrides default)")
+	initCmd.Flags().StringArrayVar(&initVarFlags, "var", nil, "Template variable as name=value (repeatable)")
 }
 
 func runInit(cmd *cobra.Command, args []string) error {
@@ -47,6 +56,11 @@ func runInit(cmd *cobra.Command, args []string) error {
 	var purpose string
 	var baseDir string
 
+	varValues, err := templates.ParseVarFlags(initVarFlags)
+	if err != nil {
+		return err
+	}
+
 	// Only prompt for the base directory when no purpose/name was provided.
 	shouldPromptForDir := len(args) == 0 && nameFlag == ""
 
@@ -135,6 +149,7 @@ func runInit(cmd *cobra.Command, args []string) error {
 		if err != nil {
 			fmt.Println(theme.Note("couldn't fetch templates, using basic"))
 		}
+		templateList = append(templateList, templates.ListCustom()...)
 
 		if len(templateList) > 0 {
 			// Build options for Huh select - type-safe
@@ -162,6 +177,33 @@ func runInit(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Apply template
+	tmpl := templates.Template{Content: "# IRL Plan\n\n[Edit this file to define your research plan]\n"}
+	if selectedTemplate != "" {
+		tmpl, err = templates.GetTemplate(selectedTemplate)
+		if err != nil {
+			fmt.Println(theme.Note(fmt.Sprintf("%v, using basic template", err)))
+			tmpl = templates.EmbeddedTemplates["irl-basic"]
+		}
+	}
+
+	// Fill template variables before anything is written
+	if !nonInteractive {
+		if err := promptTemplateVars(tmpl.Content, varValues); err != nil {
+			return err
+		}
+	}
+	planContent, err := templates.Apply(tmpl.Content, projectName, purpose, varValues)
+	if err != nil {
+		return err
+	}
+	renderValues := templates.Values(tmpl.Content, projectName, purpose, varValues)
+	templateFiles, err := tmpl.Files(renderValues)
+	if err != nil {
+		return err
+	}
+	renderedTemplate := planContent
+
 	// Create project
 	fmt.Println()
 	fmt.Println(theme.Faint("Setting things up..."))
@@ -174,17 +216,9 @@ func runInit(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	// Apply template
-	var planContent string
-	if selectedTemplate != "" {
-		tmpl, err := templates.GetTemplate(selectedTemplate)
-		if err != nil {
-			fmt.Println(theme.Note(fmt.Sprintf("%v, using basic template", err)))
-			tmpl = templates.EmbeddedTemplates["irl-basic"]
-		}
-		planContent = tmpl.Content
-	} else {
-		planContent = "# IRL Plan\n\n[Edit this file to define your research plan]\n"
+	// Directory templates bring their own files, including .gitignore overrides
+	if _, err := templates.WriteFiles(projectPath, templateFiles, true); err != nil {
+		return err
 	}
 
 	// Inject profile information
@@ -194,6 +228,20 @@ func runInit(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	// Record project metadata and the template so the plan can be upgraded later
+	meta := projects.NewMeta(purpose)
+	meta.IRLVersion = Version
+	if tmpl.Name != "" {
+		meta.Template = tmpl.Name
+		meta.Source = tmpl.Source
+		meta.Hash = projects.HashContent(tmpl.Content)
+		meta.Vars = renderValues
+		meta.PlanHash = projects.HashPlan(planContent)
+	}
+	if err := projects.Lock(projectPath, meta, renderedTemplate); err != nil {
+		fmt.Println(theme.Note(err.Error()))
+	}
+
 	if err := scaffold.GitInit(projectPath); err != nil {
 		fmt.Println(theme.Note(fmt.Sprintf("couldn't set up git: %v (no worries, you can do it later)", err)))
 	}
@@ -222,6 +270,37 @@ func runInit(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// promptTemplateVars asks for each custom variable the template declares
+// that wasn't set with --var, pre-filled with its default
+func promptTemplateVars(content string, values map[string]string) error {
+	vars, _ := templates.ParseVariables(content)
+
+	answers := map[string]*string{}
+	var fields []huh.Field
+	for _, v := range vars {
+		if _, ok := values[v.Name]; ok {
+			continue
+		}
+		answer := v.Default
+		answers[v.Name] = &answer
+		fields = append(fields, huh.NewInput().
+			Title(v.Prompt).
+			Description("{{"+v.Name+"}}").
+			Value(&answer))
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+
+	if err := theme.NewForm(huh.NewGroup(fields...)).Run(); err != nil {
+		return err
+	}
+	for name, answer := range answers {
+		values[name] = *answer
+	}
+	return nil
+}
+
 func getOrAskDefaultDirectory() string {
 	// Check if already configured
 	defaultDir := config.GetDefaultDirectory()
@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/drpedapati/irl-template/pkg/lint"
+	"github.com/drpedapati/irl-template/pkg/projects"
+	"github.com/drpedapati/irl-template/pkg/theme"
+	"github.com/spf13/cobra"
+)
+
+var (
+	lintJSONFlag     bool
+	lintTemplateFlag string
+	lintStrictFlag   bool
+)
+
+var lintCmd = &cobra.Command{
+	Use:   "lint [project]",
+	Short: "Check a plan for structural problems",
+	Long: `Check a project's main-plan.md against its source template.
+
+Errors:
+  - missing plan or required template sections
+  - edits to template sections outside an AUTHOR AREA
+  - malformed front matter
+  - duplicate headings
+  - references to 02-data/raw paths that don't exist
+
+Warnings:
+  - log files named in "After Each Loop" that are missing once loops
+    have started writing the others
+
+The plan is compared with the template as it was rendered for the project
+(.irl/template.md), or with the catalog template when the project has no
+record of it or --template is given.
+
+Exits with status 1 when errors are found (or warnings, with --strict),
+so it can be used as a pre-commit hook.
+
+Examples:
+  irl lint                        # Lint the project in the current directory
+  irl lint my-project             # Lint a workspace project by name
+  irl lint --json                 # JSON for agents and CI
+  irl lint --template my-lab      # Compare against a custom template`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runLint,
+}
+
+func init() {
+	rootCmd.AddCommand(lintCmd)
+	lintCmd.Flags().BoolVar(&lintJSONFlag, "json", false, "Output as JSON")
+	lintCmd.Flags().StringVarP(&lintTemplateFlag, "template", "t", "", "Template the plan was created from (default: the one in .irl/project.json, else irl-basic)")
+	lintCmd.Flags().BoolVar(&lintStrictFlag, "strict", false, "Treat warnings as errors")
+}
+
+func runLint(cmd *cobra.Command, args []string) error {
+	projectDir, err := projectFromArgs(args)
+	if err != nil {
+		return err
+	}
+
+	name := lintTemplateFlag
+	if name == "" {
+		name = "irl-basic"
+		if meta, err := projects.LoadMeta(projectDir); err == nil && meta.Template != "" {
+			name = meta.Template
+		}
+	}
+
+	opts := lint.Options{TemplateName: name}
+	var tmplErr error
+	if base, err := projects.ReadBase(projectDir); err == nil && lintTemplateFlag == "" {
+		opts.TemplateContent = base
+	} else if content, err := loadTemplateContent(name); err == nil {
+		opts.TemplateContent = content
+	} else {
+		tmplErr = err
+	}
+
+	result := lint.Check(projectDir, opts)
+
+	if lintJSONFlag {
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+	} else {
+		printLintResult(result, tmplErr)
+	}
+
+	if !result.OK(lintStrictFlag) {
+		os.Exit(1)
+	}
+	return nil
+}
+
+func printLintResult(r *lint.Result, tmplErr error) {
+	theme.Section("Lint " + filepath.Base(r.Project))
+	if r.Plan != "" {
+		fmt.Printf("  %s\n", theme.Faint(r.Plan))
+	}
+	if tmplErr != nil {
+		fmt.Printf("  %s\n", theme.Note(tmplErr.Error()+"; skipping template checks"))
+	}
+	fmt.Println()
+
+	if len(r.Issues) == 0 {
+		fmt.Printf("  %s\n", theme.OK("No problems found"))
+		return
+	}
+
+	for _, i := range r.Issues {
+		loc := ""
+		if i.Line > 0 {
+			loc = fmt.Sprintf("%d: ", i.Line)
+		}
+		mark := theme.Err(theme.Cross)
+		if i.Severity == lint.SeverityWarning {
+			mark = theme.Warn("!")
+		}
+		fmt.Printf("  %s %s%s %s\n", mark, theme.Faint(loc), i.Message, theme.Faint("["+i.Rule+"]"))
+	}
+
+	fmt.Printf("\n%s %d errors, %d warnings\n", theme.Faint("Total:"), r.Errors(), r.Warnings())
+}
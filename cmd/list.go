@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 	"text/tabwriter"
 	"time"
 
@@ -14,21 +15,31 @@ import (
 )
 
 var (
-	listJSONFlag bool
-	listDirFlag  string
+	listJSONFlag   bool
+	listDirFlag    string
+	listStatusFlag string
+	listTagFlags   []string
+	listRootFlag   string
 )
 
 var listCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List IRL projects in workspace",
-	Long: `List all IRL projects in the configured workspace directory.
+	Long: `List all IRL projects in the configured workspace roots.
 
 A folder is considered a project if it contains a main-plan.md file.
+Roots are the default directory plus any added with 'irl config --add-root';
+each is searched a few folders deep (see 'irl config --help').
+Purpose, status and tags come from the project's .irl/project.json
+(see 'irl meta'); projects without one are active.
 
 Examples:
   irl list                        # Table output
   irl list --json                 # JSON for agents
-  irl list --json --dir ~/Research  # Scope to specific directory`,
+  irl list --json --dir ~/Research  # Scope to specific directory
+  irl list --status paused        # Only paused projects
+  irl list --tag eeg --tag pilot  # Projects with both tags
+  irl list --root archive-drive   # Projects in one workspace root`,
 	Aliases: []string{"ls"},
 	RunE:    runList,
 }
@@ -37,6 +48,9 @@ func init() {
 	rootCmd.AddCommand(listCmd)
 	listCmd.Flags().BoolVar(&listJSONFlag, "json", false, "Output as JSON")
 	listCmd.Flags().StringVar(&listDirFlag, "dir", "", "Workspace directory to scan (overrides configured default)")
+	listCmd.Flags().StringVar(&listStatusFlag, "status", "", "Only list projects with this status (active, paused, archived)")
+	listCmd.Flags().StringArrayVar(&listTagFlags, "tag", nil, "Only list projects with this tag (repeatable)")
+	listCmd.Flags().StringVar(&listRootFlag, "root", "", "Only list projects in the workspace root with this label")
 }
 
 // listResponse is the stable JSON schema for irl list --json
@@ -45,13 +59,17 @@ type listResponse struct {
 }
 
 func runList(cmd *cobra.Command, args []string) error {
-	baseDir := listDirFlag
-	if baseDir != "" {
-		baseDir = expandPath(baseDir)
+	if listStatusFlag != "" && !projects.ValidStatus(listStatusFlag) {
+		return fmt.Errorf("unknown status %q (use %s)", listStatusFlag, strings.Join(projects.Statuses, ", "))
+	}
+
+	var roots []config.Workspace
+	if listDirFlag != "" {
+		roots = []config.Workspace{{Path: expandPath(listDirFlag)}}
 	} else {
-		baseDir = config.GetDefaultDirectory()
+		roots = config.GetWorkspaces()
 	}
-	if baseDir == "" {
+	if len(roots) == 0 {
 		if listJSONFlag {
 			// Return empty result with exit 0 for agents
 			data, _ := json.MarshalIndent(listResponse{Projects: []projects.Project{}}, "", "  ")
@@ -61,15 +79,31 @@ func runList(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("no default directory configured (run 'irl config --dir ~/path' to set one)")
 	}
 
-	list, err := projects.ScanDir(baseDir)
+	list, err := projects.ScanRoots(roots)
 	if err != nil {
-		if listJSONFlag {
-			data, _ := json.MarshalIndent(listResponse{Projects: []projects.Project{}}, "", "  ")
-			fmt.Println(string(data))
-			return nil
+		if len(list) == 0 && len(roots) == 1 {
+			if listJSONFlag {
+				data, _ := json.MarshalIndent(listResponse{Projects: []projects.Project{}}, "", "  ")
+				fmt.Println(string(data))
+				return nil
+			}
+			return fmt.Errorf("failed to scan projects: %w", err)
+		}
+		if !listJSONFlag {
+			// Unreachable roots (e.g. an unmounted drive) don't hide the others
+			fmt.Println(theme.Note(fmt.Sprintf("skipped: %v", err)))
+		}
+	}
+	if listRootFlag != "" {
+		var inRoot []projects.Project
+		for _, p := range list {
+			if p.Root == listRootFlag {
+				inRoot = append(inRoot, p)
+			}
 		}
-		return fmt.Errorf("failed to scan projects: %w", err)
+		list = inRoot
 	}
+	list = projects.Filter(list, listStatusFlag, listTagFlags)
 
 	if listJSONFlag {
 		resp := listResponse{Projects: list}
@@ -84,22 +118,39 @@ func runList(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
+	where := roots[0].Path
+	if len(roots) > 1 {
+		where = fmt.Sprintf("%d workspace roots", len(roots))
+	}
+
 	if len(list) == 0 {
-		fmt.Println(theme.Faint("No projects found in " + baseDir))
+		if listStatusFlag != "" || len(listTagFlags) > 0 || listRootFlag != "" {
+			fmt.Println(theme.Faint("No matching projects in " + where))
+		} else {
+			fmt.Println(theme.Faint("No projects found in " + where))
+		}
 		return nil
 	}
 
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	fmt.Fprintf(w, "%s\t%s\t%s\n",
-		theme.Faint("NAME"), theme.Faint("MODIFIED"), theme.Faint("PATH"))
-
-	for _, p := range list {
-		fmt.Fprintf(w, "%s\t%s\t%s\n",
-			p.Name, smartDate(p.Modified), theme.Faint(p.Path))
+	if len(roots) > 1 {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			theme.Faint("NAME"), theme.Faint("ROOT"), theme.Faint("STATUS"), theme.Faint("MODIFIED"), theme.Faint("TAGS"), theme.Faint("PATH"))
+		for _, p := range list {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+				p.Name, p.Root, p.Status, smartDate(p.Modified), strings.Join(p.Tags, ","), theme.Faint(p.Path))
+		}
+	} else {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			theme.Faint("NAME"), theme.Faint("STATUS"), theme.Faint("MODIFIED"), theme.Faint("TAGS"), theme.Faint("PATH"))
+		for _, p := range list {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+				p.Name, p.Status, smartDate(p.Modified), strings.Join(p.Tags, ","), theme.Faint(p.Path))
+		}
 	}
 	w.Flush()
 
-	fmt.Printf("\n%s %d projects in %s\n", theme.Faint("Total:"), len(list), baseDir)
+	fmt.Printf("\n%s %d projects in %s\n", theme.Faint("Total:"), len(list), where)
 
 	return nil
 }
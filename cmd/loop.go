@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/drpedapati/irl-template/pkg/gitutil"
+	"github.com/drpedapati/irl-template/pkg/loop"
+	"github.com/drpedapati/irl-template/pkg/theme"
+	"github.com/spf13/cobra"
+)
+
+var (
+	loopMessageFlag string
+	loopFilesFlag   []string
+)
+
+var loopCmd = &cobra.Command{
+	Use:   "loop",
+	Short: "Track loop iterations",
+	Long: `Enforce the Before/After Each Loop checklists from the plan.
+
+'start' checks that the git tree is clean and records a baseline commit.
+'finish' commits the loop's changes, appends a row to plans/main-plan-log.csv
+and a line to plans/main-plan-activity.md with the commit hash, then commits
+the logs. Only the intended files are committed: those passed with --files,
+or everything changed since the baseline.
+
+Examples:
+  irl loop start                           # Begin a loop in the current project
+  irl loop status                          # Show the running loop
+  irl loop finish -m "Cleaned raw data"    # Commit and log
+  irl loop finish -m "..." --files 03-outputs/fig1.png,plans/main-plan.md
+  irl loop abort                           # Forget the running loop`,
+}
+
+var loopStartCmd = &cobra.Command{
+	Use:   "start [project]",
+	Short: "Verify a clean tree and record a baseline commit",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runLoopStart,
+}
+
+var loopFinishCmd = &cobra.Command{
+	Use:   "finish [project]",
+	Short: "Commit intended changes and append to the plan logs",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runLoopFinish,
+}
+
+var loopStatusCmd = &cobra.Command{
+	Use:   "status [project]",
+	Short: "Show the running loop and changes since its baseline",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runLoopStatus,
+}
+
+var loopAbortCmd = &cobra.Command{
+	Use:   "abort [project]",
+	Short: "Forget the running loop (work tree is untouched)",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runLoopAbort,
+}
+
+func init() {
+	rootCmd.AddCommand(loopCmd)
+	loopCmd.AddCommand(loopStartCmd)
+	loopCmd.AddCommand(loopFinishCmd)
+	loopCmd.AddCommand(loopStatusCmd)
+	loopCmd.AddCommand(loopAbortCmd)
+	loopFinishCmd.Flags().StringVarP(&loopMessageFlag, "message", "m", "", "One-line change summary (required)")
+	loopFinishCmd.Flags().StringSliceVar(&loopFilesFlag, "files", nil, "Files to commit (default: all changes since baseline)")
+	loopFinishCmd.MarkFlagRequired("message")
+}
+
+func runLoopStart(cmd *cobra.Command, args []string) error {
+	projectDir, err := projectFromArgs(args)
+	if err != nil {
+		return err
+	}
+
+	state, err := loop.Start(projectDir)
+	if err != nil {
+		if dirty, ok := err.(*loop.DirtyError); ok {
+			fmt.Println(theme.Fail("Uncommitted changes:"))
+			for _, f := range dirty.Files {
+				fmt.Printf("  %s\n", theme.Faint(f))
+			}
+			fmt.Printf("\n%s\n", theme.Faint("Commit or stash these changes, then run 'irl loop start' again."))
+		}
+		return err
+	}
+
+	fmt.Printf("%s Loop started at %s\n", theme.OK(""), theme.Cmd(gitutil.Short(state.Baseline)))
+	fmt.Printf("  %s %s\n", theme.Faint("Finish with:"), theme.Cmd(`irl loop finish -m "summary"`))
+	return nil
+}
+
+func runLoopFinish(cmd *cobra.Command, args []string) error {
+	projectDir, err := projectFromArgs(args)
+	if err != nil {
+		return err
+	}
+
+	res, err := loop.Finish(projectDir, loop.FinishOptions{
+		Summary: loopMessageFlag,
+		Files:   loopFilesFlag,
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(res.Files) > 0 {
+		fmt.Printf("%s Committed %d files at %s\n",
+			theme.OK(""), len(res.Files), theme.Cmd(gitutil.Short(res.WorkCommit)))
+		for _, f := range res.Files {
+			fmt.Printf("  %s\n", theme.Faint(f))
+		}
+	} else {
+		fmt.Printf("%s No changes since baseline; logged against %s\n",
+			theme.OK(""), theme.Cmd(gitutil.Short(res.WorkCommit)))
+	}
+	fmt.Printf("%s Logged in %s\n", theme.OK(""), strings.Join(res.LogFiles, ", "))
+	return nil
+}
+
+func runLoopStatus(cmd *cobra.Command, args []string) error {
+	projectDir, err := projectFromArgs(args)
+	if err != nil {
+		return err
+	}
+
+	state, err := loop.Current(projectDir)
+	if err != nil {
+		return err
+	}
+	if state == nil {
+		fmt.Println(theme.Faint("No loop in progress"))
+		return nil
+	}
+
+	changed, err := gitutil.ChangedSince(projectDir, state.Baseline)
+	if err != nil {
+		return err
+	}
+
+	theme.Section("Loop in progress")
+	fmt.Println(theme.KeyValue("Baseline:", gitutil.Short(state.Baseline)))
+	fmt.Println(theme.KeyValue("Started: ", state.Started.Format("2006-01-02 15:04")+
+		" "+theme.Faint("("+time.Since(state.Started).Round(time.Minute).String()+" ago)")))
+	fmt.Println(theme.KeyValue("Changes: ", fmt.Sprintf("%d files", len(changed))))
+	for _, f := range changed {
+		fmt.Printf("    %s\n", theme.Faint(f))
+	}
+	return nil
+}
+
+func runLoopAbort(cmd *cobra.Command, args []string) error {
+	projectDir, err := projectFromArgs(args)
+	if err != nil {
+		return err
+	}
+	if err := loop.Abort(projectDir); err != nil {
+		return err
+	}
+	fmt.Printf("%s Loop aborted\n", theme.OK(""))
+	return nil
+}
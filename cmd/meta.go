@@ -0,0 +1,185 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/drpedapati/irl-template/pkg/projects"
+	"github.com/drpedapati/irl-template/pkg/theme"
+	"github.com/spf13/cobra"
+)
+
+var (
+	metaPurposeFlag       string
+	metaStatusFlag        string
+	metaOwnerFlag         string
+	metaTagFlags          []string
+	metaUntagFlags        []string
+	metaCollaboratorFlags []string
+	metaRemoveCollabFlags []string
+	metaJSONFlag          bool
+)
+
+var metaCmd = &cobra.Command{
+	Use:   "meta [project]",
+	Short: "View or set project metadata",
+	Long: `View or set a project's metadata in .irl/project.json.
+
+irl init and irl adopt write the metadata; use this command to change the
+purpose, status, tags, owner and collaborators later. irl list filters on
+status and tags.
+
+Statuses: active, paused, archived
+
+Examples:
+  irl meta                                  # Show metadata for the current project
+  irl meta my-project --json                # JSON output
+  irl meta --status paused                  # Pause the current project
+  irl meta my-project --tag eeg --tag pilot # Add tags
+  irl meta --untag pilot                    # Remove a tag
+  irl meta --collaborator "Sam Lee"         # Add a collaborator`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runMeta,
+}
+
+func init() {
+	rootCmd.AddCommand(metaCmd)
+	metaCmd.Flags().StringVar(&metaPurposeFlag, "purpose", "", "Set purpose")
+	metaCmd.Flags().StringVar(&metaStatusFlag, "status", "", "Set status (active, paused, archived)")
+	metaCmd.Flags().StringVar(&metaOwnerFlag, "owner", "", "Set owner")
+	metaCmd.Flags().StringArrayVar(&metaTagFlags, "tag", nil, "Add a tag (repeatable)")
+	metaCmd.Flags().StringArrayVar(&metaUntagFlags, "untag", nil, "Remove a tag (repeatable)")
+	metaCmd.Flags().StringArrayVar(&metaCollaboratorFlags, "collaborator", nil, "Add a collaborator (repeatable)")
+	metaCmd.Flags().StringArrayVar(&metaRemoveCollabFlags, "remove-collaborator", nil, "Remove a collaborator (repeatable)")
+	metaCmd.Flags().BoolVar(&metaJSONFlag, "json", false, "Output as JSON")
+}
+
+func runMeta(cmd *cobra.Command, args []string) error {
+	projectDir, err := projectFromArgs(args)
+	if err != nil {
+		return err
+	}
+
+	meta, err := projects.LoadMeta(projectDir)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	exists := meta != nil
+
+	setting := cmd.Flags().Changed("purpose") || cmd.Flags().Changed("status") ||
+		cmd.Flags().Changed("owner") || len(metaTagFlags) > 0 || len(metaUntagFlags) > 0 ||
+		len(metaCollaboratorFlags) > 0 || len(metaRemoveCollabFlags) > 0
+
+	if setting {
+		if metaStatusFlag != "" && !projects.ValidStatus(metaStatusFlag) {
+			return fmt.Errorf("unknown status %q (use %s)", metaStatusFlag, strings.Join(projects.Statuses, ", "))
+		}
+		if meta == nil {
+			meta = projects.NewMeta("")
+			meta.IRLVersion = Version
+		}
+		if cmd.Flags().Changed("purpose") {
+			meta.Purpose = metaPurposeFlag
+		}
+		if cmd.Flags().Changed("status") {
+			meta.Status = metaStatusFlag
+		}
+		if cmd.Flags().Changed("owner") {
+			meta.Owner = metaOwnerFlag
+		}
+		meta.Tags = removeFold(addUnique(meta.Tags, metaTagFlags), metaUntagFlags)
+		meta.Collaborators = removeFold(addUnique(meta.Collaborators, metaCollaboratorFlags), metaRemoveCollabFlags)
+
+		if err := meta.Save(projectDir); err != nil {
+			return fmt.Errorf("failed to save metadata: %w", err)
+		}
+		if !metaJSONFlag {
+			fmt.Println(theme.OK("Metadata updated"))
+			fmt.Println()
+		}
+	}
+
+	if metaJSONFlag {
+		if meta == nil {
+			fmt.Println("{}")
+			return nil
+		}
+		data, err := json.MarshalIndent(meta, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if !exists && !setting {
+		fmt.Println(theme.Faint("No metadata for " + filepath.Base(projectDir)))
+		fmt.Println()
+		fmt.Printf("%s irl meta --purpose \"...\" --tag name\n", theme.Faint("Set some:"))
+		return nil
+	}
+
+	status := meta.Status
+	if status == "" {
+		status = projects.StatusActive
+	}
+
+	theme.Section(filepath.Base(projectDir))
+	fmt.Println()
+	if meta.Purpose != "" {
+		fmt.Println(theme.KeyValue("Purpose      ", meta.Purpose))
+	}
+	fmt.Println(theme.KeyValue("Status       ", status))
+	if len(meta.Tags) > 0 {
+		fmt.Println(theme.KeyValue("Tags         ", strings.Join(meta.Tags, ", ")))
+	}
+	if meta.Owner != "" {
+		fmt.Println(theme.KeyValue("Owner        ", meta.Owner))
+	}
+	if len(meta.Collaborators) > 0 {
+		fmt.Println(theme.KeyValue("Collaborators", strings.Join(meta.Collaborators, ", ")))
+	}
+	if meta.Template != "" {
+		fmt.Println(theme.KeyValue("Template     ", meta.Template))
+	}
+	if !meta.Created.IsZero() {
+		fmt.Println(theme.KeyValue("Created      ", meta.Created.Format("Jan 2, 2006")))
+	}
+	fmt.Println()
+
+	return nil
+}
+
+// addUnique appends the values not already in list (case-insensitive)
+func addUnique(list, values []string) []string {
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if v != "" && !containsFold(list, v) {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+// removeFold drops the values from list (case-insensitive)
+func removeFold(list, values []string) []string {
+	var out []string
+	for _, v := range list {
+		if !containsFold(values, v) {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -2,13 +2,10 @@ package cmd
 
 import (
 	"fmt"
-	"os"
 	"os/exec"
-	"path/filepath"
 	"runtime"
 
 	"github.com/drpedapati/irl-template/pkg/config"
-	"github.com/drpedapati/irl-template/pkg/projects"
 	"github.com/drpedapati/irl-template/pkg/theme"
 	"github.com/spf13/cobra"
 )
@@ -39,28 +36,9 @@ func runOpen(cmd *cobra.Command, args []string) error {
 	projectName := args[0]
 
 	// Find the project
-	baseDir := config.GetDefaultDirectory()
-	if baseDir == "" {
-		return fmt.Errorf("no default directory configured (run 'irl config --dir ~/path' to set one)")
-	}
-
-	// Try exact path first
-	projectPath := filepath.Join(baseDir, projectName)
-	if _, err := os.Stat(projectPath); os.IsNotExist(err) {
-		// Try fuzzy match from project list
-		list, scanErr := projects.ScanDir(baseDir)
-		if scanErr != nil {
-			return fmt.Errorf("project %q not found", projectName)
-		}
-		for _, p := range list {
-			if p.Name == projectName {
-				projectPath = p.Path
-				break
-			}
-		}
-		if _, err := os.Stat(projectPath); os.IsNotExist(err) {
-			return fmt.Errorf("project %q not found in %s", projectName, baseDir)
-		}
+	projectPath, err := resolveProject(projectName)
+	if err != nil {
+		return err
 	}
 
 	// Determine editor
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/drpedapati/irl-template/pkg/config"
+	"github.com/drpedapati/irl-template/pkg/projects"
+	"github.com/drpedapati/irl-template/pkg/theme"
+	"github.com/spf13/cobra"
+)
+
+var reindexCmd = &cobra.Command{
+	Use:   "reindex",
+	Short: "Rebuild the workspace index",
+	Long: `Rebuild the workspace index from the filesystem.
+
+irl list, irl open and the TUI read projects from an index in
+~/.irl/index.json and only re-read folders whose modification time changed.
+Run reindex if the list looks out of date, for example after restoring
+files with their original timestamps.
+
+Examples:
+  irl reindex`,
+	Args: cobra.NoArgs,
+	RunE: runReindex,
+}
+
+func init() {
+	rootCmd.AddCommand(reindexCmd)
+}
+
+func runReindex(cmd *cobra.Command, args []string) error {
+	roots := config.GetWorkspaces()
+	if len(roots) == 0 {
+		return fmt.Errorf("no default directory configured (run 'irl config --dir ~/path' to set one)")
+	}
+
+	start := time.Now()
+	list, err := projects.Reindex(roots)
+	if err != nil {
+		if len(list) == 0 {
+			return fmt.Errorf("failed to rebuild index: %w", err)
+		}
+		fmt.Println(theme.Note(fmt.Sprintf("skipped: %v", err)))
+	}
+
+	fmt.Printf("%s Indexed %d projects in %d workspace roots %s\n",
+		theme.OK(""),
+		len(list),
+		len(roots),
+		theme.Faint(fmt.Sprintf("(%s)", time.Since(start).Round(time.Millisecond))))
+	fmt.Printf("  %s\n", theme.Faint(projects.IndexPath()))
+	return nil
+}
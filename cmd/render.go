@@ -0,0 +1,190 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/drpedapati/irl-template/pkg/render"
+	"github.com/drpedapati/irl-template/pkg/theme"
+	"github.com/spf13/cobra"
+)
+
+var (
+	renderForceFlag    bool
+	renderFileFlags    []string
+	renderRendererFlag string
+	renderJSONFlag     bool
+)
+
+var renderCmd = &cobra.Command{
+	Use:   "render [project]",
+	Short: "Render Quarto, R Markdown and Markdown outputs",
+	Long: `Render the .qmd, .Rmd and .md sources in 03-outputs.
+
+Each source is rendered with the configured renderer, run in the source's
+folder. The source's SHA-256 and the files the render wrote are recorded
+in .irl/render.json, and a source is rendered again only when it changed,
+the renderer changed, or one of its artifacts was changed or deleted.
+Files and folders starting with _ or . are skipped, as Quarto does.
+
+The renderer defaults to "quarto render {input}". Set another with
+  irl config --renderer "pandoc {input} -s -o {dir}/{name}.html"
+Placeholders: {input} (source path), {name} (source name without its
+extension), {dir} (source folder), {project} (project path).
+
+Use --force after changing something a source reads, such as its data.
+
+Examples:
+  irl render                           # Render what changed in the current project
+  irl render my-project --force        # Render everything again
+  irl render --file 03-outputs/report.qmd
+  irl render --json                    # Sources, statuses and artifacts for CI`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runRender,
+}
+
+func init() {
+	rootCmd.AddCommand(renderCmd)
+	renderCmd.Flags().BoolVar(&renderForceFlag, "force", false, "Render sources even when unchanged")
+	renderCmd.Flags().StringArrayVar(&renderFileFlags, "file", nil, "Only render this source (repeatable)")
+	renderCmd.Flags().StringVar(&renderRendererFlag, "renderer", "", "Renderer command template for this run")
+	renderCmd.Flags().BoolVar(&renderJSONFlag, "json", false, "Output as JSON")
+}
+
+// renderResponse is the JSON schema for irl render --json
+type renderResponse struct {
+	Project  string          `json:"project"`
+	Renderer string          `json:"renderer"`
+	Rendered int             `json:"rendered"`
+	Failed   int             `json:"failed"`
+	Sources  []render.Result `json:"sources"`
+}
+
+func runRender(cmd *cobra.Command, args []string) error {
+	projectDir, err := projectFromArgs(args)
+	if err != nil {
+		return err
+	}
+	projectDir, err = filepath.Abs(projectDir)
+	if err != nil {
+		return err
+	}
+	sources, err := renderSources(projectDir, renderFileFlags)
+	if err != nil {
+		return err
+	}
+
+	tmpl := renderRendererFlag
+	if tmpl == "" {
+		tmpl = render.Command()
+	}
+	opts := render.Options{Command: tmpl, Sources: sources, Force: renderForceFlag}
+	if !renderJSONFlag {
+		opts.Progress = func(source, reason string) {
+			fmt.Printf("%s %s %s\n", theme.Faint(theme.Arrow), source, theme.Faint("("+reason+")"))
+		}
+	}
+	results, err := render.Render(projectDir, opts)
+	if err != nil {
+		return err
+	}
+
+	resp := renderResponse{Project: filepath.Base(projectDir), Renderer: tmpl, Sources: results}
+	for _, r := range results {
+		switch r.Status {
+		case render.StatusRendered:
+			resp.Rendered++
+		case render.StatusFailed:
+			resp.Failed++
+		}
+	}
+
+	if renderJSONFlag {
+		data, err := json.MarshalIndent(resp, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		if resp.Failed > 0 {
+			os.Exit(1)
+		}
+		return nil
+	}
+
+	if len(results) == 0 {
+		fmt.Println(theme.Note("No .qmd, .Rmd or .md sources in 03-outputs"))
+		return nil
+	}
+	fmt.Println()
+	for _, r := range results {
+		switch r.Status {
+		case render.StatusRendered:
+			fmt.Printf("  %s %s\n", theme.OK(r.Source), theme.Faint(fmt.Sprintf("%s, %s", plural(len(r.Artifacts), "artifact"), r.Duration.Round(time.Millisecond))))
+			for _, a := range r.Artifacts {
+				fmt.Printf("      %s\n", theme.Faint(a.Path))
+			}
+		case render.StatusUnchanged:
+			fmt.Printf("  %s %s %s\n", theme.Faint("="), r.Source, theme.Faint("unchanged"))
+		case render.StatusFailed:
+			fmt.Printf("  %s %s\n", theme.Fail(r.Source), theme.Warn(fmt.Sprintf("exit %d", r.ExitCode)))
+			for _, line := range tailLines(r.Output, 10) {
+				fmt.Printf("      %s\n", theme.Faint(line))
+			}
+		}
+	}
+	fmt.Println()
+	if resp.Failed > 0 {
+		fmt.Println(theme.Fail(fmt.Sprintf("%d of %d renders failed", resp.Failed, resp.Failed+resp.Rendered)))
+		os.Exit(1)
+	}
+	if resp.Rendered == 0 {
+		fmt.Println(theme.OK("Everything is up to date"))
+	} else {
+		fmt.Println(theme.OK(fmt.Sprintf("Rendered %s", plural(resp.Rendered, "source"))))
+	}
+	return nil
+}
+
+// renderSources turns --file paths, relative to the current folder or the
+// project, into project paths
+func renderSources(projectDir string, files []string) ([]string, error) {
+	var sources []string
+	for _, f := range files {
+		path := expandPath(f)
+		if _, err := os.Stat(path); err != nil {
+			path = filepath.Join(projectDir, f)
+		}
+		if _, err := os.Stat(path); err != nil {
+			return nil, fmt.Errorf("%s not found", f)
+		}
+		rel, err := filepath.Rel(projectDir, path)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return nil, fmt.Errorf("%s isn't in %s", f, filepath.Base(projectDir))
+		}
+		sources = append(sources, filepath.ToSlash(rel))
+	}
+	return sources, nil
+}
+
+func plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// tailLines returns the last n non-empty lines of s
+func tailLines(s string, n int) []string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	if len(lines) == 1 && lines[0] == "" {
+		return nil
+	}
+	return lines
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/drpedapati/irl-template/pkg/config"
+	"github.com/drpedapati/irl-template/pkg/projects"
+)
+
+// resolveProject turns a command-line project argument into a directory.
+// An empty argument means the current directory; otherwise the argument is
+// tried as a path, then as a project name in the workspace roots.
+func resolveProject(arg string) (string, error) {
+	if arg == "" {
+		return os.Getwd()
+	}
+
+	if info, err := os.Stat(expandPath(arg)); err == nil && info.IsDir() {
+		return expandPath(arg), nil
+	}
+
+	roots := config.GetWorkspaces()
+	if len(roots) == 0 {
+		return "", fmt.Errorf("project %q not found (no default directory configured)", arg)
+	}
+
+	for _, root := range roots {
+		candidate := filepath.Join(root.Path, arg)
+		if info, err := os.Stat(candidate); err == nil && info.IsDir() {
+			return candidate, nil
+		}
+	}
+
+	list, _ := projects.ScanRoots(roots)
+	for _, p := range list {
+		if p.Name == arg {
+			return p.Path, nil
+		}
+	}
+
+	if len(roots) == 1 {
+		return "", fmt.Errorf("project %q not found in %s", arg, roots[0].Path)
+	}
+	return "", fmt.Errorf("project %q not found in %d workspace roots", arg, len(roots))
+}
+
+// projectFromArgs resolves an optional [project] positional argument
+func projectFromArgs(args []string) (string, error) {
+	if len(args) > 0 {
+		return resolveProject(args[0])
+	}
+	return resolveProject("")
+}
@@ -65,19 +65,35 @@ func printRootHelp() {
 	fmt.Printf("  %s\n", theme.Succ("irl init \"your research purpose\""))
 	fmt.Println()
 	fmt.Printf("%s\n", theme.Faint("Commands:"))
-	fmt.Printf("  %s        Create a new IRL project\n", theme.Cmd("init"))
-	fmt.Printf("  %s       Adopt an existing folder as a project\n", theme.Cmd("adopt"))
-	fmt.Printf("  %s        List projects in workspace\n", theme.Cmd("list"))
-	fmt.Printf("  %s        Open a project in editor\n", theme.Cmd("open"))
+	fmt.Printf("  %s           Create a new IRL project\n", theme.Cmd("init"))
+	fmt.Printf("  %s          Adopt an existing folder as a project\n", theme.Cmd("adopt"))
+	fmt.Printf("  %s           List projects in workspace\n", theme.Cmd("list"))
+	fmt.Printf("  %s           View or set project metadata\n", theme.Cmd("meta"))
+	fmt.Printf("  %s         Summarize a project or the workspace\n", theme.Cmd("status"))
+	fmt.Printf("  %s         Search plans and logs across projects\n", theme.Cmd("search"))
+	fmt.Printf("  %s           Snapshot and verify raw data\n", theme.Cmd("data"))
+	fmt.Printf("  %s         Bundle a project for sharing or submission\n", theme.Cmd("export"))
+	fmt.Printf("  %s         Import a shared project or git repository\n", theme.Cmd("import"))
+	fmt.Printf("  %s        Archive a project into _archive/\n", theme.Cmd("archive"))
+	fmt.Printf("  %s        Restore an archived project\n", theme.Cmd("restore"))
+	fmt.Printf("  %s           Open a project in editor\n", theme.Cmd("open"))
+	fmt.Printf("  %s           Check a plan for structural problems\n", theme.Cmd("lint"))
+	fmt.Printf("  %s           Start or finish a loop iteration\n", theme.Cmd("loop"))
+	fmt.Printf("  %s            Run a loop iteration with an AI agent\n", theme.Cmd("run"))
+	fmt.Printf("  %s         Render outputs that changed\n", theme.Cmd("render"))
+	fmt.Printf("  %s         Record a decision in the decision log\n", theme.Cmd("decide"))
+	fmt.Printf("  %s      List and export logged decisions\n", theme.Cmd("decisions"))
+	fmt.Printf("  %s   Merge template updates into a plan\n", theme.Cmd("upgrade-plan"))
 	fmt.Println()
 	fmt.Printf("%s\n", theme.Faint("Info:"))
-	fmt.Printf("  %s   Manage templates (list, show, create, delete)\n", theme.Cmd("templates"))
-	fmt.Printf("  %s      Check environment setup\n", theme.Cmd("doctor"))
+	fmt.Printf("  %s      Manage templates (list, show, create, delete)\n", theme.Cmd("templates"))
+	fmt.Printf("  %s         Check environment setup\n", theme.Cmd("doctor"))
 	fmt.Println()
 	fmt.Printf("%s\n", theme.Faint("Settings:"))
-	fmt.Printf("  %s      View or set configuration\n", theme.Cmd("config"))
-	fmt.Printf("  %s     View or set your profile\n", theme.Cmd("profile"))
-	fmt.Printf("  %s      Update templates from GitHub\n", theme.Cmd("update"))
+	fmt.Printf("  %s         View or set configuration\n", theme.Cmd("config"))
+	fmt.Printf("  %s        View or set your profile\n", theme.Cmd("profile"))
+	fmt.Printf("  %s         Update templates from all sources\n", theme.Cmd("update"))
+	fmt.Printf("  %s        Rebuild the workspace index\n", theme.Cmd("reindex"))
 	fmt.Println()
 	fmt.Printf("%s for details\n", theme.Faint("irl <command> --help"))
 }
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/drpedapati/irl-template/pkg/agent"
+	"github.com/drpedapati/irl-template/pkg/theme"
+	"github.com/spf13/cobra"
+)
+
+var runAgentFlag string
+
+var runCmd = &cobra.Command{
+	Use:   "run [project]",
+	Short: "Run a loop iteration with an AI agent",
+	Long: `Run one loop iteration headlessly with an AI command-line agent.
+
+The prompt is built from the project's plan. The agent runs in the project
+directory, its output is streamed to the terminal, and a transcript with
+the prompt, exit code and duration is saved to 04-logs/runs/.
+
+Built-in agents: claude, codex, copilot. Override or add agents with
+  irl config --agent name="command {prompt}"
+Placeholders: {prompt}, {prompt_file} (path to a file holding the prompt),
+{plan} (plan path), {project} (project path). Without {prompt} or
+{prompt_file}, the prompt is sent on stdin, which suits long plans best.
+
+Examples:
+  irl run                          # Run claude in the current project
+  irl run my-project --agent codex
+  irl config --agent stub="./stub.sh {plan}"
+  irl run --agent stub`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runRun,
+}
+
+func init() {
+	rootCmd.AddCommand(runCmd)
+	runCmd.Flags().StringVarP(&runAgentFlag, "agent", "a", "claude", "Agent to run (claude, codex, copilot, or a configured name)")
+}
+
+func runRun(cmd *cobra.Command, args []string) error {
+	projectDir, err := projectFromArgs(args)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s Running %s in %s\n\n",
+		theme.Faint(theme.Arrow), theme.Cmd(runAgentFlag), filepath.Base(projectDir))
+
+	res, err := agent.Run(agent.Options{Agent: runAgentFlag, ProjectDir: projectDir})
+	if err != nil {
+		return err
+	}
+
+	rel, relErr := filepath.Rel(projectDir, res.Transcript)
+	if relErr != nil {
+		rel = res.Transcript
+	}
+
+	fmt.Println()
+	summary := fmt.Sprintf("%s finished in %s", res.Agent, res.Duration.Round(time.Second))
+	if res.ExitCode == 0 {
+		fmt.Println(theme.OK(summary))
+	} else {
+		fmt.Println(theme.Fail(fmt.Sprintf("%s (exit %d)", summary, res.ExitCode)))
+	}
+	fmt.Printf("  %s %s\n", theme.Faint("Transcript:"), rel)
+
+	if res.ExitCode != 0 {
+		os.Exit(res.ExitCode)
+	}
+	return nil
+}
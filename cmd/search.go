@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/drpedapati/irl-template/pkg/config"
+	"github.com/drpedapati/irl-template/pkg/projects"
+	"github.com/drpedapati/irl-template/pkg/search"
+	"github.com/drpedapati/irl-template/pkg/theme"
+	"github.com/spf13/cobra"
+)
+
+var (
+	searchJSONFlag    bool
+	searchInFlag      []string
+	searchLimitFlag   int
+	searchMatchesFlag int
+)
+
+var searchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Search plans, logs and metadata across projects",
+	Long: `Search every project's main-plan.md, activity and plan logs, decision
+logs and .irl/project.json.
+
+A project matches when all words of the query appear in it. Use quotes to
+search for a phrase. Projects are ranked by how often and where the words
+appear: metadata first, then the plan, decision logs and activity logs.
+
+Examples:
+  irl search ERP correlation            # Both words, anywhere in a project
+  irl search '"ERP correlation"'        # The exact phrase
+  irl search eeg --in plan,decisions    # Only plans and decision logs
+  irl search pilot --json               # JSON for agents`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runSearch,
+}
+
+func init() {
+	rootCmd.AddCommand(searchCmd)
+	searchCmd.Flags().BoolVar(&searchJSONFlag, "json", false, "Output as JSON")
+	searchCmd.Flags().StringSliceVar(&searchInFlag, "in", nil, "File kinds to search: plan, log, decisions, meta (default all)")
+	searchCmd.Flags().IntVarP(&searchLimitFlag, "limit", "l", 20, "Maximum projects to show")
+	searchCmd.Flags().IntVar(&searchMatchesFlag, "matches", 3, "Matching lines to show per project")
+}
+
+// searchResponse is the JSON schema for irl search --json
+type searchResponse struct {
+	Query   string          `json:"query"`
+	Results []search.Result `json:"results"`
+}
+
+func runSearch(cmd *cobra.Command, args []string) error {
+	query := strings.Join(args, " ")
+
+	var list []projects.Project
+	if roots := config.GetWorkspaces(); len(roots) > 0 {
+		var err error
+		list, err = projects.ScanRoots(roots)
+		if err != nil && len(list) == 0 {
+			return fmt.Errorf("failed to scan projects: %w", err)
+		}
+	}
+
+	results, err := search.Search(list, query, search.Options{Kinds: searchInFlag, MaxMatches: searchMatchesFlag})
+	if err != nil {
+		return err
+	}
+	total := len(results)
+	if searchLimitFlag > 0 && len(results) > searchLimitFlag {
+		results = results[:searchLimitFlag]
+	}
+
+	if searchJSONFlag {
+		resp := searchResponse{Query: query, Results: results}
+		if resp.Results == nil {
+			resp.Results = []search.Result{} // Ensure [] not null
+		}
+		data, err := json.MarshalIndent(resp, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(results) == 0 {
+		fmt.Println(theme.Faint(fmt.Sprintf("No projects match %q", query)))
+		return nil
+	}
+
+	highlight := func(s string) string { return theme.B(theme.Warn(s)) }
+	for i, r := range results {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("%s %s\n", theme.B(theme.Cmd(r.Project)), theme.Faint(fmt.Sprintf("(%d matches) %s", r.Total, r.Path)))
+		for _, m := range r.Matches {
+			loc := m.File
+			if m.Kind != search.KindMeta {
+				loc = fmt.Sprintf("%s:%d", m.File, m.Line)
+			}
+			fmt.Printf("  %s %s\n", theme.Faint(loc), search.Highlight(m, highlight))
+		}
+		if more := r.Total - len(r.Matches); more > 0 {
+			fmt.Printf("  %s\n", theme.Faint(fmt.Sprintf("… %d more", more)))
+		}
+	}
+
+	if total > len(results) {
+		fmt.Printf("\n%s %d of %d projects (use --limit to see more)\n", theme.Faint("Showing:"), len(results), total)
+	} else {
+		fmt.Printf("\n%s %d projects\n", theme.Faint("Found:"), total)
+	}
+	return nil
+}
@@ -0,0 +1,240 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/drpedapati/irl-template/pkg/config"
+	"github.com/drpedapati/irl-template/pkg/projects"
+	"github.com/drpedapati/irl-template/pkg/status"
+	"github.com/drpedapati/irl-template/pkg/theme"
+	"github.com/spf13/cobra"
+)
+
+var (
+	statusAllFlag  bool
+	statusJSONFlag bool
+)
+
+var statusCmd = &cobra.Command{
+	Use:   "status [project]",
+	Short: "Summarize a project at a glance",
+	Long: `Summarize a project: git cleanliness and ahead/behind counts, the last
+loop iteration from the plan log, days since the plan was edited, outputs
+produced since the last commit, the size of 02-data, and whether the plan
+has been edited since irl wrote it from its template.
+
+With --all, print one row per project in the workspace roots.
+
+Examples:
+  irl status                 # The project in the current directory
+  irl status my-project      # A project by name
+  irl status --all           # Table of all projects
+  irl status --all --json    # JSON for agents`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runStatus,
+}
+
+func init() {
+	rootCmd.AddCommand(statusCmd)
+	statusCmd.Flags().BoolVarP(&statusAllFlag, "all", "a", false, "Summarize every project in the workspace")
+	statusCmd.Flags().BoolVar(&statusJSONFlag, "json", false, "Output as JSON")
+}
+
+// statusResponse is the JSON schema for irl status --all --json
+type statusResponse struct {
+	Projects []*status.Report `json:"projects"`
+}
+
+func runStatus(cmd *cobra.Command, args []string) error {
+	if statusAllFlag {
+		if len(args) > 0 {
+			return fmt.Errorf("--all doesn't take a project")
+		}
+		return runStatusAll()
+	}
+
+	projectDir, err := projectFromArgs(args)
+	if err != nil {
+		return err
+	}
+	r, err := status.Get(projectDir)
+	if err != nil {
+		return err
+	}
+
+	if statusJSONFlag {
+		data, err := json.MarshalIndent(r, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	theme.Section(r.Project)
+	fmt.Println()
+	fmt.Println(theme.KeyValue("Git         ", gitSummary(r.Git)))
+	fmt.Println(theme.KeyValue("Last loop   ", loopSummary(r)))
+	fmt.Println(theme.KeyValue("Plan edited ", daysAgo(r.DaysIdle)+theme.Faint(r.PlanEdited.Format(" (Jan 2, 2006 15:04)"))))
+	fmt.Println(theme.KeyValue("New outputs ", outputsSummary(r.NewOutputs)))
+	fmt.Println(theme.KeyValue("Data        ", fmt.Sprintf("%s in %s", status.FormatSize(r.DataBytes), status.DataDir)))
+	if r.Template != "" {
+		fmt.Println(theme.KeyValue("Template    ", fmt.Sprintf("%s %s", r.Template, theme.Faint("("+templateSummary(r.PlanState)+")"))))
+	}
+	for _, f := range r.NewOutputs {
+		fmt.Printf("  %s\n", theme.Faint("+ "+f))
+	}
+	fmt.Println()
+	return nil
+}
+
+func runStatusAll() error {
+	roots := config.GetWorkspaces()
+	if len(roots) == 0 {
+		return fmt.Errorf("no default directory configured (run 'irl config --dir ~/path' to set one)")
+	}
+	list, err := projects.ScanRoots(roots)
+	if err != nil {
+		if len(list) == 0 {
+			return fmt.Errorf("failed to scan projects: %w", err)
+		}
+		if !statusJSONFlag {
+			fmt.Println(theme.Note(fmt.Sprintf("skipped: %v", err)))
+		}
+	}
+	reports := status.All(list)
+
+	if statusJSONFlag {
+		resp := statusResponse{Projects: reports}
+		if resp.Projects == nil {
+			resp.Projects = []*status.Report{} // Ensure [] not null
+		}
+		data, err := json.MarshalIndent(resp, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(reports) == 0 {
+		fmt.Println(theme.Faint("No projects found"))
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+		theme.Faint("NAME"), theme.Faint("GIT"), theme.Faint("SYNC"), theme.Faint("LAST LOOP"),
+		theme.Faint("PLAN EDITED"), theme.Faint("OUTPUTS"), theme.Faint("DATA"))
+	for _, r := range reports {
+		if r.Err != "" {
+			fmt.Fprintf(w, "%s\t%s\t\t\t\t\t\n", r.Project, theme.Err("error"))
+			continue
+		}
+		last := "-"
+		if r.LastLoop != nil {
+			last = r.LastLoop.Date
+		}
+		if r.LoopActive {
+			last += "*"
+		}
+		outputs := "-"
+		if n := len(r.NewOutputs); n > 0 {
+			outputs = fmt.Sprintf("%d new", n)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			r.Project, gitCell(r.Git), syncCell(r.Git), last, daysAgo(r.DaysIdle), outputs, status.FormatSize(r.DataBytes))
+	}
+	w.Flush()
+
+	fmt.Printf("\n%s %d projects %s\n", theme.Faint("Total:"), len(reports), theme.Faint("(* loop in progress)"))
+	return nil
+}
+
+func gitSummary(g status.Git) string {
+	if !g.Repo {
+		return theme.Warn("not a git repository")
+	}
+	s := theme.Succ("clean")
+	if !g.Clean {
+		s = theme.Warn(fmt.Sprintf("%d uncommitted changes", g.Changes))
+	}
+	if g.Branch != "" {
+		s += " on " + g.Branch
+	}
+	if g.Upstream == "" {
+		return s + theme.Faint(" · no upstream")
+	}
+	if g.Ahead == 0 && g.Behind == 0 {
+		return s + theme.Faint(" · up to date with "+g.Upstream)
+	}
+	return s + fmt.Sprintf(" · %d ahead, %d behind %s", g.Ahead, g.Behind, g.Upstream)
+}
+
+func gitCell(g status.Git) string {
+	switch {
+	case !g.Repo:
+		return "no repo"
+	case g.Clean:
+		return "clean"
+	default:
+		return fmt.Sprintf("%d changes", g.Changes)
+	}
+}
+
+func syncCell(g status.Git) string {
+	if g.Upstream == "" {
+		return "-"
+	}
+	return fmt.Sprintf("↑%d ↓%d", g.Ahead, g.Behind)
+}
+
+func loopSummary(r *status.Report) string {
+	s := theme.Faint("none recorded")
+	if r.LastLoop != nil {
+		s = r.LastLoop.Date + " " + r.LastLoop.Summary
+		if r.LastLoop.Hash != "" {
+			s += theme.Faint(" (" + r.LastLoop.Hash + ")")
+		}
+	}
+	if r.LoopActive {
+		s += theme.Warn(" · loop in progress")
+	}
+	return s
+}
+
+func outputsSummary(files []string) string {
+	switch len(files) {
+	case 0:
+		return theme.Faint("none since the last commit")
+	case 1:
+		return "1 file since the last commit"
+	default:
+		return fmt.Sprintf("%d files since the last commit", len(files))
+	}
+}
+
+func templateSummary(state string) string {
+	switch state {
+	case status.PlanAsWritten:
+		return "plan as irl wrote it"
+	case status.PlanChanged:
+		return "plan edited since irl wrote it"
+	default:
+		return "no record of the plan irl wrote"
+	}
+}
+
+func daysAgo(days int) string {
+	switch days {
+	case 0:
+		return "today"
+	case 1:
+		return "1 day ago"
+	default:
+		return fmt.Sprintf("%d days ago", days)
+	}
+}
@@ -23,6 +23,7 @@ Examples:
   irl templates show <name>              # Print template content
   irl templates create <name>            # Create custom template from irl-basic
   irl templates create <name> --from X   # Create custom template from existing
+  irl templates create <name> --extends X # Create template inheriting from X
   irl templates delete <name>            # Delete a custom template`,
 	RunE: runTemplatesList,
 }
@@ -36,18 +37,33 @@ var templatesListCmd = &cobra.Command{
 var templatesShowCmd = &cobra.Command{
 	Use:   "show <name>",
 	Short: "Print template content",
-	Args:  cobra.ExactArgs(1),
-	RunE:  runTemplatesShow,
+	Long: `Print a template's content. Templates that use extends or blocks are
+shown resolved; use --raw to see the template's own file.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTemplatesShow,
 }
 
-var templateCreateFromFlag string
+var (
+	templateCreateFromFlag    string
+	templateCreateExtendsFlag string
+	templateShowRawFlag       bool
+)
 
 var templatesCreateCmd = &cobra.Command{
 	Use:   "create <name>",
 	Short: "Create a custom template",
 	Long: `Create a custom template in your workspace's _templates/ folder.
 
-By default, copies from irl-basic. Use --from to copy from another template.`,
+By default, copies from irl-basic. Use --from to copy from another template.
+
+Use --extends to inherit instead of copying: the new template starts empty
+and picks up changes to its parent. Sections with the same heading as the
+parent's replace them, or add to them when they contain <!-- irl:append -->.
+List reusable blocks from _templates/_blocks/<name>.md with "blocks: a, b".
+
+Anything else you add to the template folder is copied into new projects.
+Files ending in .tmpl are rendered with template variables, and .irlignore
+lists glob patterns to skip.`,
 	Args: cobra.ExactArgs(1),
 	RunE: runTemplatesCreate,
 }
@@ -67,6 +83,10 @@ func init() {
 	templatesCmd.AddCommand(templatesDeleteCmd)
 	templatesCreateCmd.Flags().StringVar(&templateCreateFromFlag, "from", "irl-basic",
 		"Source template to copy from")
+	templatesCreateCmd.Flags().StringVar(&templateCreateExtendsFlag, "extends", "",
+		"Parent template to inherit from (instead of copying)")
+	templatesShowCmd.Flags().BoolVar(&templateShowRawFlag, "raw", false,
+		"Show a custom template without resolving extends and blocks")
 }
 
 func runTemplatesList(cmd *cobra.Command, args []string) error {
@@ -119,20 +139,20 @@ func runTemplatesList(cmd *cobra.Command, args []string) error {
 func runTemplatesShow(cmd *cobra.Command, args []string) error {
 	name := args[0]
 
-	// Try standard templates first
-	tmpl, err := templates.GetTemplate(name)
-	if err == nil {
-		fmt.Print(tmpl.Content)
-		return nil
+	if templateShowRawFlag {
+		if content, err := readCustomTemplate(name); err == nil {
+			fmt.Print(content)
+			return nil
+		}
 	}
 
-	// Try custom templates
-	content, err := readCustomTemplate(name)
+	// Custom templates take precedence and come back resolved
+	tmpl, err := templates.GetTemplate(name)
 	if err != nil {
-		return fmt.Errorf("template %q not found", name)
+		return err
 	}
 
-	fmt.Print(content)
+	fmt.Print(tmpl.Content)
 	return nil
 }
 
@@ -146,9 +166,13 @@ func runTemplatesCreate(cmd *cobra.Command, args []string) error {
 
 	// Get source template content
 	var content string
-	// Try custom templates first
-	customContent, err := readCustomTemplate(templateCreateFromFlag)
-	if err == nil {
+	if templateCreateExtendsFlag != "" {
+		if _, err := templates.GetTemplate(templateCreateExtendsFlag); err != nil {
+			return fmt.Errorf("parent template: %w", err)
+		}
+		content = fmt.Sprintf("---\n%s: %s\n---\n\n# %s\n", templates.KeyExtends, templateCreateExtendsFlag, name)
+	} else if customContent, err := readCustomTemplate(templateCreateFromFlag); err == nil {
+		// Custom templates are copied as written
 		content = customContent
 	} else {
 		// Try standard templates
@@ -268,6 +292,16 @@ func readCustomTemplate(name string) (string, error) {
 	return string(content), nil
 }
 
+// loadTemplateContent returns a template's content, preferring custom
+// templates in the workspace over standard ones
+func loadTemplateContent(name string) (string, error) {
+	tmpl, err := templates.GetTemplate(name)
+	if err != nil {
+		return "", err
+	}
+	return tmpl.Content, nil
+}
+
 func extractDescriptionCLI(content string) string {
 	lines := strings.Split(content, "\n")
 	for _, line := range lines {
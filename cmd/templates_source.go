@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/drpedapati/irl-template/pkg/config"
+	"github.com/drpedapati/irl-template/pkg/naming"
+	"github.com/drpedapati/irl-template/pkg/templates"
+	"github.com/drpedapati/irl-template/pkg/theme"
+	"github.com/spf13/cobra"
+)
+
+var (
+	sourceTypeFlag string
+	sourceRefFlag  string
+	sourcePathFlag string
+)
+
+var templatesSourceCmd = &cobra.Command{
+	Use:   "source",
+	Short: "Manage template sources",
+	Long: `Add, remove, or list the catalogs templates are fetched from.
+
+Sources are searched in the order listed, then the default GitHub catalog.
+When two sources offer a template with the same name, the earlier one wins.
+Custom templates in your workspace's _templates/ folder override them all.
+
+Source types:
+  dir     A local folder of <name>.md files or <name>/main-plan.md folders
+  git     A git repository at a branch, tag, or commit (--ref)
+  http    A static JSON index: {"templates": [{"name", "description", "url"}]}
+  github  A folder of a GitHub repository (owner/repo)
+
+Examples:
+  irl templates source add lab ~/lab-templates
+  irl templates source add lab git@github.com:lab/templates.git --ref v2 --path plans
+  irl templates source add lab https://lab.example.org/irl/index.json
+  irl templates source remove lab
+  irl templates source list`,
+	RunE: runTemplatesSourceList,
+}
+
+var templatesSourceAddCmd = &cobra.Command{
+	Use:   "add <name> <location>",
+	Short: "Add a template source",
+	Long: `Add a template source. The type is detected from the location unless
+--type is given. Names use lowercase letters, digits and hyphens. Adding a
+source with an existing name replaces it.`,
+	Args: cobra.ExactArgs(2),
+	RunE: runTemplatesSourceAdd,
+}
+
+var templatesSourceRemoveCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: "Remove a template source",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runTemplatesSourceRemove,
+}
+
+var templatesSourceListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List template sources in precedence order",
+	RunE:  runTemplatesSourceList,
+}
+
+func init() {
+	templatesCmd.AddCommand(templatesSourceCmd)
+	templatesSourceCmd.AddCommand(templatesSourceAddCmd)
+	templatesSourceCmd.AddCommand(templatesSourceRemoveCmd)
+	templatesSourceCmd.AddCommand(templatesSourceListCmd)
+	templatesSourceAddCmd.Flags().StringVar(&sourceTypeFlag, "type", "",
+		"Source type: dir, git, http, or github (detected by default)")
+	templatesSourceAddCmd.Flags().StringVar(&sourceRefFlag, "ref", "",
+		"Branch, tag, or commit (git and github sources)")
+	templatesSourceAddCmd.Flags().StringVar(&sourcePathFlag, "path", "",
+		"Folder inside the source that holds the templates")
+}
+
+func runTemplatesSourceAdd(cmd *cobra.Command, args []string) error {
+	name, location := args[0], args[1]
+	if name == templates.DefaultSourceName {
+		return fmt.Errorf("%q is reserved for the built-in catalog", name)
+	}
+	if err := templates.ValidateSourceName(name); err != nil {
+		if slug := naming.Slugify(name); slug != "" {
+			return fmt.Errorf("%w (try %q)", err, slug)
+		}
+		return err
+	}
+
+	// URLs and owner/repo aren't paths, so only ~ is expanded before
+	// detecting the type
+	local := location
+	if strings.HasPrefix(location, "~") {
+		local = expandPath(location)
+	}
+	srcType := sourceTypeFlag
+	if srcType == "" {
+		srcType = templates.DetectSourceType(local)
+	}
+	// Folders and local repositories are saved as absolute paths, since
+	// git sources are fetched from elsewhere
+	_, statErr := os.Stat(local)
+	if srcType == templates.SourceDir || (srcType == templates.SourceGit && statErr == nil) {
+		abs, err := filepath.Abs(local)
+		if err != nil {
+			return err
+		}
+		location = abs
+	}
+
+	src := config.TemplateSource{
+		Name:     name,
+		Type:     srcType,
+		Location: location,
+		Ref:      sourceRefFlag,
+		Path:     sourcePathFlag,
+	}
+
+	// Make sure the source works before saving it
+	source, err := templates.NewSource(src)
+	if err != nil {
+		return err
+	}
+	list, err := source.List()
+	if err != nil {
+		return err
+	}
+
+	if err := config.AddTemplateSource(src); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	fmt.Printf("%s Added %s source %s %s\n",
+		theme.OK(""),
+		srcType,
+		theme.Cmd(name),
+		theme.Faint(fmt.Sprintf("(%d templates)", len(list))))
+	fmt.Printf("  %s\n", theme.Faint("Run 'irl update' to refresh the template list"))
+	return nil
+}
+
+func runTemplatesSourceRemove(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	removed, err := config.RemoveTemplateSource(name)
+	if err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	if !removed {
+		return fmt.Errorf("template source %q not found", name)
+	}
+
+	fmt.Printf("%s Removed source %s\n", theme.OK(""), theme.Cmd(name))
+	fmt.Printf("  %s\n", theme.Faint("Run 'irl update' to refresh the template list"))
+	return nil
+}
+
+func runTemplatesSourceList(cmd *cobra.Command, args []string) error {
+	fmt.Println(theme.B("Template sources") + theme.Faint(" (highest precedence first)"))
+	fmt.Println()
+
+	for i, src := range config.GetTemplateSources() {
+		detail := src.Location
+		if src.Ref != "" {
+			detail += "@" + src.Ref
+		}
+		if src.Path != "" {
+			detail += " " + src.Path + "/"
+		}
+		fmt.Printf("  %d. %s %s %s\n", i+1, theme.B(src.Name), theme.Faint("["+src.Type+"]"), detail)
+	}
+
+	n := len(config.GetTemplateSources()) + 1
+	fmt.Printf("  %d. %s %s %s\n", n, theme.B(templates.DefaultSourceName),
+		theme.Faint("["+templates.SourceGitHub+"]"), templates.GitHubRepo+" "+templates.TemplatesPath+"/")
+	return nil
+}
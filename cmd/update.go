@@ -15,9 +15,10 @@ import (
 
 var updateCmd = &cobra.Command{
 	Use:   "update",
-	Short: "Update templates from GitHub",
-	Long:  "Fetch the latest templates from the IRL template repository",
-	RunE:  runUpdate,
+	Short: "Update templates from their sources",
+	Long: `Fetch the latest templates from the configured template sources and the
+IRL template repository (see 'irl templates source').`,
+	RunE: runUpdate,
 }
 
 func init() {
@@ -95,6 +96,7 @@ func runUpdate(cmd *cobra.Command, args []string) error {
 
 		list, _ := templates.ListTemplates()
 		printUpdateSuccess(list)
+		printResolvedTemplates()
 		return nil
 	}
 
@@ -129,6 +131,7 @@ func runUpdate(cmd *cobra.Command, args []string) error {
 	}
 
 	printUpdateSuccess(final.list)
+	printResolvedTemplates()
 	return nil
 }
 
@@ -145,3 +148,24 @@ func printUpdateSuccess(list []templates.Template) {
 			theme.Faint(t.Description))
 	}
 }
+
+// printResolvedTemplates re-resolves custom templates that extend others
+// or use blocks, so they pick up the refreshed parents
+func printResolvedTemplates() {
+	var printed bool
+	for _, ct := range loadCustomTemplatesCLI() {
+		content, err := readCustomTemplate(ct.name)
+		if err != nil || !templates.Inherits(content) {
+			continue
+		}
+		if !printed {
+			fmt.Printf("\n%s\n", theme.B("Inherited templates:"))
+			printed = true
+		}
+		if _, err := templates.GetCustom(ct.name); err != nil {
+			fmt.Printf("  %s %s\n", theme.Fail(theme.B(ct.name)), theme.Faint(err.Error()))
+			continue
+		}
+		fmt.Printf("  %s %s\n", theme.OK(theme.B(ct.name)), theme.Faint("resolved"))
+	}
+}
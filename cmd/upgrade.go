@@ -0,0 +1,189 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/drpedapati/irl-template/pkg/plan"
+	"github.com/drpedapati/irl-template/pkg/projects"
+	"github.com/drpedapati/irl-template/pkg/templates"
+	"github.com/drpedapati/irl-template/pkg/theme"
+	"github.com/spf13/cobra"
+)
+
+var (
+	upgradeDryRunFlag   bool
+	upgradeTemplateFlag string
+	upgradeVarFlags     []string
+)
+
+var upgradePlanCmd = &cobra.Command{
+	Use:   "upgrade-plan [project]",
+	Short: "Merge template updates into a project's plan",
+	Long: `Merge changes from the template a plan was created from into the plan.
+
+irl init records the template in .irl/project.json and keeps the rendered
+template in .irl/template.md. upgrade-plan compares that with the current
+template and applies the differences section by section:
+
+  - template text that you haven't edited is updated
+  - content in AUTHOR AREAs is always kept
+  - sections new in the template are added
+  - sections dropped from the template are removed if you didn't use them
+
+Sections edited both in the plan and in the template are conflicts: both
+versions are written between <<<<<<< plan and >>>>>>> template markers and
+the command exits with status 1.
+
+Projects without a lock need --template to name their template; the current
+version is then treated as the one the plan was created from.
+
+Examples:
+  irl upgrade-plan                  # Upgrade the project in the current directory
+  irl upgrade-plan my-project -n    # Show what would change
+  irl upgrade-plan -t irl-basic     # Start tracking a project without a lock`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runUpgradePlan,
+}
+
+func init() {
+	rootCmd.AddCommand(upgradePlanCmd)
+	upgradePlanCmd.Flags().BoolVarP(&upgradeDryRunFlag, "dry-run", "n", false, "Show what would change without writing")
+	upgradePlanCmd.Flags().StringVarP(&upgradeTemplateFlag, "template", "t", "", "Template to upgrade from (overrides the lock)")
+	upgradePlanCmd.Flags().StringArrayVar(&upgradeVarFlags, "var", nil, "Value for a template variable as name=value (repeatable)")
+}
+
+func runUpgradePlan(cmd *cobra.Command, args []string) error {
+	projectDir, err := projectFromArgs(args)
+	if err != nil {
+		return err
+	}
+	planPath, err := plan.Find(projectDir)
+	if err != nil {
+		return err
+	}
+
+	meta, err := projects.LoadMeta(projectDir)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if meta == nil {
+		if upgradeTemplateFlag == "" {
+			return fmt.Errorf("%s has no %s; pass --template to name its template",
+				filepath.Base(projectDir), filepath.Join(projects.MetaDir, "project.json"))
+		}
+		meta = &projects.Meta{}
+	}
+	name := meta.Template
+	if upgradeTemplateFlag != "" {
+		name = upgradeTemplateFlag
+	}
+
+	tmpl, err := templates.GetTemplate(name)
+	if err != nil {
+		return err
+	}
+
+	// Render the template with the values the plan was created with
+	values, err := templates.ParseVarFlags(upgradeVarFlags)
+	if err != nil {
+		return err
+	}
+	for k, v := range meta.Vars {
+		if _, ok := values[k]; !ok {
+			values[k] = v
+		}
+	}
+	projectName := filepath.Base(projectDir)
+	purpose := ""
+	if v, ok := meta.Vars["project.purpose"]; ok {
+		purpose = v
+	}
+	theirs, err := templates.Apply(tmpl.Content, projectName, purpose, values)
+	if err != nil {
+		return err
+	}
+
+	base, err := projects.ReadBase(projectDir)
+	if err != nil {
+		if meta.Template != "" && !os.IsNotExist(err) {
+			return err
+		}
+		base = theirs // no ancestor: assume the plan started from this version
+	}
+
+	data, err := os.ReadFile(planPath)
+	if err != nil {
+		return err
+	}
+	result := plan.Merge(base, string(data), theirs)
+
+	hash := projects.HashContent(tmpl.Content)
+	relPlan, _ := filepath.Rel(projectDir, planPath)
+	printUpgradeResult(name, meta.Hash == hash && meta.Template == name, result)
+
+	if upgradeDryRunFlag {
+		if result.Changed() {
+			fmt.Printf("\n%s\n", theme.Faint("Dry run: nothing written"))
+		}
+		return nil
+	}
+
+	if result.Changed() {
+		if err := os.WriteFile(planPath, []byte(result.Content), 0644); err != nil {
+			return fmt.Errorf("failed to write plan: %w", err)
+		}
+	}
+
+	now := time.Now()
+	meta.Template = name
+	meta.Source = tmpl.Source
+	meta.Hash = hash
+	meta.Vars = templates.Values(tmpl.Content, projectName, purpose, values)
+	meta.IRLVersion = Version
+	meta.Upgraded = &now
+	// A plan nobody had edited is still as irl wrote it
+	if meta.PlanHash == projects.HashPlan(string(data)) && len(result.Conflicts) == 0 {
+		meta.PlanHash = projects.HashPlan(result.Content)
+	}
+	if err := projects.Lock(projectDir, meta, theirs); err != nil {
+		return err
+	}
+
+	if len(result.Conflicts) > 0 {
+		fmt.Printf("\n%s\n", theme.Warn(fmt.Sprintf("Resolve the conflict markers in %s, then commit", relPlan)))
+		os.Exit(1)
+	}
+	if result.Changed() {
+		fmt.Printf("\n%s %s\n", theme.OK("Upgraded"), theme.Faint(relPlan))
+	}
+	return nil
+}
+
+func printUpgradeResult(name string, sameVersion bool, r *plan.MergeResult) {
+	fmt.Printf("%s %s\n", theme.B("Template:"), name)
+	if !r.Changed() {
+		if sameVersion {
+			fmt.Println(theme.OK("Plan is up to date with its template"))
+		} else {
+			fmt.Println(theme.OK("Template changes don't affect the plan"))
+		}
+		return
+	}
+
+	fmt.Println()
+	for _, s := range r.Updated {
+		fmt.Printf("  %s %s\n", theme.Succ("~"), s)
+	}
+	for _, s := range r.Added {
+		fmt.Printf("  %s %s\n", theme.Succ("+"), s)
+	}
+	for _, s := range r.Removed {
+		fmt.Printf("  %s %s\n", theme.Warn("-"), s)
+	}
+	for _, c := range r.Conflicts {
+		fmt.Printf("  %s %s %s\n", theme.Err("!"), c.Section, theme.Faint("("+c.Reason+")"))
+	}
+}
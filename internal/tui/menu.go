@@ -33,6 +33,7 @@ const (
 	ViewUpdate      // Updates templates from GitHub
 	ViewHelp        // Help/tutorial view
 	ViewAdopt       // Adopt existing folder
+	ViewSearch      // Search plans and logs across projects
 )
 
 // Menu represents the main menu
@@ -50,6 +51,7 @@ func NewMenu() Menu {
 			{Title: "New project", Desc: "Create a new IRL project", Key: "n", ViewType: ViewInit},
 			{Title: "Adopt folder", Desc: "Bring an existing folder into IRL", Key: "a", ViewType: ViewAdopt},
 			{Title: "Projects", Desc: "Browse existing IRL projects", Key: "p", ViewType: ViewProjects},
+			{Title: "Search", Desc: "Search plans and logs across projects", Key: "s", ViewType: ViewSearch},
 			{Title: "Folder", Desc: "Set default project folder", Key: "f", ViewType: ViewFolder},
 			{Title: "Templates", Desc: "Browse and manage templates", Key: "t", ViewType: ViewTemplates, SeparatorAfter: true},
 			{Title: "Docs", Desc: "Open documentation in browser", Key: "o", ViewType: ViewDocs},
@@ -132,6 +132,8 @@ func ProjectsViewKeys() []KeyBinding {
 		{Key: "↑↓", Desc: "Navigate"},
 		{Key: "e", Desc: "Edit"},
 		{Key: "b", Desc: "Backup"},
+		{Key: "a", Desc: "Archive"},
+		{Key: "R", Desc: "Restore"},
 		{Key: "x", Desc: "Delete"},
 		{Key: "←", Desc: "Back"},
 	}
@@ -146,3 +148,12 @@ func ProjectsFilterKeys() []KeyBinding {
 		{Key: "←", Desc: "Back"},
 	}
 }
+
+// SearchViewKeys returns key bindings for the search view
+func SearchViewKeys() []KeyBinding {
+	return []KeyBinding{
+		{Key: "Enter", Desc: "Search"},
+		{Key: "Esc", Desc: "Clear"},
+		{Key: "←", Desc: "Back"},
+	}
+}
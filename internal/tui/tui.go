@@ -55,6 +55,7 @@ type Model struct {
 	editorsView     views.EditorsModel
 	helpView        views.HelpModel
 	adoptView       views.AdoptModel
+	searchView      views.SearchModel
 
 	// Loading state
 	loading bool
@@ -97,10 +98,11 @@ func New(version string) Model {
 		folderView:         views.NewFolderModel(),
 		personalizeView:    views.NewPersonalizeModel(),
 		doctorView:         views.NewDoctorModel(),
-		initView:           views.NewInitModel(),
+		initView:           views.NewInitModel(version),
 		configView:         views.NewConfigModel(),
 		helpView:           views.NewHelpModel(),
-		adoptView:          views.NewAdoptModel(),
+		adoptView:          views.NewAdoptModel(version),
+		searchView:         views.NewSearchModel(),
 		spinner:            s,
 		checkingForUpdates: true, // Will check on init
 	}
@@ -118,6 +120,7 @@ func New(version string) Model {
 	m.configView.SetSize(appWidth, appHeight-7)
 	m.helpView.SetSize(appWidth-2, appHeight-7) // -2 for left margin
 	m.adoptView.SetSize(appWidth, appHeight-7)
+	m.searchView.SetSize(appWidth, appHeight-7)
 
 	return m
 }
@@ -228,6 +231,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m.updateHelp(msg)
 		case ViewAdopt:
 			return m.updateAdopt(msg)
+		case ViewSearch:
+			return m.updateSearch(msg)
 		}
 
 	case spinner.TickMsg:
@@ -323,9 +328,17 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case views.AdoptProjectMsg:
 		m.adoptView, _ = m.adoptView.Update(msg)
 
+	case views.AdoptProgressMsg:
+		var cmd tea.Cmd
+		m.adoptView, cmd = m.adoptView.Update(msg)
+		cmds = append(cmds, cmd)
+
 	case views.AdoptTemplatesLoadedMsg:
 		m.adoptView, _ = m.adoptView.Update(msg)
 
+	case views.SearchResultsMsg:
+		m.searchView, _ = m.searchView.Update(msg)
+
 	case views.BackToMenuMsg:
 		m.view = ViewMenu
 		m.statusBar.SetKeys(m.getMenuKeys())
@@ -333,8 +346,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case views.HelpLoadedMsg:
 		m.helpView, _ = m.helpView.Update(msg)
 
-	case editor.EditorFinishedMsg, editor.EditorOpenedMsg:
-		// Pass editor messages to the appropriate view
+	case editor.EditorFinishedMsg, editor.EditorOpenedMsg, views.ProjectStatusMsg:
+		// Pass editor and project status messages to the appropriate view
 		if m.view == ViewProjects {
 			var cmd tea.Cmd
 			m.projectsView, cmd = m.projectsView.Update(msg)
@@ -355,6 +368,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.adoptView, cmd = m.adoptView.Update(msg)
 			return m, cmd
 		}
+		if m.view == ViewSearch {
+			var cmd tea.Cmd
+			m.searchView, cmd = m.searchView.Update(msg)
+			return m, cmd
+		}
 
 	}
 
@@ -414,6 +432,10 @@ func (m Model) updateMenu(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		if v, ok := m.menu.SelectByKey("p"); ok {
 			return m.selectView(v)
 		}
+	case "s":
+		if v, ok := m.menu.SelectByKey("s"); ok {
+			return m.selectView(v)
+		}
 	case "f":
 		if v, ok := m.menu.SelectByKey("f"); ok {
 			return m.selectView(v)
@@ -461,7 +483,7 @@ func (m Model) selectView(v ViewType) (tea.Model, tea.Cmd) {
 	case ViewInit:
 		m.view = v
 		m.statusBar.SetKeys(InitViewKeys())
-		m.initView = views.NewInitModel()
+		m.initView = views.NewInitModel(m.version)
 		m.initView.SetSize(appWidth, appHeight-7)
 		cmd = m.initView.Init()
 	case ViewConfig:
@@ -487,8 +509,14 @@ func (m Model) selectView(v ViewType) (tea.Model, tea.Cmd) {
 	case ViewAdopt:
 		m.view = v
 		m.statusBar.SetKeys(InitViewKeys())
-		m.adoptView = views.NewAdoptModel()
+		m.adoptView = views.NewAdoptModel(m.version)
 		m.adoptView.SetSize(appWidth, appHeight-7)
+	case ViewSearch:
+		m.view = v
+		m.statusBar.SetKeys(SearchViewKeys())
+		m.searchView = views.NewSearchModel()
+		m.searchView.SetSize(appWidth, appHeight-7)
+		cmd = m.searchView.Init()
 	default:
 		m.view = v
 	}
@@ -537,8 +565,9 @@ func openInEditor(path string) {
 }
 
 func (m Model) updateProjects(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	// If viewing project details or confirming delete, let the view handle all keys
-	if m.projectsView.IsViewing() || m.projectsView.IsConfirmingDelete() {
+	// If viewing project details, archives or a confirmation, let the view handle all keys
+	if m.projectsView.IsViewing() || m.projectsView.IsConfirmingDelete() ||
+		m.projectsView.IsConfirmingArchive() || m.projectsView.IsShowingArchives() {
 		var cmd tea.Cmd
 		m.projectsView, cmd = m.projectsView.Update(msg)
 		return m, cmd
@@ -712,6 +741,29 @@ func (m Model) updateAdopt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+func (m Model) updateSearch(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	// If viewing a project, let the view handle all keys
+	if m.searchView.IsViewing() {
+		var cmd tea.Cmd
+		m.searchView, cmd = m.searchView.Update(msg)
+		return m, cmd
+	}
+
+	// Two-stage escape: clear the query first, then go back
+	switch msg.String() {
+	case "esc", "left":
+		if m.searchView.IsInputMode() && !m.searchView.HasQuery() {
+			m.view = ViewMenu
+			m.statusBar.SetKeys(m.getMenuKeys())
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.searchView, cmd = m.searchView.Update(msg)
+	return m, cmd
+}
+
 func (m Model) updateConfig(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
 	case "q":
@@ -841,7 +893,15 @@ func (m Model) View() string {
 	viewTitle := "Main Menu"
 	switch m.view {
 	case ViewProjects:
-		viewTitle = "Projects"
+		if m.projectsView.IsViewingHistory() {
+			viewTitle = "Plan History"
+		} else if m.projectsView.IsViewingDecision() {
+			viewTitle = "Log Decision"
+		} else if m.projectsView.IsShowingArchives() {
+			viewTitle = "Archives"
+		} else {
+			viewTitle = "Projects"
+		}
 	case ViewFolder:
 		viewTitle = "Default Folder"
 	case ViewTemplates:
@@ -864,6 +924,8 @@ func (m Model) View() string {
 		viewTitle = "Help"
 	case ViewAdopt:
 		viewTitle = "Adopt Folder"
+	case ViewSearch:
+		viewTitle = "Search"
 	}
 
 	// Show view title on left, datetime on right
@@ -923,6 +985,8 @@ func (m Model) View() string {
 		content = m.helpView.View()
 	case ViewAdopt:
 		content = m.adoptView.View()
+	case ViewSearch:
+		content = m.searchView.View()
 	}
 
 	// Truncate or pad content to fixed height (top-justified)
@@ -1104,6 +1168,12 @@ func (m Model) getContextHint() string {
 	case ViewMenu:
 		return ""
 	case ViewProjects:
+		if m.projectsView.IsViewingHistory() || m.projectsView.IsViewingDecision() {
+			return "" // History view and decision form have their own hints
+		}
+		if m.projectsView.IsShowingArchives() {
+			return keyStyle.Render("Enter") + mutedStyle.Render(" restore  ") + keyStyle.Render("←") + mutedStyle.Render(" back")
+		}
 		if m.projectsView.IsFilterMode() {
 			return keyStyle.Render("↓") + mutedStyle.Render(" to select projects")
 		}
@@ -1141,6 +1211,14 @@ func (m Model) getContextHint() string {
 	case ViewHelp:
 		// Help view has its own footer with navigation hints
 		return ""
+	case ViewSearch:
+		if m.searchView.IsViewing() {
+			return keyStyle.Render("e") + mutedStyle.Render(" edit  ") + keyStyle.Render("g") + mutedStyle.Render(" history  ") + keyStyle.Render("←") + mutedStyle.Render(" back")
+		}
+		if m.searchView.IsInputMode() {
+			return keyStyle.Render("Enter") + mutedStyle.Render(" search  ") + keyStyle.Render("↓") + mutedStyle.Render(" to select results")
+		}
+		return keyStyle.Render("↑↓") + mutedStyle.Render(" navigate  ") + keyStyle.Render("Enter") + mutedStyle.Render(" open project")
 	}
 	return ""
 }
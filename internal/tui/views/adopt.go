@@ -3,7 +3,6 @@ package views
 import (
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"sort"
 	"strings"
@@ -12,9 +11,13 @@ import (
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/drpedapati/irl-template/pkg/analyze"
 	"github.com/drpedapati/irl-template/pkg/config"
 	"github.com/drpedapati/irl-template/pkg/editor"
+	"github.com/drpedapati/irl-template/pkg/fsutil"
+	"github.com/drpedapati/irl-template/pkg/projects"
 	"github.com/drpedapati/irl-template/pkg/scaffold"
+	"github.com/drpedapati/irl-template/pkg/status"
 	"github.com/drpedapati/irl-template/pkg/templates"
 	"github.com/drpedapati/irl-template/pkg/theme"
 )
@@ -24,6 +27,7 @@ type AdoptStep int
 
 const (
 	AdoptStepBrowse AdoptStep = iota
+	AdoptStepMode
 	AdoptStepTemplate
 	AdoptStepAdopting
 	AdoptStepDone
@@ -43,33 +47,54 @@ type AdoptModel struct {
 	browseScroll  int
 	browseSortBy  string // "name-asc" or "date-desc"
 
+	// Mode state
+	modeIdx int // index into projects.AdoptModes
+
 	// Template state
 	templates   []templates.Template
 	templateIdx int
 
+	// Copy progress
+	progress  fsutil.Progress
+	adoptMsgs chan tea.Msg // progress updates, then the result
+
 	// Result
 	spinner     spinner.Model
 	projectPath string
 	err         error
 	done        bool
 	actionView  ProjectActionModel
+	version     string // irl version recorded in the project lock
 }
 
 const adoptBrowseVisibleItems = 8
 
+// adoptModeHints describe each of projects.AdoptModes
+var adoptModeHints = map[string]string{
+	projects.AdoptCopy:    "Copy into the workspace",
+	projects.AdoptMove:    "Move into the workspace",
+	projects.AdoptInPlace: "Leave it where it is",
+	projects.AdoptSymlink: "Leave it where it is, linked from the workspace",
+}
+
 // AdoptProjectMsg is sent when the adopt operation completes
 type AdoptProjectMsg struct {
 	Path string
 	Err  error
 }
 
+// AdoptProgressMsg reports how far the folder copy has got
+type AdoptProgressMsg struct {
+	Progress fsutil.Progress
+}
+
 // AdoptTemplatesLoadedMsg is sent when templates are loaded for adopt wizard
 type AdoptTemplatesLoadedMsg struct {
 	Templates []templates.Template
 }
 
 // NewAdoptModel creates a new adopt view
-func NewAdoptModel() AdoptModel {
+func NewAdoptModel(version string) AdoptModel {
 	s := spinner.New()
 	s.Spinner = spinner.Dot
 	s.Style = lipgloss.NewStyle().Foreground(theme.Accent)
@@ -80,6 +105,7 @@ func NewAdoptModel() AdoptModel {
 		step:      AdoptStepBrowse,
 		browseDir: home,
 		spinner:   s,
+		version:   version,
 	}
 	m.loadFolders()
 	return m
@@ -98,7 +124,10 @@ func (m AdoptModel) Init() tea.Cmd {
 
 // CanGoBack returns true if we can go back within the wizard
 func (m AdoptModel) CanGoBack() bool {
-	return m.step == AdoptStepTemplate
+	if m.step == AdoptStepDone && (m.actionView.IsShowingHistory() || m.actionView.IsShowingStatus() || m.actionView.IsShowingDecision()) {
+		return true // Close the sub-view first
+	}
+	return m.step == AdoptStepMode || m.step == AdoptStepTemplate
 }
 
 // Done returns true if the wizard is complete
@@ -111,7 +140,7 @@ func (m AdoptModel) Update(msg tea.Msg) (AdoptModel, tea.Cmd) {
 	var cmds []tea.Cmd
 
 	switch msg := msg.(type) {
-	case editor.EditorFinishedMsg, editor.EditorOpenedMsg:
+	case editor.EditorFinishedMsg, editor.EditorOpenedMsg, ProjectStatusMsg:
 		if m.step == AdoptStepDone {
 			var cmd tea.Cmd
 			m.actionView, cmd = m.actionView.Update(msg)
@@ -122,6 +151,8 @@ func (m AdoptModel) Update(msg tea.Msg) (AdoptModel, tea.Cmd) {
 		switch m.step {
 		case AdoptStepBrowse:
 			return m.updateBrowse(msg)
+		case AdoptStepMode:
+			return m.updateMode(msg)
 		case AdoptStepTemplate:
 			return m.updateTemplate(msg)
 		case AdoptStepDone:
@@ -135,9 +166,14 @@ func (m AdoptModel) Update(msg tea.Msg) (AdoptModel, tea.Cmd) {
 			cmds = append(cmds, cmd)
 		}
 
+	case AdoptProgressMsg:
+		if m.step == AdoptStepAdopting {
+			m.progress = msg.Progress
+			return m, waitForAdopt(m.adoptMsgs)
+		}
+
 	case AdoptTemplatesLoadedMsg:
 		m.templates = msg.Templates
-		m.step = AdoptStepTemplate
 
 	case AdoptProjectMsg:
 		m.step = AdoptStepDone
@@ -233,11 +269,32 @@ func (m AdoptModel) updateBrowse(msg tea.KeyMsg) (AdoptModel, tea.Cmd) {
 			// If empty folder list, select current directory
 			m.sourcePath = m.browseDir
 		}
+		m.step = AdoptStepMode
+		return m, nil
+	case "esc":
+		// Let parent handle going back to menu
+		return m, nil
+	}
+	return m, nil
+}
+
+func (m AdoptModel) updateMode(msg tea.KeyMsg) (AdoptModel, tea.Cmd) {
+	switch msg.String() {
+	case "up":
+		if m.modeIdx > 0 {
+			m.modeIdx--
+		}
+	case "down":
+		if m.modeIdx < len(projects.AdoptModes)-1 {
+			m.modeIdx++
+		}
+	case "enter", "right":
 		// Load templates for next step
 		m.step = AdoptStepTemplate
 		return m, tea.Batch(m.loadTemplates(), m.spinner.Tick)
-	case "esc":
-		// Let parent handle going back to menu
+	case "esc", "left":
+		// Go back to browse
+		m.step = AdoptStepBrowse
 		return m, nil
 	}
 	return m, nil
@@ -255,10 +312,13 @@ func (m AdoptModel) updateTemplate(msg tea.KeyMsg) (AdoptModel, tea.Cmd) {
 		}
 	case "enter", "right":
 		m.step = AdoptStepAdopting
-		return m, tea.Batch(m.adoptProject(), m.spinner.Tick)
+		m.progress = fsutil.Progress{}
+		m.adoptMsgs = make(chan tea.Msg, 1)
+		go m.adoptProject(m.adoptMsgs)
+		return m, tea.Batch(waitForAdopt(m.adoptMsgs), m.spinner.Tick)
 	case "esc", "left":
-		// Go back to browse
-		m.step = AdoptStepBrowse
+		// Go back to mode
+		m.step = AdoptStepMode
 		return m, nil
 	}
 	return m, nil
@@ -278,66 +338,146 @@ func (m AdoptModel) updateDone(msg tea.KeyMsg) (AdoptModel, tea.Cmd) {
 func (m AdoptModel) loadTemplates() tea.Cmd {
 	return func() tea.Msg {
 		list, _ := templates.ListTemplates()
+		list = append(list, templates.ListCustom()...)
 		return AdoptTemplatesLoadedMsg{Templates: list}
 	}
 }
 
-func (m AdoptModel) adoptProject() tea.Cmd {
+// waitForAdopt returns the next message from a running adopt
+func waitForAdopt(ch chan tea.Msg) tea.Cmd {
 	return func() tea.Msg {
-		baseDir := config.GetDefaultDirectory()
-		if baseDir == "" {
-			return AdoptProjectMsg{Err: fmt.Errorf("no default directory configured")}
+		return <-ch
+	}
+}
+
+// adoptProject adopts the folder, sending copy progress and then the
+// result on ch. Progress the view hasn't picked up yet is dropped.
+func (m AdoptModel) adoptProject(ch chan tea.Msg) {
+	var last time.Time
+	progress := func(p fsutil.Progress) {
+		if time.Since(last) >= 100*time.Millisecond {
+			last = time.Now()
+			select {
+			case ch <- AdoptProgressMsg{Progress: p}:
+			default:
+			}
 		}
+	}
+	ch <- m.adopt(progress)
+}
 
-		folderName := filepath.Base(m.sourcePath)
-		destPath := filepath.Join(baseDir, folderName)
+// adopt brings the folder into the workspace the chosen way and adds the
+// IRL scaffolding
+func (m AdoptModel) adopt(progress func(fsutil.Progress)) tea.Msg {
+	mode := projects.AdoptModes[m.modeIdx]
+	baseDir := config.GetDefaultDirectory()
+	if baseDir == "" && mode != projects.AdoptInPlace {
+		return AdoptProjectMsg{Err: fmt.Errorf("no default directory configured")}
+	}
 
+	folderName := filepath.Base(m.sourcePath)
+	destPath := filepath.Join(baseDir, folderName)
+	if mode == projects.AdoptInPlace {
+		destPath = m.sourcePath
+	} else if _, err := os.Lstat(destPath); !os.IsNotExist(err) {
 		// Check destination doesn't exist
-		if _, err := os.Stat(destPath); !os.IsNotExist(err) {
-			return AdoptProjectMsg{Err: fmt.Errorf("'%s' already exists in workspace", folderName)}
-		}
+		return AdoptProjectMsg{Err: fmt.Errorf("'%s' already exists in workspace", folderName)}
+	}
+
+	// Render the plan before copying so undefined variables fail early
+	tmpl := templates.EmbeddedTemplates["irl-basic"]
+	if m.templateIdx > 0 && m.templateIdx <= len(m.templates) {
+		tmpl = m.templates[m.templateIdx-1]
+	}
+	planContent, err := templates.Apply(tmpl.Content, folderName, "", nil)
+	if err != nil {
+		return AdoptProjectMsg{Err: err}
+	}
+	renderValues := templates.Values(tmpl.Content, folderName, "", nil)
+	files, err := tmpl.Files(renderValues)
+	if err != nil {
+		return AdoptProjectMsg{Err: err}
+	}
+	renderedTemplate := planContent
+
+	// Note what the folder already holds in the plan
+	if tailored, _, err := analyze.TailorPlan(planContent, m.sourcePath); err == nil {
+		planContent = tailored
+	}
 
-		// Ensure workspace exists
+	// Ensure workspace exists
+	if mode != projects.AdoptInPlace {
 		if err := os.MkdirAll(baseDir, 0755); err != nil {
 			return AdoptProjectMsg{Err: err}
 		}
+	}
 
-		// Copy folder
-		cpCmd := exec.Command("cp", "-r", m.sourcePath, destPath)
-		if err := cpCmd.Run(); err != nil {
+	switch mode {
+	case projects.AdoptCopy:
+		// Copy folder, leaving out environments and caches
+		opts := fsutil.Options{Exclude: fsutil.DefaultExcludes, Progress: progress}
+		if _, err := fsutil.Copy(m.sourcePath, destPath, opts); err != nil {
 			return AdoptProjectMsg{Err: fmt.Errorf("copy failed: %w", err)}
 		}
-
-		// Add .gitignore if not present
-		gitignorePath := filepath.Join(destPath, ".gitignore")
-		if _, err := os.Stat(gitignorePath); os.IsNotExist(err) {
-			scaffold.Create(destPath)
+	case projects.AdoptMove:
+		if _, err := fsutil.Move(m.sourcePath, destPath, progress); err != nil {
+			return AdoptProjectMsg{Err: fmt.Errorf("move failed: %w", err)}
 		}
+	case projects.AdoptSymlink:
+		if err := os.Symlink(m.sourcePath, destPath); err != nil {
+			return AdoptProjectMsg{Err: fmt.Errorf("link failed: %w", err)}
+		}
+	}
 
-		// Add plans/main-plan.md if no plan file exists
-		hasPlan := fileExists(filepath.Join(destPath, "plans", "main-plan.md")) ||
-			fileExists(filepath.Join(destPath, "main-plan.md")) ||
-			fileExists(filepath.Join(destPath, "01-plans", "main-plan.md"))
+	// Add directory template files without touching existing ones
+	if _, err := templates.WriteFiles(destPath, files, false); err != nil {
+		return AdoptProjectMsg{Err: err}
+	}
+
+	// Add .gitignore if not present
+	gitignorePath := filepath.Join(destPath, ".gitignore")
+	if _, err := os.Stat(gitignorePath); os.IsNotExist(err) {
+		scaffold.Create(destPath)
+	}
 
+	// Add plans/main-plan.md if no plan file exists
+	hasPlan := fileExists(filepath.Join(destPath, "plans", "main-plan.md")) ||
+		fileExists(filepath.Join(destPath, "main-plan.md")) ||
+		fileExists(filepath.Join(destPath, "01-plans", "main-plan.md"))
+
+	if !hasPlan {
+		planContent = scaffold.InjectProfile(planContent)
+		scaffold.WritePlan(destPath, planContent)
+	}
+
+	// Record project metadata unless the folder already has some
+	if !fileExists(projects.MetaPath(destPath)) {
+		meta := projects.NewMeta("")
+		meta.IRLVersion = m.version
 		if !hasPlan {
-			var planContent string
-			if m.templateIdx > 0 && m.templateIdx <= len(m.templates) {
-				planContent = m.templates[m.templateIdx-1].Content
-			} else {
-				planContent = templates.EmbeddedTemplates["irl-basic"].Content
-			}
-			planContent = scaffold.InjectProfile(planContent)
-			scaffold.WritePlan(destPath, planContent)
+			meta.Template = tmpl.Name
+			meta.Source = tmpl.Source
+			meta.Hash = projects.HashContent(tmpl.Content)
+			meta.Vars = renderValues
+			meta.PlanHash = projects.HashPlan(planContent)
 		}
+		projects.Lock(destPath, meta, renderedTemplate)
+	}
 
-		// Git init if not already a repo
-		gitDir := filepath.Join(destPath, ".git")
-		if _, err := os.Stat(gitDir); os.IsNotExist(err) {
-			scaffold.GitInit(destPath)
-		}
+	// Git init if not already a repo
+	gitDir := filepath.Join(destPath, ".git")
+	if _, err := os.Stat(gitDir); os.IsNotExist(err) {
+		scaffold.GitInit(destPath)
+	}
 
-		return AdoptProjectMsg{Path: destPath}
+	// In-place projects are listed from where they are
+	if mode == projects.AdoptInPlace {
+		if err := projects.RegisterInPlace(destPath); err != nil {
+			return AdoptProjectMsg{Err: fmt.Errorf("couldn't register project: %w", err)}
+		}
 	}
+
+	return AdoptProjectMsg{Path: destPath}
 }
 
 func fileExists(path string) bool {
@@ -352,6 +492,8 @@ func (m AdoptModel) View() string {
 	switch m.step {
 	case AdoptStepBrowse:
 		b.WriteString(m.viewBrowse())
+	case AdoptStepMode:
+		b.WriteString(m.viewMode())
 	case AdoptStepTemplate:
 		if len(m.templates) == 0 && m.step == AdoptStepTemplate {
 			// Still loading
@@ -361,6 +503,9 @@ func (m AdoptModel) View() string {
 		}
 	case AdoptStepAdopting:
 		b.WriteString("  " + m.spinner.View() + " Adopting folder...")
+		if m.progress.TotalFiles > 0 {
+			b.WriteString("\n\n" + m.viewProgress())
+		}
 	case AdoptStepDone:
 		b.WriteString(m.viewDone())
 	}
@@ -368,6 +513,25 @@ func (m AdoptModel) View() string {
 	return b.String()
 }
 
+// viewProgress renders a bar for the folder copy
+func (m AdoptModel) viewProgress() string {
+	barWidth := 30
+	filled := int(m.progress.Percent() * float64(barWidth))
+	if filled > barWidth {
+		filled = barWidth
+	}
+
+	barStyle := lipgloss.NewStyle().Foreground(theme.Accent)
+	emptyStyle := lipgloss.NewStyle().Foreground(theme.Muted)
+	statusStyle := lipgloss.NewStyle().Foreground(theme.Muted)
+
+	p := m.progress
+	bar := barStyle.Render(strings.Repeat("━", filled)) + emptyStyle.Render(strings.Repeat("─", barWidth-filled))
+	counts := fmt.Sprintf("%d/%d files · %s of %s", p.Files, p.TotalFiles,
+		status.FormatSize(p.Bytes), status.FormatSize(p.TotalBytes))
+	return "  " + bar + " " + statusStyle.Render(counts)
+}
+
 func (m AdoptModel) viewBrowse() string {
 	var b strings.Builder
 
@@ -430,6 +594,41 @@ func (m AdoptModel) viewBrowse() string {
 	return b.String()
 }
 
+func (m AdoptModel) viewMode() string {
+	var b strings.Builder
+
+	hintStyle := lipgloss.NewStyle().Foreground(theme.Muted).MarginLeft(2)
+	pathStyle := lipgloss.NewStyle().Foreground(theme.Muted).MarginLeft(2)
+	nameStyle := lipgloss.NewStyle().Foreground(theme.Accent).Bold(true).MarginLeft(2)
+
+	b.WriteString(pathStyle.Render("Adopting:"))
+	b.WriteString("\n")
+	b.WriteString(nameStyle.Render(filepath.Base(m.sourcePath)))
+	b.WriteString("\n\n")
+
+	b.WriteString(hintStyle.Render("How should the folder join the workspace?"))
+	b.WriteString("\n\n")
+
+	cursorOn := lipgloss.NewStyle().Foreground(theme.Accent).Bold(true).Render("●")
+	cursorOff := "  "
+	itemStyle := lipgloss.NewStyle().MarginLeft(2)
+	selectedStyle := lipgloss.NewStyle().Foreground(theme.Accent).Bold(true).MarginLeft(2)
+	descStyle := lipgloss.NewStyle().Foreground(theme.Muted)
+
+	for i, mode := range projects.AdoptModes {
+		cursor := cursorOff
+		style := itemStyle
+		if m.modeIdx == i {
+			cursor = cursorOn
+			style = selectedStyle
+		}
+		b.WriteString(cursor + " " + style.Render(fmt.Sprintf("%-9s", mode)) + " " + descStyle.Render(adoptModeHints[mode]))
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
 func (m AdoptModel) viewTemplate() string {
 	var b strings.Builder
 
@@ -440,6 +639,7 @@ func (m AdoptModel) viewTemplate() string {
 	b.WriteString(pathStyle.Render("Adopting:"))
 	b.WriteString("\n")
 	b.WriteString(nameStyle.Render(filepath.Base(m.sourcePath)))
+	b.WriteString(pathStyle.Render("(" + projects.AdoptModes[m.modeIdx] + ")"))
 	b.WriteString("\n\n")
 
 	b.WriteString(hintStyle.Render("Pick a template for the plan file"))
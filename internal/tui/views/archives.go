@@ -0,0 +1,171 @@
+package views
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/drpedapati/irl-template/pkg/archive"
+	"github.com/drpedapati/irl-template/pkg/status"
+	"github.com/drpedapati/irl-template/pkg/theme"
+)
+
+// ArchivesModel lists archived projects and restores them
+type ArchivesModel struct {
+	entries  []archive.Entry
+	cursor   int
+	scroll   int
+	confirm  bool   // asking before restoring the selected archive
+	message  string // feedback after a restore
+	failed   bool   // message is an error
+	restored string // name of the last restored project
+	done     bool
+}
+
+const archivesVisibleItems = 8
+
+// NewArchivesModel lists the archives in the workspace roots
+func NewArchivesModel() ArchivesModel {
+	return ArchivesModel{entries: archive.List(archive.Dirs())}
+}
+
+// IsDone returns true when the user wants to go back
+func (m ArchivesModel) IsDone() bool {
+	return m.done
+}
+
+// Restored returns the name of the last project restored, if any
+func (m ArchivesModel) Restored() string {
+	return m.restored
+}
+
+// Update handles key presses
+func (m ArchivesModel) Update(msg tea.KeyMsg) (ArchivesModel, tea.Cmd) {
+	if m.confirm {
+		switch msg.String() {
+		case "y", "Y":
+			m.confirm = false
+			m.restore()
+		case "n", "N", "esc":
+			m.confirm = false
+		}
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "up":
+		if m.cursor > 0 {
+			m.cursor--
+			if m.cursor < m.scroll {
+				m.scroll = m.cursor
+			}
+		}
+	case "down":
+		if m.cursor < len(m.entries)-1 {
+			m.cursor++
+			if m.cursor >= m.scroll+archivesVisibleItems {
+				m.scroll = m.cursor - archivesVisibleItems + 1
+			}
+		}
+	case "enter", "right":
+		if len(m.entries) > 0 {
+			m.confirm = true
+			m.message = ""
+		}
+	case "esc", "left":
+		m.done = true
+	}
+	return m, nil
+}
+
+// restore verifies and extracts the selected archive to where it came
+// from, then deletes the archive
+func (m *ArchivesModel) restore() {
+	e := m.entries[m.cursor]
+	if _, err := archive.Restore(e.Path, e.Manifest.Path); err != nil {
+		m.message, m.failed = err.Error(), true
+		return
+	}
+	m.restored = e.Manifest.Project
+	if err := os.Remove(e.Path); err != nil {
+		m.message, m.failed = "Restored, but couldn't remove the archive", true
+	} else {
+		m.message, m.failed = "Restored "+e.Manifest.Project, false
+	}
+	m.entries = append(m.entries[:m.cursor], m.entries[m.cursor+1:]...)
+	if m.cursor >= len(m.entries) && m.cursor > 0 {
+		m.cursor--
+	}
+	if m.scroll > m.cursor {
+		m.scroll = m.cursor
+	}
+}
+
+// View renders the archive list
+func (m ArchivesModel) View() string {
+	var b strings.Builder
+
+	headerStyle := lipgloss.NewStyle().Foreground(theme.Muted).Bold(true)
+	mutedStyle := lipgloss.NewStyle().Foreground(theme.Muted)
+	accentStyle := lipgloss.NewStyle().Foreground(theme.Accent).Bold(true)
+	warningStyle := lipgloss.NewStyle().Foreground(theme.Warning).Bold(true)
+
+	b.WriteString("\n")
+	b.WriteString("  " + headerStyle.Render("Archived projects"))
+	b.WriteString("\n\n")
+
+	if m.message != "" {
+		if m.failed {
+			b.WriteString("  " + lipgloss.NewStyle().Foreground(theme.Error).Render("✗ "+m.message))
+		} else {
+			b.WriteString("  " + lipgloss.NewStyle().Foreground(theme.Success).Render("✓ "+m.message))
+		}
+		b.WriteString("\n\n")
+	}
+
+	if len(m.entries) == 0 {
+		b.WriteString(mutedStyle.Render("  No archived projects"))
+		b.WriteString("\n\n")
+		b.WriteString(mutedStyle.Render("  Press a on a project to archive it"))
+		b.WriteString("\n")
+		return b.String()
+	}
+
+	endIdx := m.scroll + archivesVisibleItems
+	if endIdx > len(m.entries) {
+		endIdx = len(m.entries)
+	}
+	for i := m.scroll; i < endIdx; i++ {
+		e := m.entries[i]
+		cursor := " "
+		nameStyle := lipgloss.NewStyle()
+		if i == m.cursor {
+			cursor = accentStyle.Render(">")
+			nameStyle = accentStyle
+		}
+		name := e.Manifest.Project
+		if len(name) > 36 {
+			name = name[:33] + "..."
+		}
+		name += strings.Repeat(" ", 37-len(name))
+		b.WriteString("  " + cursor + " " + nameStyle.Render(name) +
+			mutedStyle.Render(smartDate(e.Manifest.Created)+"  "+status.FormatSize(e.Manifest.TotalSize())))
+		b.WriteString("\n")
+	}
+	if len(m.entries) > archivesVisibleItems {
+		b.WriteString(mutedStyle.Render("    " + itoa(m.scroll+1) + "-" + itoa(endIdx) + " of " + itoa(len(m.entries))))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	sel := m.entries[m.cursor]
+	if m.confirm {
+		b.WriteString("  " + warningStyle.Render("Restore \""+sel.Manifest.Project+"\" to "+filepath.Dir(sel.Manifest.Path)+"? (y/n)"))
+	} else {
+		b.WriteString("  " + mutedStyle.Render("Restores to "+sel.Manifest.Path))
+	}
+	b.WriteString("\n")
+	return b.String()
+}
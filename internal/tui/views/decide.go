@@ -0,0 +1,147 @@
+package views
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/drpedapati/irl-template/pkg/decisions"
+	"github.com/drpedapati/irl-template/pkg/theme"
+)
+
+// Decision form fields
+const (
+	decisionFieldDecision = iota
+	decisionFieldRationale
+	decisionFieldEvidence
+	decisionFieldValidation
+	decisionFieldCount
+)
+
+var decisionFieldLabels = []string{"Decision", "Rationale", "Evidence", "Validation"}
+
+// DecisionFormModel records a decision in a project's decision log
+type DecisionFormModel struct {
+	projectPath string
+	inputs      []textinput.Model
+	focusIndex  int
+	err         error
+	saved       *decisions.Decision
+	done        bool
+}
+
+// NewDecisionFormModel creates an empty decision form for a project
+func NewDecisionFormModel(projectPath string) DecisionFormModel {
+	placeholders := []string{
+		"What was decided",
+		"Why (required)",
+		"Data, figures or papers behind it",
+		"How it will be checked",
+	}
+	inputs := make([]textinput.Model, decisionFieldCount)
+	for i := range inputs {
+		inputs[i] = textinput.New()
+		inputs[i].Placeholder = placeholders[i]
+		inputs[i].Width = 50
+	}
+	inputs[decisionFieldDecision].Focus()
+
+	return DecisionFormModel{
+		projectPath: projectPath,
+		inputs:      inputs,
+	}
+}
+
+// IsDone returns true when the form should close
+func (m DecisionFormModel) IsDone() bool {
+	return m.done
+}
+
+// Saved returns the logged decision, or nil if the form was cancelled
+func (m DecisionFormModel) Saved() *decisions.Decision {
+	return m.saved
+}
+
+func (m *DecisionFormModel) setFocus(index int) tea.Cmd {
+	m.inputs[m.focusIndex].Blur()
+	m.focusIndex = index
+	m.inputs[m.focusIndex].Focus()
+	return textinput.Blink
+}
+
+// Update handles messages
+func (m DecisionFormModel) Update(msg tea.Msg) (DecisionFormModel, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "esc":
+			m.done = true
+			return m, nil
+
+		case "tab", "down":
+			return m, m.setFocus((m.focusIndex + 1) % decisionFieldCount)
+
+		case "shift+tab", "up":
+			return m, m.setFocus((m.focusIndex + decisionFieldCount - 1) % decisionFieldCount)
+
+		case "enter":
+			// Enter moves through the required fields, then saves
+			if m.focusIndex < decisionFieldRationale && strings.TrimSpace(m.inputs[decisionFieldRationale].Value()) == "" {
+				return m, m.setFocus(m.focusIndex + 1)
+			}
+			d, err := decisions.Append(m.projectPath, decisions.Decision{
+				Decision:   m.inputs[decisionFieldDecision].Value(),
+				Rationale:  m.inputs[decisionFieldRationale].Value(),
+				Evidence:   m.inputs[decisionFieldEvidence].Value(),
+				Validation: m.inputs[decisionFieldValidation].Value(),
+			})
+			if err != nil {
+				m.err = err
+				return m, nil
+			}
+			m.saved = &d
+			m.done = true
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.inputs[m.focusIndex], cmd = m.inputs[m.focusIndex].Update(msg)
+	return m, cmd
+}
+
+// View renders the decision form
+func (m DecisionFormModel) View() string {
+	var b strings.Builder
+
+	headerStyle := lipgloss.NewStyle().Foreground(theme.Muted).Bold(true)
+	pathStyle := lipgloss.NewStyle().Foreground(theme.Accent).Bold(true)
+	labelStyle := lipgloss.NewStyle().Foreground(theme.Muted).Width(14)
+	focusedLabelStyle := lipgloss.NewStyle().Foreground(theme.Accent).Bold(true).Width(14)
+	errorStyle := lipgloss.NewStyle().Foreground(theme.Error).MarginLeft(2)
+	hintStyle := lipgloss.NewStyle().Foreground(theme.Muted).MarginLeft(2)
+
+	b.WriteString("\n")
+	b.WriteString("  " + headerStyle.Render("Log decision") + "  " + pathStyle.Render(decisions.File))
+	b.WriteString("\n\n")
+
+	if m.err != nil {
+		b.WriteString(errorStyle.Render("✗ " + m.err.Error()))
+		b.WriteString("\n\n")
+	}
+
+	for i, input := range m.inputs {
+		style := labelStyle
+		if i == m.focusIndex {
+			style = focusedLabelStyle
+		}
+		b.WriteString("  ")
+		b.WriteString(style.Render(decisionFieldLabels[i]))
+		b.WriteString(input.View())
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(hintStyle.Render("tab next field · enter save · esc cancel"))
+	return b.String()
+}
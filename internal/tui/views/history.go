@@ -0,0 +1,419 @@
+package views
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/drpedapati/irl-template/pkg/gitutil"
+	"github.com/drpedapati/irl-template/pkg/plan"
+	"github.com/drpedapati/irl-template/pkg/theme"
+)
+
+const (
+	historyVisibleItems = 10
+	historyDiffLines    = 13
+)
+
+// HistoryModel lists the commits that touched a project's plan and shows
+// diffs between them
+type HistoryModel struct {
+	projectPath string
+	planRel     string // plan path relative to projectPath
+	commits     []gitutil.Commit
+	err         error
+	cursor      int
+	scroll      int
+	marked      []int // up to two commit indexes selected for comparison
+
+	// Diff mode
+	diffing    bool
+	diffTitle  string
+	diffLines  []string
+	diffScroll int
+
+	// Restore confirmation
+	confirmRestore bool
+	planDirty      bool // the plan has uncommitted changes restoring would discard
+
+	message string
+	isError bool
+	done    bool
+}
+
+// NewHistoryModel loads the plan history for a project
+func NewHistoryModel(projectPath string) HistoryModel {
+	m := HistoryModel{projectPath: projectPath}
+	m.load()
+	return m
+}
+
+func (m *HistoryModel) load() {
+	m.commits = nil
+	m.err = nil
+
+	planPath, err := plan.Find(m.projectPath)
+	if err != nil {
+		m.err = err
+		return
+	}
+	m.planRel, _ = filepath.Rel(m.projectPath, planPath)
+
+	if !gitutil.IsRepo(m.projectPath) {
+		m.err = errNotRepo
+		return
+	}
+
+	m.commits, m.err = gitutil.FileLog(m.projectPath, m.planRel)
+	if m.cursor >= len(m.commits) {
+		m.cursor = 0
+		m.scroll = 0
+	}
+}
+
+var errNotRepo = errors.New("project is not a git repository")
+
+// IsDone returns true when the user leaves the history view
+func (m HistoryModel) IsDone() bool {
+	return m.done
+}
+
+// Update handles key messages
+func (m HistoryModel) Update(msg tea.Msg) (HistoryModel, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	key := keyMsg.String()
+
+	if m.confirmRestore {
+		switch key {
+		case "y", "Y":
+			m.confirmRestore = false
+			m.restore()
+		case "n", "N", "esc":
+			m.confirmRestore = false
+		}
+		return m, nil
+	}
+
+	if m.diffing {
+		switch key {
+		case "up", "k":
+			if m.diffScroll > 0 {
+				m.diffScroll--
+			}
+		case "down", "j":
+			if m.diffScroll < len(m.diffLines)-historyDiffLines {
+				m.diffScroll++
+			}
+		case "pgup":
+			m.diffScroll -= historyDiffLines
+			if m.diffScroll < 0 {
+				m.diffScroll = 0
+			}
+		case "pgdown", " ":
+			m.diffScroll += historyDiffLines
+			if last := len(m.diffLines) - historyDiffLines; m.diffScroll > last {
+				m.diffScroll = last
+			}
+			if m.diffScroll < 0 {
+				m.diffScroll = 0
+			}
+		case "esc", "left", "q":
+			m.diffing = false
+		}
+		return m, nil
+	}
+
+	switch key {
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+			if m.cursor < m.scroll {
+				m.scroll = m.cursor
+			}
+		}
+	case "down", "j":
+		if m.cursor < len(m.commits)-1 {
+			m.cursor++
+			if m.cursor >= m.scroll+historyVisibleItems {
+				m.scroll = m.cursor - historyVisibleItems + 1
+			}
+		}
+	case " ":
+		m.toggleMark(m.cursor)
+	case "enter", "right", "d":
+		m.showDiff()
+	case "r":
+		if len(m.commits) > 0 {
+			m.message = ""
+			clean, err := gitutil.IsClean(m.projectPath, m.planRel)
+			if err != nil {
+				m.message = err.Error()
+				m.isError = true
+				return m, nil
+			}
+			m.planDirty = !clean
+			m.confirmRestore = true
+		}
+	case "esc", "left":
+		if len(m.marked) > 0 {
+			m.marked = nil
+			return m, nil
+		}
+		m.done = true
+	}
+	return m, nil
+}
+
+func (m *HistoryModel) toggleMark(i int) {
+	if i < 0 || i >= len(m.commits) {
+		return
+	}
+	for j, idx := range m.marked {
+		if idx == i {
+			m.marked = append(m.marked[:j], m.marked[j+1:]...)
+			return
+		}
+	}
+	if len(m.marked) == 2 {
+		m.marked = m.marked[1:]
+	}
+	m.marked = append(m.marked, i)
+}
+
+func (m HistoryModel) isMarked(i int) bool {
+	for _, idx := range m.marked {
+		if idx == i {
+			return true
+		}
+	}
+	return false
+}
+
+// showDiff compares the two marked commits, or shows what the selected
+// commit changed when fewer than two are marked
+func (m *HistoryModel) showDiff() {
+	if len(m.commits) == 0 {
+		return
+	}
+
+	var out string
+	var err error
+	if len(m.marked) == 2 {
+		// Commits are listed newest first, so the higher index is older
+		older, newer := m.commits[m.marked[0]], m.commits[m.marked[1]]
+		if m.marked[0] < m.marked[1] {
+			older, newer = newer, older
+		}
+		m.diffTitle = older.Short + " → " + newer.Short
+		out, err = gitutil.DiffFile(m.projectPath, older.Hash, newer.Hash, older.Path, newer.Path)
+	} else {
+		c := m.commits[m.cursor]
+		m.diffTitle = c.Short + " " + c.Subject
+		// The plan may have been renamed by this commit; the older one has its previous name
+		paths := []string{c.Path}
+		if m.cursor+1 < len(m.commits) {
+			paths = append(paths, m.commits[m.cursor+1].Path)
+		}
+		out, err = gitutil.ShowCommitFile(m.projectPath, c.Hash, paths...)
+	}
+
+	if err != nil {
+		m.message = err.Error()
+		m.isError = true
+		return
+	}
+	if strings.TrimSpace(out) == "" {
+		out = "(no changes to " + m.planRel + ")"
+	}
+
+	m.diffLines = strings.Split(out, "\n")
+	m.diffScroll = 0
+	m.diffing = true
+}
+
+// restore writes the selected version of the plan and commits it,
+// so the restore itself becomes part of the history
+func (m *HistoryModel) restore() {
+	c := m.commits[m.cursor]
+
+	// The plan may have had another name at that commit
+	content, err := gitutil.ShowFile(m.projectPath, c.Hash, c.Path)
+	if err != nil {
+		m.message = err.Error()
+		m.isError = true
+		return
+	}
+
+	planPath := filepath.Join(m.projectPath, m.planRel)
+	if current, err := os.ReadFile(planPath); err == nil && string(current) == content {
+		m.message = "Plan already matches " + c.Short
+		m.isError = false
+		return
+	}
+
+	if err := os.WriteFile(planPath, []byte(content), 0644); err != nil {
+		m.message = "Failed to write plan: " + err.Error()
+		m.isError = true
+		return
+	}
+
+	msg := "Restore " + filepath.ToSlash(m.planRel) + " to " + c.Short
+	hash, err := gitutil.CommitFiles(m.projectPath, msg, []string{m.planRel})
+	if err != nil {
+		m.message = "Failed to commit: " + err.Error()
+		m.isError = true
+		return
+	}
+
+	m.message = "Restored " + c.Short + " as " + gitutil.Short(hash)
+	m.isError = false
+	m.marked = nil
+	m.cursor = 0
+	m.scroll = 0
+	m.load()
+}
+
+// View renders the history view
+func (m HistoryModel) View() string {
+	var b strings.Builder
+
+	headerStyle := lipgloss.NewStyle().Foreground(theme.Muted).Bold(true)
+	mutedStyle := lipgloss.NewStyle().Foreground(theme.Muted)
+	keyStyle := lipgloss.NewStyle().Foreground(theme.Accent).Bold(true)
+	selectedStyle := lipgloss.NewStyle().Foreground(theme.Accent).Bold(true)
+	hashStyle := lipgloss.NewStyle().Foreground(theme.Primary)
+
+	if m.diffing {
+		return m.renderDiff()
+	}
+
+	b.WriteString("\n")
+	b.WriteString("  " + headerStyle.Render("Plan history") + "  " + mutedStyle.Render(filepath.ToSlash(m.planRel)))
+	b.WriteString("\n\n")
+
+	if m.err != nil {
+		errStyle := lipgloss.NewStyle().Foreground(theme.Error)
+		b.WriteString("  " + errStyle.Render("✗ "+m.err.Error()))
+		b.WriteString("\n")
+		return b.String()
+	}
+
+	if m.confirmRestore {
+		warningStyle := lipgloss.NewStyle().Foreground(theme.Warning).Bold(true)
+		if m.planDirty {
+			b.WriteString("  " + warningStyle.Render(filepath.ToSlash(m.planRel)+" has uncommitted changes that restoring will discard."))
+			b.WriteString("\n")
+		}
+		b.WriteString("  " + warningStyle.Render("Restore plan to "+m.commits[m.cursor].Short+" as a new commit? (y/n)"))
+		b.WriteString("\n\n")
+	} else if m.message != "" {
+		style := lipgloss.NewStyle().Foreground(theme.Success)
+		prefix := "✓ "
+		if m.isError {
+			style = lipgloss.NewStyle().Foreground(theme.Error)
+			prefix = "✗ "
+		}
+		b.WriteString("  " + style.Render(prefix+m.message))
+		b.WriteString("\n\n")
+	}
+
+	if len(m.commits) == 0 {
+		b.WriteString(mutedStyle.Render("  No commits touch the plan yet"))
+		b.WriteString("\n")
+		return b.String()
+	}
+
+	endIdx := m.scroll + historyVisibleItems
+	if endIdx > len(m.commits) {
+		endIdx = len(m.commits)
+	}
+
+	for i := m.scroll; i < endIdx; i++ {
+		c := m.commits[i]
+
+		cursor := " "
+		subjectStyle := lipgloss.NewStyle()
+		if i == m.cursor {
+			cursor = keyStyle.Render(">")
+			subjectStyle = selectedStyle
+		}
+		mark := " "
+		if m.isMarked(i) {
+			mark = keyStyle.Render("●")
+		}
+
+		subject := []rune(c.Subject)
+		if len(subject) > 40 {
+			subject = append(subject[:37], []rune("...")...)
+		}
+		subjectText := string(subject) + strings.Repeat(" ", 40-len(subject))
+
+		b.WriteString("  " + cursor + mark + " " + hashStyle.Render(c.Short) + " " +
+			subjectStyle.Render(subjectText) + " " + mutedStyle.Render(smartDate(c.Date)))
+		b.WriteString("\n")
+	}
+
+	if len(m.commits) > historyVisibleItems {
+		b.WriteString(mutedStyle.Render("    " + itoa(m.scroll+1) + "-" + itoa(endIdx) + " of " + itoa(len(m.commits))))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString("  " + keyStyle.Render("space") + mutedStyle.Render(" mark  ") +
+		keyStyle.Render("enter") + mutedStyle.Render(" diff  ") +
+		keyStyle.Render("r") + mutedStyle.Render(" restore  ") +
+		keyStyle.Render("←") + mutedStyle.Render(" back"))
+
+	return b.String()
+}
+
+// renderDiff renders a colorized unified diff
+func (m HistoryModel) renderDiff() string {
+	var b strings.Builder
+
+	headerStyle := lipgloss.NewStyle().Foreground(theme.Muted).Bold(true)
+	mutedStyle := lipgloss.NewStyle().Foreground(theme.Muted)
+	addStyle := lipgloss.NewStyle().Foreground(theme.Success)
+	delStyle := lipgloss.NewStyle().Foreground(theme.Error)
+	hunkStyle := lipgloss.NewStyle().Foreground(theme.Accent)
+
+	b.WriteString("\n")
+	b.WriteString("  " + headerStyle.Render("Diff") + "  " + mutedStyle.Render(m.diffTitle))
+	b.WriteString("\n\n")
+
+	end := m.diffScroll + historyDiffLines
+	if end > len(m.diffLines) {
+		end = len(m.diffLines)
+	}
+
+	for _, line := range m.diffLines[m.diffScroll:end] {
+		if r := []rune(line); len(r) > 66 {
+			line = string(r[:65]) + "…"
+		}
+		switch {
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"),
+			strings.HasPrefix(line, "diff "), strings.HasPrefix(line, "index "):
+			line = mutedStyle.Render(line)
+		case strings.HasPrefix(line, "+"):
+			line = addStyle.Render(line)
+		case strings.HasPrefix(line, "-"):
+			line = delStyle.Render(line)
+		case strings.HasPrefix(line, "@@"):
+			line = hunkStyle.Render(line)
+		}
+		b.WriteString("  " + line + "\n")
+	}
+
+	if len(m.diffLines) > historyDiffLines {
+		b.WriteString(mutedStyle.Render("    " + itoa(m.diffScroll+1) + "-" + itoa(end) + " of " + itoa(len(m.diffLines)) + " lines"))
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
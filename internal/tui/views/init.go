@@ -15,6 +15,7 @@ import (
 	"github.com/drpedapati/irl-template/pkg/config"
 	"github.com/drpedapati/irl-template/pkg/editor"
 	"github.com/drpedapati/irl-template/pkg/naming"
+	"github.com/drpedapati/irl-template/pkg/projects"
 	"github.com/drpedapati/irl-template/pkg/scaffold"
 	"github.com/drpedapati/irl-template/pkg/templates"
 	"github.com/drpedapati/irl-template/pkg/theme"
@@ -28,6 +29,7 @@ const (
 	StepBrowse
 	StepPurpose
 	StepTemplate
+	StepVariables
 	StepCreating
 	StepDone
 )
@@ -43,11 +45,15 @@ type InitModel struct {
 	projectName    string
 	templates      []templates.Template
 	templateIdx    int
+	variables      []templates.Variable // custom variables the template declares
+	varInputs      []textinput.Model
+	varIdx         int
 	spinner        spinner.Model
 	projectPath    string
 	err            error
 	done           bool
-	skippedDirStep bool // True if we skipped directory selection (default was set)
+	skippedDirStep bool   // True if we skipped directory selection (default was set)
+	version        string // irl version recorded in the project lock
 
 	// Directory selection state
 	directoryCursor int // 0 = use current, 1 = browse
@@ -72,7 +78,7 @@ type browseEntry struct {
 const browseVisibleItems = 8
 
 // NewInitModel creates a new init view
-func NewInitModel() InitModel {
+func NewInitModel(version string) InitModel {
 	ti := textinput.New()
 	ti.Placeholder = "Enter your project purpose..."
 	ti.Width = 50
@@ -103,6 +109,7 @@ func NewInitModel() InitModel {
 		purposeInput:   ti,
 		spinner:        s,
 		skippedDirStep: hasDefaultDir,
+		version:        version,
 	}
 }
 
@@ -125,7 +132,10 @@ func (m InitModel) CanGoBack() bool {
 	if m.step == StepPurpose && m.skippedDirStep {
 		return false // Go back to menu, not within wizard
 	}
-	return m.step == StepBrowse || m.step == StepPurpose || m.step == StepTemplate
+	if m.step == StepDone && (m.actionView.IsShowingHistory() || m.actionView.IsShowingStatus() || m.actionView.IsShowingDecision()) {
+		return true // Close the sub-view first
+	}
+	return m.step == StepBrowse || m.step == StepPurpose || m.step == StepTemplate || m.step == StepVariables
 }
 
 // Done returns true if the wizard is complete
@@ -138,7 +148,7 @@ func (m InitModel) Update(msg tea.Msg) (InitModel, tea.Cmd) {
 	var cmds []tea.Cmd
 
 	switch msg := msg.(type) {
-	case editor.EditorFinishedMsg, editor.EditorOpenedMsg:
+	case editor.EditorFinishedMsg, editor.EditorOpenedMsg, ProjectStatusMsg:
 		// Pass editor messages to action view when in StepDone
 		if m.step == StepDone {
 			var cmd tea.Cmd
@@ -156,6 +166,8 @@ func (m InitModel) Update(msg tea.Msg) (InitModel, tea.Cmd) {
 			return m.updatePurpose(msg)
 		case StepTemplate:
 			return m.updateTemplate(msg)
+		case StepVariables:
+			return m.updateVariables(msg)
 		case StepDone:
 			return m.updateDone(msg)
 		}
@@ -354,6 +366,22 @@ func (m InitModel) updateTemplate(msg tea.KeyMsg) (InitModel, tea.Cmd) {
 			m.templateIdx++ // +1 for "None" option
 		}
 	case "enter", "right":
+		// Ask for the template's custom variables before creating
+		m.variables, _ = templates.ParseVariables(m.selectedTemplate().Content)
+		if len(m.variables) > 0 {
+			m.varInputs = make([]textinput.Model, len(m.variables))
+			for i, v := range m.variables {
+				ti := textinput.New()
+				ti.Placeholder = v.Name
+				ti.Width = 50
+				ti.SetValue(v.Default)
+				m.varInputs[i] = ti
+			}
+			m.varIdx = 0
+			m.varInputs[0].Focus()
+			m.step = StepVariables
+			return m, textinput.Blink
+		}
 		m.step = StepCreating
 		return m, tea.Batch(m.createProject(), m.spinner.Tick)
 	case "esc", "left":
@@ -364,6 +392,49 @@ func (m InitModel) updateTemplate(msg tea.KeyMsg) (InitModel, tea.Cmd) {
 	return m, nil
 }
 
+func (m InitModel) updateVariables(msg tea.KeyMsg) (InitModel, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.step = StepTemplate
+		return m, nil
+	case "up", "shift+tab":
+		if m.varIdx > 0 {
+			m.focusVariable(m.varIdx - 1)
+		}
+		return m, textinput.Blink
+	case "down", "tab":
+		if m.varIdx < len(m.varInputs)-1 {
+			m.focusVariable(m.varIdx + 1)
+		}
+		return m, textinput.Blink
+	case "enter":
+		if m.varIdx < len(m.varInputs)-1 {
+			m.focusVariable(m.varIdx + 1)
+			return m, textinput.Blink
+		}
+		m.step = StepCreating
+		return m, tea.Batch(m.createProject(), m.spinner.Tick)
+	}
+
+	var cmd tea.Cmd
+	m.varInputs[m.varIdx], cmd = m.varInputs[m.varIdx].Update(msg)
+	return m, cmd
+}
+
+func (m *InitModel) focusVariable(i int) {
+	m.varInputs[m.varIdx].Blur()
+	m.varIdx = i
+	m.varInputs[i].Focus()
+}
+
+// selectedTemplate returns the chosen template, or an empty plan for "None"
+func (m InitModel) selectedTemplate() templates.Template {
+	if m.templateIdx > 0 && m.templateIdx <= len(m.templates) {
+		return m.templates[m.templateIdx-1]
+	}
+	return templates.Template{Content: "# IRL Plan\n\n[Edit this file to define your research plan]\n"}
+}
+
 func (m InitModel) updateDone(msg tea.KeyMsg) (InitModel, tea.Cmd) {
 	var cmd tea.Cmd
 	m.actionView, cmd = m.actionView.Update(msg)
@@ -390,6 +461,7 @@ type InitProjectCreatedMsg struct {
 func (m InitModel) loadTemplates() tea.Cmd {
 	return func() tea.Msg {
 		list, _ := templates.ListTemplates()
+		list = append(list, templates.ListCustom()...)
 		return InitTemplatesLoadedMsg{Templates: list}
 	}
 }
@@ -403,6 +475,24 @@ func (m InitModel) createProject() tea.Cmd {
 			return InitProjectCreatedMsg{Err: fmt.Errorf("'%s' already exists", projectPath)}
 		}
 
+		// Render the template first so undefined variables fail before
+		// anything is written
+		values := map[string]string{}
+		for i, v := range m.variables {
+			values[v.Name] = m.varInputs[i].Value()
+		}
+		tmpl := m.selectedTemplate()
+		planContent, err := templates.Apply(tmpl.Content, m.projectName, m.purpose, values)
+		if err != nil {
+			return InitProjectCreatedMsg{Err: err}
+		}
+		renderValues := templates.Values(tmpl.Content, m.projectName, m.purpose, values)
+		files, err := tmpl.Files(renderValues)
+		if err != nil {
+			return InitProjectCreatedMsg{Err: err}
+		}
+		renderedTemplate := planContent
+
 		// Create base directory if needed
 		if err := os.MkdirAll(m.baseDir, 0755); err != nil {
 			return InitProjectCreatedMsg{Err: err}
@@ -418,13 +508,9 @@ func (m InitModel) createProject() tea.Cmd {
 			return InitProjectCreatedMsg{Err: err}
 		}
 
-		// Apply template
-		var planContent string
-		if m.templateIdx > 0 && m.templateIdx <= len(m.templates) {
-			tmpl := m.templates[m.templateIdx-1]
-			planContent = tmpl.Content
-		} else {
-			planContent = "# IRL Plan\n\n[Edit this file to define your research plan]\n"
+		// Directory templates bring their own files
+		if _, err := templates.WriteFiles(projectPath, files, true); err != nil {
+			return InitProjectCreatedMsg{Err: err}
 		}
 
 		// Inject profile information
@@ -434,6 +520,18 @@ func (m InitModel) createProject() tea.Cmd {
 			return InitProjectCreatedMsg{Err: err}
 		}
 
+		// Record project metadata and the template so the plan can be upgraded later
+		meta := projects.NewMeta(m.purpose)
+		meta.IRLVersion = m.version
+		if tmpl.Name != "" {
+			meta.Template = tmpl.Name
+			meta.Source = tmpl.Source
+			meta.Hash = projects.HashContent(tmpl.Content)
+			meta.Vars = renderValues
+			meta.PlanHash = projects.HashPlan(planContent)
+		}
+		projects.Lock(projectPath, meta, renderedTemplate) // Ignore errors
+
 		// Git init
 		scaffold.GitInit(projectPath) // Ignore errors
 
@@ -454,6 +552,8 @@ func (m InitModel) View() string {
 		b.WriteString(m.viewPurpose())
 	case StepTemplate:
 		b.WriteString(m.viewTemplate())
+	case StepVariables:
+		b.WriteString(m.viewVariables())
 	case StepCreating:
 		b.WriteString(m.viewCreating())
 	case StepDone:
@@ -650,6 +750,41 @@ func (m InitModel) viewTemplate() string {
 	return b.String()
 }
 
+func (m InitModel) viewVariables() string {
+	var b strings.Builder
+
+	pathStyle := lipgloss.NewStyle().Foreground(theme.Muted).MarginLeft(2)
+	nameStyle := lipgloss.NewStyle().Foreground(theme.Accent).Bold(true).MarginLeft(2)
+	hintStyle := lipgloss.NewStyle().Foreground(theme.Muted).MarginLeft(2)
+	labelStyle := lipgloss.NewStyle().MarginLeft(2)
+	selectedStyle := lipgloss.NewStyle().Foreground(theme.Accent).Bold(true).MarginLeft(2)
+
+	b.WriteString(pathStyle.Render("📁 " + m.baseDir))
+	b.WriteString("\n")
+	b.WriteString(nameStyle.Render(m.projectName))
+	b.WriteString("\n\n")
+
+	b.WriteString(hintStyle.Render("Fill in the template's details"))
+	b.WriteString("\n\n")
+
+	for i, v := range m.variables {
+		style := labelStyle
+		if i == m.varIdx {
+			style = selectedStyle
+		}
+		b.WriteString(style.Render(v.Prompt))
+		b.WriteString("\n")
+		b.WriteString("  " + m.varInputs[i].View())
+		b.WriteString("\n\n")
+	}
+
+	keyStyle := lipgloss.NewStyle().Foreground(theme.Accent).Bold(true)
+	b.WriteString(hintStyle.Render(keyStyle.Render("Enter") + " next  " + keyStyle.Render("↑↓") + " move  " + keyStyle.Render("Esc") + " back"))
+	b.WriteString("\n")
+
+	return b.String()
+}
+
 func (m InitModel) viewCreating() string {
 	var b strings.Builder
 	b.WriteString("  " + m.spinner.View() + " Creating project...")
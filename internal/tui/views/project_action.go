@@ -1,11 +1,14 @@
 package views
 
 import (
+	"fmt"
 	"strings"
 
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/drpedapati/irl-template/pkg/editor"
+	"github.com/drpedapati/irl-template/pkg/status"
 	"github.com/drpedapati/irl-template/pkg/theme"
 )
 
@@ -19,6 +22,34 @@ type ProjectActionModel struct {
 	done            bool
 	isNew           bool // True if this is a newly created project
 	launchingEditor bool // True while terminal editor is open
+
+	// Plan history sub-view
+	showingHistory bool
+	history        HistoryModel
+
+	// Status panel
+	showingStatus bool
+	status        *status.Report
+	statusErr     error
+
+	// Decision log form
+	showingDecision bool
+	decision        DecisionFormModel
+}
+
+// ProjectStatusMsg is sent when a project's status report is ready
+type ProjectStatusMsg struct {
+	Path   string
+	Report *status.Report
+	Err    error
+}
+
+// loadStatus builds the status report in the background
+func loadStatus(path string) tea.Cmd {
+	return func() tea.Msg {
+		r, err := status.Get(path)
+		return ProjectStatusMsg{Path: path, Report: r, Err: err}
+	}
 }
 
 // NewProjectActionModel creates a new project action view
@@ -44,6 +75,21 @@ func (m ProjectActionModel) ProjectPath() string {
 	return m.projectPath
 }
 
+// IsShowingHistory returns true while the plan history sub-view is open
+func (m ProjectActionModel) IsShowingHistory() bool {
+	return m.showingHistory
+}
+
+// IsShowingStatus returns true while the status panel is open
+func (m ProjectActionModel) IsShowingStatus() bool {
+	return m.showingStatus
+}
+
+// IsShowingDecision returns true while the decision form is open
+func (m ProjectActionModel) IsShowingDecision() bool {
+	return m.showingDecision
+}
+
 // IsDone returns true when user wants to exit
 func (m ProjectActionModel) IsDone() bool {
 	return m.done
@@ -72,7 +118,46 @@ func (m ProjectActionModel) Update(msg tea.Msg) (ProjectActionModel, tea.Cmd) {
 		}
 		return m, nil
 
+	case ProjectStatusMsg:
+		if msg.Path == m.projectPath {
+			m.status, m.statusErr = msg.Report, msg.Err
+		}
+		return m, nil
+
 	case tea.KeyMsg:
+		// Any key but refresh closes the status panel
+		if m.showingStatus {
+			if msg.String() == "r" {
+				m.status, m.statusErr = nil, nil
+				return m, loadStatus(m.projectPath)
+			}
+			m.showingStatus = false
+			return m, nil
+		}
+
+		// Delegate to the history view while it's open
+		if m.showingHistory {
+			var cmd tea.Cmd
+			m.history, cmd = m.history.Update(msg)
+			if m.history.IsDone() {
+				m.showingHistory = false
+			}
+			return m, cmd
+		}
+
+		// Delegate to the decision form while it's open
+		if m.showingDecision {
+			var cmd tea.Cmd
+			m.decision, cmd = m.decision.Update(msg)
+			if m.decision.IsDone() {
+				m.showingDecision = false
+				if d := m.decision.Saved(); d != nil {
+					m.message = "Logged decision: " + d.Decision
+				}
+			}
+			return m, cmd
+		}
+
 		key := msg.String()
 
 		// Edit plan file with preferred editor
@@ -80,6 +165,30 @@ func (m ProjectActionModel) Update(msg tea.Msg) (ProjectActionModel, tea.Cmd) {
 			return m.editPlanFile()
 		}
 
+		// Browse plan history
+		if key == "g" {
+			m.history = NewHistoryModel(m.projectPath)
+			m.showingHistory = true
+			m.message = ""
+			return m, nil
+		}
+
+		// Log a decision
+		if key == "l" {
+			m.decision = NewDecisionFormModel(m.projectPath)
+			m.showingDecision = true
+			m.message = ""
+			return m, textinput.Blink
+		}
+
+		// Show the status panel
+		if key == "d" {
+			m.showingStatus = true
+			m.status, m.statusErr = nil, nil
+			m.message = ""
+			return m, loadStatus(m.projectPath)
+		}
+
 		// Check for editor hotkeys (opens project, not plan file)
 		for _, ed := range m.editors {
 			if key == ed.Key {
@@ -143,6 +252,16 @@ func (m ProjectActionModel) editPlanFile() (ProjectActionModel, tea.Cmd) {
 
 // View renders the project action view
 func (m ProjectActionModel) View() string {
+	if m.showingHistory {
+		return m.history.View()
+	}
+	if m.showingStatus {
+		return m.statusView()
+	}
+	if m.showingDecision {
+		return m.decision.View()
+	}
+
 	var b strings.Builder
 
 	checkStyle := lipgloss.NewStyle().Foreground(theme.Success)
@@ -183,7 +302,10 @@ func (m ProjectActionModel) View() string {
 		b.WriteString("  " + keyStyle.Render("e") + " " + primaryActionStyle.Render("Edit plan") + "  " + hintStyle.Render("← start here"))
 		b.WriteString("\n\n")
 	} else {
-		b.WriteString("  " + keyStyle.Render("e") + " " + nameStyle.Render("Edit plan"))
+		b.WriteString("  " + keyStyle.Render("e") + " " + nameStyle.Render("Edit plan") + "    " +
+			keyStyle.Render("g") + " " + nameStyle.Render("Plan history") + "    " +
+			keyStyle.Render("d") + " " + nameStyle.Render("Status") + "    " +
+			keyStyle.Render("l") + " " + nameStyle.Render("Log decision"))
 		b.WriteString("\n\n")
 	}
 
@@ -258,3 +380,111 @@ func (m ProjectActionModel) View() string {
 
 	return b.String()
 }
+
+// statusView renders the status panel
+func (m ProjectActionModel) statusView() string {
+	var b strings.Builder
+
+	headerStyle := lipgloss.NewStyle().Foreground(theme.Muted).Bold(true)
+	pathStyle := lipgloss.NewStyle().Foreground(theme.Accent).Bold(true)
+	labelStyle := lipgloss.NewStyle().Foreground(theme.Muted).Width(14)
+	mutedStyle := lipgloss.NewStyle().Foreground(theme.Muted)
+	goodStyle := lipgloss.NewStyle().Foreground(theme.Success)
+	warnStyle := lipgloss.NewStyle().Foreground(theme.Warning)
+
+	b.WriteString("\n")
+	b.WriteString("  " + headerStyle.Render("Status") + "  " + pathStyle.Render(m.projectName))
+	b.WriteString("\n\n")
+
+	if m.statusErr != nil {
+		errStyle := lipgloss.NewStyle().Foreground(theme.Error)
+		b.WriteString("  " + errStyle.Render("✗ "+m.statusErr.Error()))
+		b.WriteString("\n")
+		return b.String()
+	}
+	r := m.status
+	if r == nil {
+		b.WriteString("  " + mutedStyle.Render("Checking..."))
+		b.WriteString("\n")
+		return b.String()
+	}
+
+	row := func(label, value string) {
+		b.WriteString("  " + labelStyle.Render(label) + value + "\n")
+	}
+
+	// Git
+	var git string
+	switch {
+	case !r.Git.Repo:
+		git = warnStyle.Render("not a git repository")
+	case r.Git.Clean:
+		git = goodStyle.Render("✓ clean")
+	default:
+		git = warnStyle.Render(fmt.Sprintf("%d uncommitted changes", r.Git.Changes))
+	}
+	if r.Git.Branch != "" {
+		git += mutedStyle.Render(" on " + r.Git.Branch)
+	}
+	row("Git", git)
+	if r.Git.Repo {
+		if r.Git.Upstream == "" {
+			row("Sync", mutedStyle.Render("no upstream"))
+		} else {
+			row("Sync", fmt.Sprintf("↑%d ↓%d ", r.Git.Ahead, r.Git.Behind)+mutedStyle.Render(r.Git.Upstream))
+		}
+	}
+
+	// Last loop
+	loopText := mutedStyle.Render("none recorded")
+	if r.LastLoop != nil {
+		loopText = r.LastLoop.Date + " " + r.LastLoop.Summary
+		if len(loopText) > 48 {
+			loopText = loopText[:45] + "..."
+		}
+	}
+	if r.LoopActive {
+		loopText += warnStyle.Render(" (in progress)")
+	}
+	row("Last loop", loopText)
+
+	// Plan edit age
+	idle := "today"
+	if r.DaysIdle == 1 {
+		idle = "1 day ago"
+	} else if r.DaysIdle > 1 {
+		idle = itoa(r.DaysIdle) + " days ago"
+	}
+	row("Plan edited", idle)
+
+	// Outputs since the last commit
+	if len(r.NewOutputs) == 0 {
+		row("New outputs", mutedStyle.Render("none since the last commit"))
+	} else {
+		row("New outputs", goodStyle.Render(itoa(len(r.NewOutputs)))+" since the last commit")
+		for i, f := range r.NewOutputs {
+			if i == 3 {
+				b.WriteString("  " + labelStyle.Render("") + mutedStyle.Render(fmt.Sprintf("… %d more", len(r.NewOutputs)-3)) + "\n")
+				break
+			}
+			b.WriteString("  " + labelStyle.Render("") + mutedStyle.Render("+ "+f) + "\n")
+		}
+	}
+
+	row("Data", status.FormatSize(r.DataBytes)+mutedStyle.Render(" in "+status.DataDir))
+
+	if r.Template != "" {
+		switch r.PlanState {
+		case status.PlanAsWritten:
+			row("Template", r.Template+mutedStyle.Render(" · plan as irl wrote it"))
+		case status.PlanChanged:
+			row("Template", r.Template+mutedStyle.Render(" · plan edited since irl wrote it"))
+		default:
+			row("Template", r.Template+mutedStyle.Render(" · no record to compare"))
+		}
+	}
+
+	b.WriteString("\n")
+	b.WriteString("  " + mutedStyle.Render("r refresh · any other key to go back"))
+	return b.String()
+}
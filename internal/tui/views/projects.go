@@ -12,22 +12,24 @@ import (
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/drpedapati/irl-template/pkg/archive"
 	"github.com/drpedapati/irl-template/pkg/config"
 	"github.com/drpedapati/irl-template/pkg/editor"
+	"github.com/drpedapati/irl-template/pkg/projects"
 	"github.com/drpedapati/irl-template/pkg/theme"
 )
 
 // Project represents a discovered IRL project
 type Project struct {
-	Name     string
-	Path     string
-	Modified time.Time
+	projects.Project
 }
 
 // Implement list.Item interface
 func (p Project) Title() string       { return p.Name }
 func (p Project) Description() string { return smartDate(p.Modified) }
-func (p Project) FilterValue() string { return p.Name }
+func (p Project) FilterValue() string {
+	return strings.Join(append([]string{p.Name, p.Purpose, p.Status}, p.Tags...), " ")
+}
 
 // ProjectsModel displays discovered IRL projects
 type ProjectsModel struct {
@@ -46,6 +48,7 @@ type ProjectsModel struct {
 	tools       []AppInfo // Tools like Finder, Terminal, etc.
 	openMsg     string    // Message shown after opening
 	warningMsg  string    // Warning message (e.g., editor not found)
+	multiRoot   bool      // Show each project's workspace root
 
 	// Project detail view
 	viewing    bool
@@ -57,6 +60,14 @@ type ProjectsModel struct {
 	// Delete confirmation
 	confirmDelete bool
 	deleteTarget  string // Path of project to delete
+
+	// Archive confirmation
+	confirmArchive bool
+	archiveTarget  string // Path of project to archive
+
+	// Archived projects list
+	showingArchives bool
+	archivesView    ArchivesModel
 }
 
 const projectsVisibleItems = 10
@@ -67,6 +78,9 @@ type ProjectsLoadedMsg struct {
 	Editors  []AppInfo // Uses unified AppInfo from editors.go
 	Tools    []AppInfo // Tools like Finder, Terminal
 	Err      error
+
+	MultiRoot bool   // projects come from more than one workspace root
+	Warning   string // roots that couldn't be scanned
 }
 
 // NewProjectsModel creates a new projects view
@@ -92,71 +106,31 @@ func (m *ProjectsModel) SetSize(width, height int) {
 // ScanProjects returns a command that scans for IRL projects
 func (m *ProjectsModel) ScanProjects() tea.Cmd {
 	return func() tea.Msg {
-		baseDir := config.GetDefaultDirectory()
-		if baseDir == "" {
+		roots := config.GetWorkspaces()
+		if len(roots) == 0 {
 			return ProjectsLoadedMsg{Err: nil, Projects: []Project{}}
 		}
 
-		projects, err := scanForProjects(baseDir)
+		found, err := scanForProjects(roots)
 		editors := GetInstalledEditors() // Uses unified source
 		tools := GetInstalledTools()     // Tools like Finder, Terminal
-		return ProjectsLoadedMsg{Projects: projects, Editors: editors, Tools: tools, Err: err}
+		msg := ProjectsLoadedMsg{Projects: found, Editors: editors, Tools: tools, MultiRoot: len(roots) > 1}
+		if err != nil && len(found) > 0 {
+			msg.Warning = "Skipped: " + err.Error() // e.g. an unmounted drive
+		} else {
+			msg.Err = err
+		}
+		return msg
 	}
 }
 
-func scanForProjects(baseDir string) ([]Project, error) {
-	var projects []Project
-
-	entries, err := os.ReadDir(baseDir)
-	if err != nil {
-		return nil, err
+func scanForProjects(roots []config.Workspace) ([]Project, error) {
+	list, err := projects.ScanRoots(roots)
+	found := make([]Project, len(list))
+	for i, p := range list {
+		found[i] = Project{p}
 	}
-
-	for _, entry := range entries {
-		if !entry.IsDir() {
-			continue
-		}
-
-		// Skip IRL internal folders - these are not projects
-		name := entry.Name()
-		if name == "01-plans" || name == "02-data" || name == "03-outputs" || name == "04-logs" {
-			continue
-		}
-
-		projectDir := filepath.Join(baseDir, name)
-
-		// Check for main-plan.md in multiple locations
-		planPaths := []string{
-			filepath.Join(projectDir, "plans", "main-plan.md"),    // new standard
-			filepath.Join(projectDir, "main-plan.md"),             // legacy root level
-			filepath.Join(projectDir, "01-plans", "main-plan.md"), // legacy IRL structure
-		}
-
-		var planInfo os.FileInfo
-		for _, planPath := range planPaths {
-			if info, err := os.Stat(planPath); err == nil {
-				planInfo = info
-				break
-			}
-		}
-
-		if planInfo == nil {
-			continue // Not an IRL project
-		}
-
-		projects = append(projects, Project{
-			Name:     name,
-			Path:     projectDir,
-			Modified: planInfo.ModTime(),
-		})
-	}
-
-	// Sort by modified time, most recent first (default)
-	sort.Slice(projects, func(i, j int) bool {
-		return projects[i].Modified.After(projects[j].Modified)
-	})
-
-	return projects, nil
+	return found, err
 }
 
 // IsViewing returns true when viewing a project detail
@@ -164,11 +138,31 @@ func (m ProjectsModel) IsViewing() bool {
 	return m.viewing
 }
 
+// IsViewingHistory returns true when the plan history of a project is open
+func (m ProjectsModel) IsViewingHistory() bool {
+	return m.viewing && m.actionView.IsShowingHistory()
+}
+
+// IsViewingDecision returns true when the decision form of a project is open
+func (m ProjectsModel) IsViewingDecision() bool {
+	return m.viewing && m.actionView.IsShowingDecision()
+}
+
 // IsConfirmingDelete returns true when confirming a delete
 func (m ProjectsModel) IsConfirmingDelete() bool {
 	return m.confirmDelete
 }
 
+// IsConfirmingArchive returns true when confirming an archive
+func (m ProjectsModel) IsConfirmingArchive() bool {
+	return m.confirmArchive
+}
+
+// IsShowingArchives returns true when the archived projects list is open
+func (m ProjectsModel) IsShowingArchives() bool {
+	return m.showingArchives
+}
+
 // IsFilterMode returns true when in filter/typing mode
 func (m ProjectsModel) IsFilterMode() bool {
 	return m.filterMode
@@ -182,7 +176,7 @@ func (m *ProjectsModel) trashProject() error {
 
 	// Check if trash command is available
 	if _, err := exec.LookPath("trash"); err != nil {
-		return fmt.Errorf("'trash' command not found (brew install trash)")
+		return fmt.Errorf("'trash' command not found (brew install trash, or press a to archive instead)")
 	}
 
 	// Move to trash
@@ -194,7 +188,17 @@ func (m *ProjectsModel) trashProject() error {
 	return nil
 }
 
-// backupProject copies the selected project to a _backups directory with timestamp
+// archiveProject zips the archive target into its workspace's _archive
+// folder and removes it
+func (m *ProjectsModel) archiveProject() error {
+	if m.archiveTarget == "" {
+		return fmt.Errorf("no project selected")
+	}
+	_, _, err := archive.Archive(m.archiveTarget, archive.DirFor(m.archiveTarget))
+	return err
+}
+
+// backupProject zips the selected project into a _backups directory with timestamp
 func (m *ProjectsModel) backupProject() error {
 	projectPath := m.SelectedProject()
 	if projectPath == "" {
@@ -205,21 +209,10 @@ func (m *ProjectsModel) backupProject() error {
 	baseDir := config.GetDefaultDirectory()
 	projectName := filepath.Base(projectPath)
 
-	// Create _backups directory in base dir
-	backupsDir := filepath.Join(baseDir, "_backups")
-	if err := os.MkdirAll(backupsDir, 0755); err != nil {
-		return fmt.Errorf("failed to create backups directory: %w", err)
-	}
-
-	// Create backup name with timestamp: projectname_20260202_091500
-	timestamp := time.Now().Format("20060102_150405")
-	backupName := fmt.Sprintf("%s_%s", projectName, timestamp)
-	backupPath := filepath.Join(backupsDir, backupName)
-
-	// Copy directory using cp -r
-	cmd := exec.Command("cp", "-r", projectPath, backupPath)
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to copy project: %w", err)
+	// Backup name with timestamp: _backups/projectname_20260202_091500.zip
+	backupPath := filepath.Join(baseDir, "_backups", archive.FileName(projectName, time.Now()))
+	if _, err := archive.Create(projectPath, backupPath); err != nil {
+		return fmt.Errorf("failed to back up project: %w", err)
 	}
 
 	return nil
@@ -235,12 +228,22 @@ func (m ProjectsModel) Update(msg tea.Msg) (ProjectsModel, tea.Cmd) {
 		m.filtered = msg.Projects
 		m.editors = msg.Editors
 		m.tools = msg.Tools
+		m.multiRoot = msg.MultiRoot
+		m.warningMsg = msg.Warning
 		m.cursor = 0
 		m.scroll = 0
 		m.filterMode = true // Start in filter mode
 		m.filterInput.Focus()
 		return m, textinput.Blink
 
+	case ProjectStatusMsg:
+		if m.viewing {
+			var cmd tea.Cmd
+			m.actionView, cmd = m.actionView.Update(msg)
+			return m, cmd
+		}
+		return m, nil
+
 	case editor.EditorFinishedMsg:
 		// Terminal editor closed, TUI resumes - clear screen to remove artifacts
 		m.launchingEditor = false
@@ -272,6 +275,42 @@ func (m ProjectsModel) Update(msg tea.Msg) (ProjectsModel, tea.Cmd) {
 			return m, cmd
 		}
 
+		// Archived projects list
+		if m.showingArchives {
+			m.archivesView, _ = m.archivesView.Update(msg)
+			if m.archivesView.IsDone() {
+				m.showingArchives = false
+				if name := m.archivesView.Restored(); name != "" {
+					m.openMsg = "Restored " + name
+					m.warningMsg = ""
+					return m, m.ScanProjects()
+				}
+			}
+			return m, nil
+		}
+
+		// Handle archive confirmation
+		if m.confirmArchive {
+			switch msg.String() {
+			case "y", "Y":
+				err := m.archiveProject()
+				name := filepath.Base(m.archiveTarget)
+				m.confirmArchive = false
+				m.archiveTarget = ""
+				if err != nil {
+					m.warningMsg = err.Error()
+					return m, nil
+				}
+				m.openMsg = "Archived " + name + " (R to restore)"
+				return m, m.ScanProjects()
+			case "n", "N", "esc":
+				m.confirmArchive = false
+				m.archiveTarget = ""
+				return m, nil
+			}
+			return m, nil
+		}
+
 		// Handle delete confirmation
 		if m.confirmDelete {
 			switch msg.String() {
@@ -413,6 +452,22 @@ func (m ProjectsModel) Update(msg tea.Msg) (ProjectsModel, tea.Cmd) {
 				}
 			}
 			return m, nil
+		case "a":
+			// Archive project (with confirmation)
+			if m.SelectedProject() != "" {
+				m.confirmArchive = true
+				m.archiveTarget = m.SelectedProject()
+				m.warningMsg = ""
+				m.openMsg = ""
+			}
+			return m, nil
+		case "R":
+			// Show archived projects
+			m.showingArchives = true
+			m.archivesView = NewArchivesModel()
+			m.warningMsg = ""
+			m.openMsg = ""
+			return m, nil
 		case "x":
 			// Delete project (with confirmation)
 			if m.SelectedProject() != "" {
@@ -500,7 +555,7 @@ func (m *ProjectsModel) applyFilter() {
 	} else {
 		m.filtered = []Project{}
 		for _, p := range m.projects {
-			if strings.Contains(strings.ToLower(p.Name), query) {
+			if strings.Contains(strings.ToLower(p.FilterValue()), query) {
 				m.filtered = append(m.filtered, p)
 			}
 		}
@@ -570,6 +625,9 @@ func (m ProjectsModel) View() string {
 	if m.viewing {
 		return m.actionView.View()
 	}
+	if m.showingArchives {
+		return m.archivesView.View()
+	}
 
 	var b strings.Builder
 
@@ -578,6 +636,7 @@ func (m ProjectsModel) View() string {
 	selectedStyle := lipgloss.NewStyle().Foreground(theme.Accent).Bold(true)
 	normalStyle := lipgloss.NewStyle()
 	dateStyle := lipgloss.NewStyle().Foreground(theme.Muted)
+	statusStyle := lipgloss.NewStyle().Foreground(theme.Warning)
 
 	if m.err != nil {
 		errStyle := lipgloss.NewStyle().Foreground(theme.Error).MarginLeft(2)
@@ -624,6 +683,11 @@ func (m ProjectsModel) View() string {
 		projectName := filepath.Base(m.deleteTarget)
 		b.WriteString("  " + warningStyle.Render("Delete \""+projectName+"\"? (y/n)"))
 		b.WriteString("\n\n")
+	} else if m.confirmArchive {
+		warningStyle := lipgloss.NewStyle().Foreground(theme.Warning).Bold(true)
+		projectName := filepath.Base(m.archiveTarget)
+		b.WriteString("  " + warningStyle.Render("Archive \""+projectName+"\" to "+archive.Dir+"/ and remove it? (y/n)"))
+		b.WriteString("\n\n")
 	} else if m.warningMsg != "" {
 		// Warning message (inline, above list)
 		warningStyle := lipgloss.NewStyle().Foreground(theme.Warning)
@@ -691,7 +755,19 @@ func (m ProjectsModel) View() string {
 		// Smart date
 		dateStr := smartDate(p.Modified)
 
-		b.WriteString("  " + cursor + " " + nameStyle.Render(namePadded) + " " + dateStyleLocal.Render(dateStr))
+		// Root label, status and tags from project metadata
+		extra := ""
+		if m.multiRoot && p.Root != "" {
+			extra += " " + mutedStyle.Render("["+p.Root+"]")
+		}
+		if p.Status != "" && p.Status != projects.StatusActive {
+			extra += " " + statusStyle.Render(p.Status)
+		}
+		if len(p.Tags) > 0 {
+			extra += " " + mutedStyle.Render("#"+strings.Join(p.Tags, " #"))
+		}
+
+		b.WriteString("  " + cursor + " " + nameStyle.Render(namePadded) + " " + dateStyleLocal.Render(dateStr) + extra)
 		b.WriteString("\n")
 	}
 
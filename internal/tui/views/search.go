@@ -0,0 +1,311 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/drpedapati/irl-template/pkg/config"
+	"github.com/drpedapati/irl-template/pkg/editor"
+	"github.com/drpedapati/irl-template/pkg/projects"
+	"github.com/drpedapati/irl-template/pkg/search"
+	"github.com/drpedapati/irl-template/pkg/theme"
+)
+
+// SearchModel searches plans and logs across projects
+type SearchModel struct {
+	input     textinput.Model
+	inputMode bool // true = typing a query, false = selecting results
+	searching bool
+	query     string // query of the shown results
+	results   []search.Result
+	err       error
+	cursor    int
+	scroll    int
+	width     int
+	height    int
+
+	// Project detail view
+	viewing    bool
+	actionView ProjectActionModel
+}
+
+const searchVisibleItems = 6
+
+// SearchResultsMsg is sent when a search completes
+type SearchResultsMsg struct {
+	Query   string
+	Results []search.Result
+	Err     error
+}
+
+// NewSearchModel creates a new search view
+func NewSearchModel() SearchModel {
+	ti := textinput.New()
+	ti.Placeholder = "Search plans, logs and metadata..."
+	ti.Width = 50
+	ti.Focus()
+
+	return SearchModel{
+		input:     ti,
+		inputMode: true,
+	}
+}
+
+// SetSize sets the view dimensions
+func (m *SearchModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
+
+// Init starts the cursor blinking
+func (m SearchModel) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+// IsInputMode returns true while typing a query
+func (m SearchModel) IsInputMode() bool {
+	return m.inputMode && !m.viewing
+}
+
+// IsViewing returns true when viewing a project detail
+func (m SearchModel) IsViewing() bool {
+	return m.viewing
+}
+
+// HasQuery returns true if the query input has text
+func (m SearchModel) HasQuery() bool {
+	return m.input.Value() != ""
+}
+
+// runSearch scans the workspace roots and searches the projects found
+func runSearch(query string) tea.Cmd {
+	return func() tea.Msg {
+		roots := config.GetWorkspaces()
+		if len(roots) == 0 {
+			return SearchResultsMsg{Query: query}
+		}
+		list, err := projects.ScanRoots(roots)
+		if err != nil && len(list) == 0 {
+			return SearchResultsMsg{Query: query, Err: err}
+		}
+		results, err := search.Search(list, query, search.Options{MaxMatches: 1})
+		return SearchResultsMsg{Query: query, Results: results, Err: err}
+	}
+}
+
+// Update handles messages
+func (m SearchModel) Update(msg tea.Msg) (SearchModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case SearchResultsMsg:
+		// Ignore results of an older query
+		if msg.Query != strings.TrimSpace(m.input.Value()) {
+			return m, nil
+		}
+		m.searching = false
+		m.query = msg.Query
+		m.results = msg.Results
+		m.err = msg.Err
+		m.cursor = 0
+		m.scroll = 0
+		return m, nil
+
+	case editor.EditorFinishedMsg, editor.EditorOpenedMsg, ProjectStatusMsg:
+		if m.viewing {
+			var cmd tea.Cmd
+			m.actionView, cmd = m.actionView.Update(msg)
+			return m, cmd
+		}
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.viewing {
+			var cmd tea.Cmd
+			m.actionView, cmd = m.actionView.Update(msg)
+			if m.actionView.IsDone() {
+				m.viewing = false
+			}
+			return m, cmd
+		}
+
+		key := msg.String()
+
+		// === INPUT MODE: typing a query ===
+		if m.inputMode {
+			switch key {
+			case "enter":
+				query := strings.TrimSpace(m.input.Value())
+				if query == "" {
+					return m, nil
+				}
+				m.searching = true
+				m.err = nil
+				return m, runSearch(query)
+			case "down":
+				if len(m.results) > 0 {
+					m.inputMode = false
+					m.input.Blur()
+					m.cursor = 0
+					m.scroll = 0
+				}
+				return m, nil
+			case "esc":
+				m.input.SetValue("")
+				m.results = nil
+				m.query = ""
+				m.err = nil
+				return m, nil
+			}
+			var cmd tea.Cmd
+			m.input, cmd = m.input.Update(msg)
+			return m, cmd
+		}
+
+		// === SELECTION MODE: navigating results ===
+		switch key {
+		case "up":
+			if m.cursor > 0 {
+				m.cursor--
+				if m.cursor < m.scroll {
+					m.scroll = m.cursor
+				}
+			} else {
+				m.inputMode = true
+				m.input.Focus()
+			}
+		case "down":
+			if m.cursor < len(m.results)-1 {
+				m.cursor++
+				if m.cursor >= m.scroll+searchVisibleItems {
+					m.scroll = m.cursor - searchVisibleItems + 1
+				}
+			}
+		case "right", "enter":
+			if m.cursor < len(m.results) {
+				m.viewing = true
+				m.actionView = NewProjectActionModel(m.results[m.cursor].Path, false)
+			}
+		case "left", "esc":
+			m.inputMode = true
+			m.input.Focus()
+		}
+	}
+
+	return m, nil
+}
+
+// View renders the search view
+func (m SearchModel) View() string {
+	if m.viewing {
+		return m.actionView.View()
+	}
+
+	var b strings.Builder
+
+	mutedStyle := lipgloss.NewStyle().Foreground(theme.Muted)
+	accentStyle := lipgloss.NewStyle().Foreground(theme.Accent).Bold(true)
+	selectedStyle := lipgloss.NewStyle().Foreground(theme.Accent).Bold(true)
+	matchStyle := lipgloss.NewStyle().Foreground(theme.Warning).Bold(true)
+
+	b.WriteString("\n")
+	b.WriteString("  " + m.input.View())
+	if m.inputMode {
+		b.WriteString("  " + mutedStyle.Render("enter to search"))
+	} else {
+		b.WriteString("  " + lipgloss.NewStyle().Foreground(theme.Accent).Render("● selecting"))
+	}
+	b.WriteString("\n\n")
+
+	switch {
+	case m.searching:
+		b.WriteString(mutedStyle.Render("  Searching..."))
+		b.WriteString("\n")
+		return b.String()
+	case m.err != nil:
+		errStyle := lipgloss.NewStyle().Foreground(theme.Error)
+		b.WriteString("  " + errStyle.Render("Error: "+m.err.Error()))
+		b.WriteString("\n")
+		return b.String()
+	case m.query == "":
+		b.WriteString(mutedStyle.Render("  Words match anywhere in a project; use \"quotes\" for a phrase"))
+		b.WriteString("\n")
+		return b.String()
+	case len(m.results) == 0:
+		b.WriteString(mutedStyle.Render("  No projects match \"" + m.query + "\""))
+		b.WriteString("\n")
+		return b.String()
+	}
+
+	endIdx := m.scroll + searchVisibleItems
+	if endIdx > len(m.results) {
+		endIdx = len(m.results)
+	}
+
+	// Snippets are indented under the project name
+	snippetWidth := m.width - 8
+	if snippetWidth < 20 {
+		snippetWidth = 20
+	}
+
+	for i := m.scroll; i < endIdx; i++ {
+		r := m.results[i]
+		cursor := " "
+		nameStyle := lipgloss.NewStyle()
+		if !m.inputMode && i == m.cursor {
+			cursor = accentStyle.Render(">")
+			nameStyle = selectedStyle
+		}
+
+		count := fmt.Sprintf("%d matches", r.Total)
+		if r.Total == 1 {
+			count = "1 match"
+		}
+		line := "  " + cursor + " " + nameStyle.Render(r.Project) + "  " + mutedStyle.Render(count)
+
+		// Best match: where it is on the name line, the text below
+		if len(r.Matches) > 0 {
+			mt := r.Matches[0]
+			loc := mt.File
+			if mt.Kind != search.KindMeta {
+				loc = fmt.Sprintf("%s:%d", mt.File, mt.Line)
+			}
+			if lipgloss.Width(line)+3+len(loc) <= m.width {
+				line += mutedStyle.Render(" · " + loc)
+			}
+			line += "\n    " + search.Highlight(clipMatch(mt, snippetWidth), func(s string) string { return matchStyle.Render(s) })
+		}
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+
+	if len(m.results) > searchVisibleItems {
+		b.WriteString(mutedStyle.Render("    " + itoa(m.scroll+1) + "-" + itoa(endIdx) + " of " + itoa(len(m.results))))
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+// clipMatch shortens a match's text to width runes, dropping ranges past the end
+func clipMatch(mt search.Match, width int) search.Match {
+	if utf8.RuneCountInString(mt.Text) <= width {
+		return mt
+	}
+	end := 0
+	for n := 0; n < width-1; n++ {
+		_, size := utf8.DecodeRuneInString(mt.Text[end:])
+		end += size
+	}
+	var ranges [][2]int
+	for _, r := range mt.Ranges {
+		if r[1] <= end {
+			ranges = append(ranges, r)
+		}
+	}
+	mt.Text = mt.Text[:end] + "…"
+	mt.Ranges = ranges
+	return mt
+}
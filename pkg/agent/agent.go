@@ -0,0 +1,286 @@
+// Package agent runs AI coding assistants headlessly against a project plan.
+package agent
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/drpedapati/irl-template/pkg/config"
+	"github.com/drpedapati/irl-template/pkg/plan"
+)
+
+// RunsDir is where transcripts are written, relative to the project
+const RunsDir = "04-logs/runs"
+
+// DefaultCommands are the built-in invocation templates for the agents
+// doctor.AITools knows about. Placeholders:
+//
+//	{prompt}      the generated prompt
+//	{prompt_file} absolute path to a file holding the prompt
+//	{plan}        absolute path to the plan file
+//	{project}     absolute path to the project directory
+//
+// If a template has neither {prompt} nor {prompt_file}, the prompt is
+// written to the agent's stdin. A large plan can exceed the system's limit
+// on argument length, so prefer stdin or {prompt_file} where the agent
+// supports them.
+var DefaultCommands = map[string]string{
+	"claude":  "claude -p",
+	"codex":   "codex exec -",
+	"copilot": "copilot -p {prompt}",
+}
+
+// maxArgLen is the longest single argument Linux accepts (MAX_ARG_STRLEN)
+const maxArgLen = 128 * 1024
+
+// Command returns the invocation template for an agent, preferring the
+// user's config over the built-in defaults
+func Command(name string) (string, error) {
+	if c := config.GetAgentCommand(name); c != "" {
+		return c, nil
+	}
+	if c, ok := DefaultCommands[name]; ok {
+		return c, nil
+	}
+	return "", fmt.Errorf("unknown agent %q (configure with: irl config --agent %s=\"cmd {prompt}\")", name, name)
+}
+
+// Names returns all known agent names: built-ins plus configured ones
+func Names() []string {
+	seen := map[string]bool{}
+	for n := range DefaultCommands {
+		seen[n] = true
+	}
+	if cfg, err := config.Load(); err == nil {
+		for n := range cfg.Agents {
+			seen[n] = true
+		}
+	}
+	var names []string
+	for n := range seen {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// BuildPrompt returns the prompt for one loop iteration of the plan
+func BuildPrompt(planPath string) (string, error) {
+	data, err := os.ReadFile(planPath)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	b.WriteString("Execute one iteration of the Idempotent Research Loop defined in ")
+	b.WriteString(planPath)
+	b.WriteString(".\n")
+	b.WriteString("Follow the Before Each Loop checklist, do the work in the Instruction Loop, ")
+	b.WriteString("then complete the After Each Loop steps. Only edit the plan where it permits.\n\n")
+	b.WriteString("--- PLAN ---\n")
+	b.Write(data)
+	return b.String(), nil
+}
+
+// BuildArgs splits a command template into arguments and substitutes
+// placeholders. Quoting with '...' or "..." groups words; substituted
+// values are never split.
+func BuildArgs(template string, vars map[string]string) ([]string, error) {
+	words, err := splitWords(template)
+	if err != nil {
+		return nil, err
+	}
+	if len(words) == 0 {
+		return nil, errors.New("empty agent command")
+	}
+	// One pass, so a value containing a placeholder is left alone
+	pairs := make([]string, 0, 2*len(vars))
+	for k, v := range vars {
+		pairs = append(pairs, "{"+k+"}", v)
+	}
+	r := strings.NewReplacer(pairs...)
+	args := make([]string, len(words))
+	for i, w := range words {
+		args[i] = r.Replace(w)
+	}
+	return args, nil
+}
+
+// splitWords splits s on whitespace, honouring single and double quotes
+func splitWords(s string) ([]string, error) {
+	var words []string
+	var cur strings.Builder
+	inWord := false
+	var quote rune
+
+	for _, r := range s {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				cur.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inWord = true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inWord {
+				words = append(words, cur.String())
+				cur.Reset()
+				inWord = false
+			}
+		default:
+			cur.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 {
+		return nil, errors.New("unterminated quote in agent command")
+	}
+	if inWord {
+		words = append(words, cur.String())
+	}
+	return words, nil
+}
+
+// Options configures a run
+type Options struct {
+	Agent      string
+	ProjectDir string
+	Stdout     io.Writer // live output; defaults to os.Stdout
+	Stderr     io.Writer // live output; defaults to os.Stderr
+}
+
+// Result describes a finished run
+type Result struct {
+	Agent      string
+	Args       []string
+	Transcript string // path to the transcript log
+	ExitCode   int
+	Duration   time.Duration
+}
+
+// Run launches the agent in the project directory, streaming its output
+// and capturing it in a timestamped transcript under 04-logs/runs/.
+// A non-zero exit from the agent is reported in Result, not as an error.
+func Run(opts Options) (*Result, error) {
+	tmpl, err := Command(opts.Agent)
+	if err != nil {
+		return nil, err
+	}
+
+	projectDir, err := filepath.Abs(opts.ProjectDir)
+	if err != nil {
+		return nil, err
+	}
+	planPath, err := plan.Find(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	prompt, err := BuildPrompt(planPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plan: %w", err)
+	}
+
+	runsDir := filepath.Join(projectDir, filepath.FromSlash(RunsDir))
+	start := time.Now()
+	stem := filepath.Join(runsDir, start.Format("20060102-150405")+"-"+opts.Agent)
+	promptPath := stem + ".prompt.md"
+
+	args, err := BuildArgs(tmpl, map[string]string{
+		"prompt":      prompt,
+		"prompt_file": promptPath,
+		"plan":        planPath,
+		"project":     projectDir,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range args {
+		if len(a) > maxArgLen {
+			return nil, fmt.Errorf("the prompt is too long to pass as an argument; send it on stdin by leaving {prompt} out of the %s command, or use {prompt_file}", opts.Agent)
+		}
+	}
+
+	if err := os.MkdirAll(runsDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", RunsDir, err)
+	}
+	usesFile := strings.Contains(tmpl, "{prompt_file}")
+	if usesFile {
+		if err := os.WriteFile(promptPath, []byte(prompt), 0644); err != nil {
+			return nil, fmt.Errorf("failed to write prompt: %w", err)
+		}
+	}
+
+	logPath := stem + ".log"
+	logFile, err := os.Create(logPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transcript: %w", err)
+	}
+	defer logFile.Close()
+
+	fmt.Fprintf(logFile, "# irl run\n")
+	fmt.Fprintf(logFile, "agent:    %s\n", opts.Agent)
+	fmt.Fprintf(logFile, "command:  %s\n", tmpl)
+	fmt.Fprintf(logFile, "project:  %s\n", projectDir)
+	fmt.Fprintf(logFile, "plan:     %s\n", planPath)
+	fmt.Fprintf(logFile, "started:  %s\n\n", start.Format(time.RFC3339))
+	fmt.Fprintf(logFile, "--- prompt ---\n%s\n--- output ---\n", strings.TrimRight(prompt, "\n"))
+
+	stdout, stderr := opts.Stdout, opts.Stderr
+	if stdout == nil {
+		stdout = os.Stdout
+	}
+	if stderr == nil {
+		stderr = os.Stderr
+	}
+	// stdout and stderr are copied concurrently, so serialize transcript writes
+	transcript := &lockedWriter{w: logFile}
+
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Dir = projectDir
+	cmd.Stdout = io.MultiWriter(stdout, transcript)
+	cmd.Stderr = io.MultiWriter(stderr, transcript)
+	if !usesFile && !strings.Contains(tmpl, "{prompt}") {
+		cmd.Stdin = strings.NewReader(prompt)
+	}
+
+	res := &Result{Agent: opts.Agent, Args: args, Transcript: logPath}
+	runErr := cmd.Run()
+	res.Duration = time.Since(start)
+
+	var exitErr *exec.ExitError
+	switch {
+	case runErr == nil:
+	case errors.As(runErr, &exitErr):
+		res.ExitCode = exitErr.ExitCode()
+	default:
+		fmt.Fprintf(logFile, "\nerror: %v\n", runErr)
+		return res, fmt.Errorf("failed to start %s: %w", args[0], runErr)
+	}
+
+	fmt.Fprintf(logFile, "\nfinished: %s\n", time.Now().Format(time.RFC3339))
+	fmt.Fprintf(logFile, "exit:     %d\n", res.ExitCode)
+	fmt.Fprintf(logFile, "duration: %s\n", res.Duration.Round(time.Millisecond))
+
+	return res, nil
+}
+
+type lockedWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (l *lockedWriter) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.w.Write(p)
+}
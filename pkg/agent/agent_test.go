@@ -0,0 +1,38 @@
+package agent
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBuildArgs(t *testing.T) {
+	vars := map[string]string{
+		"prompt":      "fix {plan} and {project}",
+		"prompt_file": "/p/04-logs/runs/x.prompt.md",
+		"plan":        "/p/plans/main-plan.md",
+		"project":     "/p",
+	}
+	tests := []struct {
+		tmpl string
+		want []string
+	}{
+		{"claude -p {prompt}", []string{"claude", "-p", "fix {plan} and {project}"}},
+		{`agent --file {prompt_file} --in "{project}/out dir"`, []string{"agent", "--file", "/p/04-logs/runs/x.prompt.md", "--in", "/p/out dir"}},
+		{"stub.sh '{plan}' {unknown}", []string{"stub.sh", "/p/plans/main-plan.md", "{unknown}"}},
+	}
+	for _, tt := range tests {
+		got, err := BuildArgs(tt.tmpl, vars)
+		if err != nil {
+			t.Fatalf("BuildArgs(%q): %v", tt.tmpl, err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("BuildArgs(%q) = %q, want %q", tt.tmpl, got, tt.want)
+		}
+	}
+
+	for _, tmpl := range []string{"", "   ", `claude -p "{prompt}`} {
+		if _, err := BuildArgs(tmpl, vars); err == nil {
+			t.Errorf("BuildArgs(%q) succeeded", tmpl)
+		}
+	}
+}
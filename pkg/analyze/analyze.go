@@ -0,0 +1,402 @@
+// Package analyze inspects a folder being adopted and tailors its plan
+// to what is already there.
+package analyze
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/drpedapati/irl-template/pkg/plan"
+	"github.com/drpedapati/irl-template/pkg/status"
+)
+
+// maxFiles bounds how many files Analyze looks at, so adopting a huge
+// dataset stays quick. Counts are partial beyond it.
+const maxFiles = 20000
+
+// Where IRL projects keep data and outputs
+const (
+	RawDataDir     = "02-data/raw"
+	DerivedDataDir = "02-data/derived"
+	OutputsDir     = "03-outputs"
+)
+
+// conventionDirs maps common top-level folder names to the IRL folder
+// they correspond to
+var conventionDirs = map[string]string{
+	"data":      RawDataDir,
+	"raw":       RawDataDir,
+	"raw_data":  RawDataDir,
+	"rawdata":   RawDataDir,
+	"raw-data":  RawDataDir,
+	"input":     RawDataDir,
+	"inputs":    RawDataDir,
+	"derived":   DerivedDataDir,
+	"processed": DerivedDataDir,
+	"clean":     DerivedDataDir,
+	"interim":   DerivedDataDir,
+	"output":    OutputsDir,
+	"outputs":   OutputsDir,
+	"results":   OutputsDir,
+	"figures":   OutputsDir,
+	"figs":      OutputsDir,
+	"plots":     OutputsDir,
+	"reports":   OutputsDir,
+	"tables":    OutputsDir,
+}
+
+// dataExts are extensions of data files
+var dataExts = map[string]bool{
+	".csv": true, ".tsv": true, ".xlsx": true, ".xls": true,
+	".parquet": true, ".feather": true, ".arrow": true,
+	".rds": true, ".rdata": true, ".rda": true,
+	".sav": true, ".dta": true, ".sas7bdat": true,
+	".h5": true, ".hdf5": true, ".mat": true, ".npy": true, ".npz": true,
+	".nii": true, ".edf": true, ".bdf": true, ".set": true, ".fif": true,
+	".sqlite": true, ".db": true,
+}
+
+// skipDirs are folders that hold environments, caches or VCS data
+var skipDirs = map[string]bool{
+	".git": true, ".venv": true, "venv": true, "node_modules": true,
+	"__pycache__": true, "renv": true, ".quarto": true, ".Rproj.user": true,
+}
+
+// Report is what Analyze found in a folder. Paths are slash-separated and
+// relative to the folder.
+type Report struct {
+	RProject     string            // .Rproj file
+	Renv         bool              // renv.lock
+	RScripts     int               // .R files
+	PyProject    bool              // pyproject.toml
+	Requirements bool              // requirements.txt
+	CondaEnv     string            // environment.yml or environment.yaml
+	PyScripts    int               // .py files
+	Quarto       bool              // _quarto.yml
+	QuartoDocs   []string          // .qmd files
+	Notebooks    []string          // .ipynb and .Rmd files
+	DataFiles    int               // files with data extensions
+	DataBytes    int64             // their total size
+	Docs         []string          // README files and docs/
+	Folders      map[string]string // existing folder -> IRL convention
+	Partial      bool              // stopped after maxFiles
+}
+
+// Analyze inspects dir, skipping environments and caches
+func Analyze(dir string) (*Report, error) {
+	r := &Report{Folders: map[string]string{}}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		name := e.Name()
+		lower := strings.ToLower(name)
+		switch {
+		case e.IsDir():
+			if conv, ok := conventionDirs[lower]; ok {
+				r.Folders[name] = conv
+			}
+			if lower == "docs" || lower == "doc" {
+				r.Docs = append(r.Docs, name+"/")
+			}
+		case strings.HasSuffix(lower, ".rproj") && r.RProject == "":
+			r.RProject = name
+		case name == "renv.lock":
+			r.Renv = true
+		case name == "pyproject.toml":
+			r.PyProject = true
+		case name == "requirements.txt":
+			r.Requirements = true
+		case name == "environment.yml" || name == "environment.yaml":
+			r.CondaEnv = name
+		case name == "_quarto.yml" || name == "_quarto.yaml":
+			r.Quarto = true
+		case strings.HasPrefix(lower, "readme"):
+			r.Docs = append(r.Docs, name)
+		}
+	}
+
+	files := 0
+	err = filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if p == dir {
+				return err
+			}
+			return nil // unreadable corners don't spoil the report
+		}
+		if d.IsDir() {
+			if p != dir && (skipDirs[d.Name()] || strings.HasPrefix(d.Name(), ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if files++; files > maxFiles {
+			r.Partial = true
+			return filepath.SkipAll
+		}
+
+		rel, _ := filepath.Rel(dir, p)
+		rel = filepath.ToSlash(rel)
+		ext := strings.ToLower(path.Ext(rel))
+		switch {
+		case ext == ".r":
+			r.RScripts++
+		case ext == ".py":
+			r.PyScripts++
+		case ext == ".qmd":
+			r.QuartoDocs = append(r.QuartoDocs, rel)
+		case ext == ".ipynb" || ext == ".rmd":
+			r.Notebooks = append(r.Notebooks, rel)
+		case dataExts[ext]:
+			r.DataFiles++
+			if info, err := d.Info(); err == nil {
+				r.DataBytes += info.Size()
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(r.QuartoDocs)
+	sort.Strings(r.Notebooks)
+	return r, nil
+}
+
+// Empty reports whether nothing worth mentioning was found
+func (r *Report) Empty() bool {
+	return len(r.Facts()) == 0 && len(r.Folders) == 0
+}
+
+// Facts describes what was found, one markdown line each
+func (r *Report) Facts() []string {
+	var facts []string
+	if r.RProject != "" || r.Renv || r.RScripts > 0 {
+		fact := "R project"
+		if r.RProject != "" {
+			fact += fmt.Sprintf(" (`%s`)", r.RProject)
+		}
+		if r.RScripts > 0 {
+			fact += fmt.Sprintf(", %s", plural(r.RScripts, "R script"))
+		}
+		if r.Renv {
+			fact += "; packages are locked in `renv.lock`"
+		}
+		facts = append(facts, fact)
+	}
+	if r.PyProject || r.Requirements || r.CondaEnv != "" || r.PyScripts > 0 {
+		var specs []string
+		if r.PyProject {
+			specs = append(specs, "`pyproject.toml`")
+		}
+		if r.Requirements {
+			specs = append(specs, "`requirements.txt`")
+		}
+		if r.CondaEnv != "" {
+			specs = append(specs, "`"+r.CondaEnv+"`")
+		}
+		fact := "Python project"
+		if r.PyScripts > 0 {
+			fact += ", " + plural(r.PyScripts, "Python script")
+		}
+		if len(specs) > 0 {
+			fact += "; dependencies in " + strings.Join(specs, " and ")
+		}
+		facts = append(facts, fact)
+	}
+	if r.Quarto || len(r.QuartoDocs) > 0 {
+		fact := "Quarto"
+		if r.Quarto {
+			fact += " project (`_quarto.yml`)"
+		}
+		if len(r.QuartoDocs) > 0 {
+			fact += ", " + listed(r.QuartoDocs)
+		}
+		facts = append(facts, fact)
+	}
+	if len(r.Notebooks) > 0 {
+		facts = append(facts, "Notebooks: "+listed(r.Notebooks))
+	}
+	if r.DataFiles > 0 {
+		facts = append(facts, fmt.Sprintf("%s (%s)", plural(r.DataFiles, "data file"), status.FormatSize(r.DataBytes)))
+	}
+	if len(r.Docs) > 0 {
+		facts = append(facts, "Existing docs: "+code(r.Docs))
+	}
+	if r.Partial {
+		facts = append(facts, fmt.Sprintf("Counts cover the first %d files only", maxFiles))
+	}
+	return facts
+}
+
+// FolderLines describes how existing folders map to IRL conventions
+func (r *Report) FolderLines() []string {
+	names := make([]string, 0, len(r.Folders))
+	for name := range r.Folders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var lines []string
+	for _, name := range names {
+		switch conv := r.Folders[name]; conv {
+		case RawDataDir:
+			// Spelled out so lint doesn't take it for a path to check
+			lines = append(lines, fmt.Sprintf("`%s/` holds raw data, in place of IRL's `02-data` raw folder: never modify it", name))
+		case DerivedDataDir:
+			lines = append(lines, fmt.Sprintf("`%s/` holds derived data: treat it as `%s`", name, conv))
+		default:
+			lines = append(lines, fmt.Sprintf("`%s/` holds outputs: treat it as `%s`", name, conv))
+		}
+	}
+	return lines
+}
+
+// setupSteps are First Time Setup instructions for the detected tooling
+func (r *Report) setupSteps() []string {
+	var steps []string
+	if r.Renv {
+		steps = append(steps, "Restore R packages with `renv::restore()`")
+	}
+	switch {
+	case r.CondaEnv != "":
+		steps = append(steps, fmt.Sprintf("Create the conda environment with `conda env create -f %s`", r.CondaEnv))
+	case r.PyProject:
+		steps = append(steps, "Create a virtual environment and install the project with `pip install -e .`")
+	case r.Requirements:
+		steps = append(steps, "Create a virtual environment and run `pip install -r requirements.txt`")
+	}
+	if r.Quarto || len(r.QuartoDocs) > 0 {
+		steps = append(steps, "Check that `quarto render` works")
+	}
+	return steps
+}
+
+// loopSteps are Instruction Loop notes for the detected layout
+func (r *Report) loopSteps() []string {
+	var steps []string
+	for _, doc := range r.Docs {
+		if strings.HasPrefix(strings.ToLower(doc), "readme") {
+			steps = append(steps, fmt.Sprintf("Read `%s` for background before changing anything", doc))
+			break
+		}
+	}
+	if r.Renv {
+		steps = append(steps, "Keep `renv.lock` in sync with `renv::snapshot()` when packages change")
+	}
+	if r.Requirements && !r.PyProject {
+		steps = append(steps, "Add new Python dependencies to `requirements.txt`")
+	}
+	for _, name := range sortedFolders(r.Folders, RawDataDir) {
+		steps = append(steps, fmt.Sprintf("Read raw data from `%s/` only; write anything derived elsewhere", name))
+	}
+	for _, name := range sortedFolders(r.Folders, OutputsDir) {
+		steps = append(steps, fmt.Sprintf("Write new outputs to `%s/`", name))
+	}
+	return steps
+}
+
+// Apply writes the findings into the plan's First Time Setup and
+// Instruction Loop author areas. Sections without an AUTHOR AREA, and
+// author areas that already have content, are left alone. It reports
+// whether the plan changed.
+func (r *Report) Apply(p *plan.Plan) bool {
+	changed := false
+
+	var setup []string
+	if facts := r.Facts(); len(facts) > 0 {
+		setup = append(setup, "Detected when this folder was adopted:", "")
+		setup = append(setup, bullets(facts)...)
+	}
+	if folders := r.FolderLines(); len(folders) > 0 {
+		setup = append(setup, "", "Existing folders map to IRL conventions:", "")
+		setup = append(setup, bullets(folders)...)
+	}
+	if steps := r.setupSteps(); len(steps) > 0 {
+		setup = append(setup, "", "Setup:", "")
+		setup = append(setup, bullets(steps)...)
+	}
+	if fillAuthorArea(p.Section(plan.SectionFirstTimeSetup), setup) {
+		changed = true
+	}
+	if fillAuthorArea(p.Section(plan.SectionInstructionLoop), bullets(r.loopSteps())) {
+		changed = true
+	}
+	return changed
+}
+
+// fillAuthorArea sets an empty author area to lines
+func fillAuthorArea(s *plan.Section, lines []string) bool {
+	if s == nil || len(lines) == 0 {
+		return false
+	}
+	marker, content := s.AuthorArea()
+	if marker == nil || strings.TrimSpace(content) != "" {
+		return false
+	}
+	return s.SetAuthorContent("\n" + strings.Join(lines, "\n") + "\n\n")
+}
+
+// TailorPlan analyzes dir and writes the findings into plan content. The
+// content is returned unchanged when nothing was found or it has nowhere
+// to go.
+func TailorPlan(content, dir string) (string, *Report, error) {
+	r, err := Analyze(dir)
+	if err != nil {
+		return content, nil, err
+	}
+	p := plan.Parse(content)
+	if !r.Apply(p) {
+		return content, r, nil
+	}
+	return p.String(), r, nil
+}
+
+func sortedFolders(folders map[string]string, conv string) []string {
+	var names []string
+	for name, c := range folders {
+		if c == conv {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func bullets(lines []string) []string {
+	out := make([]string, len(lines))
+	for i, l := range lines {
+		out[i] = "- " + l
+	}
+	return out
+}
+
+func plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// listed names up to three paths, then counts the rest
+func listed(paths []string) string {
+	if len(paths) <= 3 {
+		return code(paths)
+	}
+	return fmt.Sprintf("%s and %d more", code(paths[:3]), len(paths)-3)
+}
+
+func code(paths []string) string {
+	quoted := make([]string, len(paths))
+	for i, p := range paths {
+		quoted[i] = "`" + p + "`"
+	}
+	return strings.Join(quoted, ", ")
+}
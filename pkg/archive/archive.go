@@ -0,0 +1,576 @@
+// Package archive packs projects into zip files with a manifest of SHA-256
+// sums and restores them after checking every file against it.
+package archive
+
+import (
+	"archive/zip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/drpedapati/irl-template/pkg/config"
+	"github.com/drpedapati/irl-template/pkg/gitutil"
+)
+
+// Dir is the folder in a workspace root that holds archived projects
+const Dir = "_archive"
+
+// ManifestName is the manifest's name inside an archive
+const ManifestName = "irl-manifest.json"
+
+const manifestVersion = 1
+
+// Manifest describes an archive's contents
+type Manifest struct {
+	Version int       `json:"version"`
+	Project string    `json:"project"`
+	Path    string    `json:"path"` // where the project was archived from
+	Created time.Time `json:"created"`
+	GitHead string    `json:"git_head,omitempty"`
+	Files   []File    `json:"files"`
+}
+
+// File is one archived file. Symlinks are stored with their target as
+// content.
+type File struct {
+	Path   string `json:"path"` // slash-separated, relative to the project
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+	Link   bool   `json:"symlink,omitempty"`
+}
+
+// TotalSize returns the summed size of the archived files
+func (m *Manifest) TotalSize() int64 {
+	var n int64
+	for _, f := range m.Files {
+		n += f.Size
+	}
+	return n
+}
+
+// Entry is an archive found on disk
+type Entry struct {
+	Path     string
+	Size     int64 // compressed size
+	Manifest *Manifest
+}
+
+// DirFor returns the archive folder for a project: _archive in the
+// workspace root that contains it, or next to it outside the roots
+func DirFor(projectDir string) string {
+	projectDir = filepath.Clean(projectDir)
+	for _, root := range config.GetWorkspaces() {
+		base := filepath.Clean(root.Path)
+		if strings.HasPrefix(projectDir, base+string(filepath.Separator)) {
+			return filepath.Join(base, Dir)
+		}
+	}
+	return filepath.Join(filepath.Dir(projectDir), Dir)
+}
+
+// Dirs returns the archive folders of all workspace roots
+func Dirs() []string {
+	var dirs []string
+	for _, root := range config.GetWorkspaces() {
+		dirs = append(dirs, filepath.Join(root.Path, Dir))
+	}
+	return dirs
+}
+
+// FileName returns the archive name for a project archived at t,
+// e.g. my-study_20260202_091500.zip
+func FileName(project string, t time.Time) string {
+	return project + "_" + t.Format("20060102_150405") + ".zip"
+}
+
+// Create writes a zip of projectDir to dest with a manifest of its files.
+// A project that is a symlink, as adopt's symlink mode makes, is archived
+// from the folder it points to. The archive is written to a temporary
+// file and renamed into place.
+func Create(projectDir, dest string) (*Manifest, error) {
+	projectDir, err := filepath.Abs(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	root, err := filepath.EvalSymlinks(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	m := &Manifest{
+		Version: manifestVersion,
+		Project: filepath.Base(projectDir),
+		Path:    projectDir,
+		Created: time.Now(),
+		Files:   []File{},
+	}
+	if gitutil.IsRepo(root) {
+		m.GitHead, _ = gitutil.Head(root)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return nil, err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(dest), ".archive-*.zip")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name()) // no-op after the rename
+
+	zw := zip.NewWriter(tmp)
+	err = filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil || rel == "." {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		f, err := addEntry(zw, p, filepath.ToSlash(rel), info)
+		if err != nil {
+			return fmt.Errorf("%s: %w", rel, err)
+		}
+		if f != nil {
+			m.Files = append(m.Files, *f)
+		}
+		return nil
+	})
+	if err == nil {
+		err = writeManifest(zw, m)
+	}
+	if cerr := zw.Close(); err == nil {
+		err = cerr
+	}
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Rename(tmp.Name(), dest); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// addEntry stores one walked path; it returns the manifest entry for files
+// and symlinks, nil for folders. Other file types are skipped.
+func addEntry(zw *zip.Writer, src, name string, info fs.FileInfo) (*File, error) {
+	hdr, err := zip.FileInfoHeader(info)
+	if err != nil {
+		return nil, err
+	}
+	hdr.Name = name
+	hdr.Modified = info.ModTime()
+
+	switch {
+	case info.IsDir():
+		hdr.Name += "/"
+		_, err := zw.CreateHeader(hdr)
+		return nil, err
+
+	case info.Mode()&fs.ModeSymlink != 0:
+		target, err := os.Readlink(src)
+		if err != nil {
+			return nil, err
+		}
+		w, err := zw.CreateHeader(hdr)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.WriteString(w, target); err != nil {
+			return nil, err
+		}
+		return &File{Path: name, Size: int64(len(target)), SHA256: hashString(target), Link: true}, nil
+
+	case info.Mode().IsRegular():
+		hdr.Method = zip.Deflate
+		w, err := zw.CreateHeader(hdr)
+		if err != nil {
+			return nil, err
+		}
+		in, err := os.Open(src)
+		if err != nil {
+			return nil, err
+		}
+		defer in.Close()
+		h := sha256.New()
+		n, err := io.Copy(io.MultiWriter(w, h), in)
+		if err != nil {
+			return nil, err
+		}
+		return &File{Path: name, Size: n, SHA256: hex.EncodeToString(h.Sum(nil))}, nil
+	}
+	return nil, nil
+}
+
+func writeManifest(zw *zip.Writer, m *Manifest) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	w, err := zw.CreateHeader(&zip.FileHeader{Name: ManifestName, Method: zip.Deflate, Modified: m.Created})
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// Archive zips projectDir into archiveDir and removes the project.
+// Projects adopted as a symlink or in place are refused: removing them
+// would delete only the link, or a folder irl doesn't manage.
+func Archive(projectDir, archiveDir string) (string, *Manifest, error) {
+	if err := checkRemovable(projectDir); err != nil {
+		return "", nil, err
+	}
+	dest := filepath.Join(archiveDir, FileName(filepath.Base(projectDir), time.Now()))
+	m, err := Create(projectDir, dest)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create archive: %w", err)
+	}
+	// Check what was written before deleting the only other copy
+	if _, err := Verify(dest); err != nil {
+		os.Remove(dest)
+		return "", nil, fmt.Errorf("archive failed verification: %w", err)
+	}
+	if err := os.RemoveAll(projectDir); err != nil {
+		return dest, m, fmt.Errorf("archived to %s but failed to remove the project: %w", dest, err)
+	}
+	return dest, m, nil
+}
+
+// checkRemovable refuses projects that are symlinks or their own
+// workspace root
+func checkRemovable(projectDir string) error {
+	info, err := os.Lstat(projectDir)
+	if err != nil {
+		return err
+	}
+	name := filepath.Base(projectDir)
+	if info.Mode()&fs.ModeSymlink != 0 {
+		target, _ := filepath.EvalSymlinks(projectDir)
+		return fmt.Errorf("%s links to %s; archive it with --keep and remove the link yourself", name, target)
+	}
+	for _, w := range config.GetWorkspaces() {
+		if w.Project && filepath.Clean(w.Path) == filepath.Clean(projectDir) {
+			return fmt.Errorf("%s was adopted in place; archive it with --keep and remove it yourself", name)
+		}
+	}
+	return nil
+}
+
+// ReadManifest returns an archive's manifest without checking its files
+func ReadManifest(zipPath string) (*Manifest, error) {
+	zr, err := zip.OpenReader(zipPath)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+	return manifest(&zr.Reader)
+}
+
+func manifest(zr *zip.Reader) (*Manifest, error) {
+	f, err := zr.Open(ManifestName)
+	if err != nil {
+		return nil, fmt.Errorf("not an irl archive (no %s)", ManifestName)
+	}
+	defer f.Close()
+	var m Manifest
+	if err := json.NewDecoder(f).Decode(&m); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", ManifestName, err)
+	}
+	return &m, nil
+}
+
+// Verify checks every file in an archive against its manifest
+func Verify(zipPath string) (*Manifest, error) {
+	zr, err := zip.OpenReader(zipPath)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+	m, err := manifest(&zr.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return m, verify(&zr.Reader, m)
+}
+
+func verify(zr *zip.Reader, m *Manifest) error {
+	entries := map[string]*zip.File{}
+	for _, f := range zr.File {
+		if !f.FileInfo().IsDir() && f.Name != ManifestName {
+			entries[f.Name] = f
+		}
+	}
+	var problems []string
+	for _, want := range m.Files {
+		f, ok := entries[want.Path]
+		if !ok {
+			problems = append(problems, want.Path+": missing")
+			continue
+		}
+		delete(entries, want.Path)
+		sum, err := hashEntry(f)
+		if err != nil {
+			problems = append(problems, want.Path+": "+err.Error())
+		} else if sum != want.SHA256 {
+			problems = append(problems, want.Path+": checksum mismatch")
+		}
+	}
+	for name := range entries {
+		problems = append(problems, name+": not in manifest")
+	}
+	sort.Strings(problems)
+	n := len(problems)
+	switch {
+	case n == 0:
+		return nil
+	case n == 1:
+		return fmt.Errorf("%s", problems[0])
+	case n > 5:
+		problems = append(problems[:5], fmt.Sprintf("and %d more", n-5))
+	}
+	return fmt.Errorf("%d files don't match: %s", n, strings.Join(problems, "; "))
+}
+
+// Restore verifies an archive and extracts it to dest, which must not
+// exist. Files are extracted next to dest and renamed into place, so a
+// failed restore leaves nothing behind.
+func Restore(zipPath, dest string) (*Manifest, error) {
+	if _, err := os.Lstat(dest); err == nil {
+		return nil, fmt.Errorf("%s already exists", dest)
+	}
+	zr, err := zip.OpenReader(zipPath)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+	m, err := manifest(&zr.Reader)
+	if err != nil {
+		return nil, err
+	}
+	if err := verify(&zr.Reader, m); err != nil {
+		return nil, fmt.Errorf("archive failed verification: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return nil, err
+	}
+	tmp, err := os.MkdirTemp(filepath.Dir(dest), ".restore-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmp) // no-op after the rename
+
+	var dirs []*zip.File
+	for _, f := range zr.File {
+		if f.Name == ManifestName {
+			continue
+		}
+		if err := extract(f, tmp); err != nil {
+			return nil, fmt.Errorf("%s: %w", f.Name, err)
+		}
+		if f.FileInfo().IsDir() {
+			dirs = append(dirs, f)
+		}
+	}
+	// Folder times change as their contents are written, so set them last
+	for _, f := range dirs {
+		p := filepath.Join(tmp, filepath.FromSlash(strings.TrimSuffix(f.Name, "/")))
+		os.Chtimes(p, f.Modified, f.Modified)
+	}
+	if err := os.Chmod(tmp, 0755); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(tmp, dest); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// extract writes one zip entry below root
+func extract(f *zip.File, root string) error {
+	name := strings.TrimSuffix(f.Name, "/")
+	target, err := SafePath(root, name)
+	if err != nil {
+		return err
+	}
+	mode := f.Mode()
+
+	if mode.IsDir() {
+		return os.MkdirAll(target, mode.Perm()|0700)
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	if mode&fs.ModeSymlink != 0 {
+		link, err := io.ReadAll(rc)
+		if err != nil {
+			return err
+		}
+		if err := CheckLink(root, name, string(link)); err != nil {
+			return err
+		}
+		return os.Symlink(string(link), target)
+	}
+
+	out, err := os.OpenFile(target, os.O_CREATE|os.O_EXCL|os.O_WRONLY, mode.Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, rc); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	return os.Chtimes(target, f.Modified, f.Modified)
+}
+
+// SafePath returns where the slash-separated entry name is extracted
+// below root. It refuses names that leave root and names below a folder
+// that is a symlink, so nothing is written through a link an earlier
+// entry made.
+func SafePath(root, name string) (string, error) {
+	clean := path.Clean(name)
+	if path.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, "../") {
+		return "", fmt.Errorf("unsafe path")
+	}
+	parts := strings.Split(clean, "/")
+	for i := 1; i < len(parts); i++ {
+		parent := path.Join(parts[:i]...)
+		info, err := os.Lstat(filepath.Join(root, filepath.FromSlash(parent)))
+		if os.IsNotExist(err) {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		if info.Mode()&fs.ModeSymlink != 0 {
+			return "", fmt.Errorf("unsafe path (%s is a symlink)", parent)
+		}
+	}
+	return filepath.Join(root, filepath.FromSlash(clean)), nil
+}
+
+// CheckLink refuses a symlink entry, about to be written below root,
+// whose target is absolute or points outside the tree. The target is
+// followed through links already extracted, since b/.. isn't the folder
+// holding b when b is itself a link. A ".." after a path that doesn't
+// exist yet is refused too, as a later entry could make it a link.
+func CheckLink(root, name, target string) error {
+	if target == "" || path.IsAbs(target) || filepath.IsAbs(target) {
+		return fmt.Errorf("unsafe symlink to %s", target)
+	}
+	var cur []string // the resolved path so far, relative to root
+	if dir := path.Dir(path.Clean(name)); dir != "." {
+		cur = strings.Split(dir, "/")
+	}
+	todo := strings.Split(filepath.ToSlash(target), "/")
+	pending := false // cur includes a part that doesn't exist yet
+	for hops := 0; len(todo) > 0; {
+		part := todo[0]
+		todo = todo[1:]
+		switch part {
+		case "", ".":
+			continue
+		case "..":
+			if len(cur) == 0 || pending {
+				return fmt.Errorf("unsafe symlink to %s", target)
+			}
+			cur = cur[:len(cur)-1]
+			continue
+		}
+		cur = append(cur, part)
+		if pending {
+			continue
+		}
+		p := filepath.Join(root, filepath.FromSlash(path.Join(cur...)))
+		info, err := os.Lstat(p)
+		if os.IsNotExist(err) {
+			pending = true
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if info.Mode()&fs.ModeSymlink == 0 {
+			continue
+		}
+		link, err := os.Readlink(p)
+		if err != nil {
+			return err
+		}
+		if hops++; hops > 40 || path.IsAbs(link) || filepath.IsAbs(link) {
+			return fmt.Errorf("unsafe symlink to %s", target)
+		}
+		// Carry on from the folder holding the link, through its target
+		cur = cur[:len(cur)-1]
+		todo = append(strings.Split(filepath.ToSlash(link), "/"), todo...)
+	}
+	return nil
+}
+
+// List returns the archives in the given folders, newest first. Folders
+// that don't exist are skipped, as are zips without a manifest.
+func List(dirs []string) []Entry {
+	var entries []Entry
+	for _, dir := range dirs {
+		matches, _ := filepath.Glob(filepath.Join(dir, "*.zip"))
+		for _, p := range matches {
+			m, err := ReadManifest(p)
+			if err != nil {
+				continue
+			}
+			e := Entry{Path: p, Manifest: m}
+			if info, err := os.Stat(p); err == nil {
+				e.Size = info.Size()
+			}
+			entries = append(entries, e)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Manifest.Created.After(entries[j].Manifest.Created)
+	})
+	return entries
+}
+
+func hashEntry(f *zip.File) (string, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return "", err
+	}
+	defer rc.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, rc); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func hashString(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,149 @@
+package archive
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// entry is one file of a hand-built zip
+type entry struct {
+	name, body string
+	link       bool
+}
+
+// writeZip builds a zip holding entries and a manifest that matches them,
+// as a crafted archive would
+func writeZip(t *testing.T, file string, entries []entry) {
+	t.Helper()
+	out, err := os.Create(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer out.Close()
+	zw := zip.NewWriter(out)
+	m := &Manifest{Version: manifestVersion, Project: "evil", Created: time.Now(), Files: []File{}}
+	for _, e := range entries {
+		hdr := &zip.FileHeader{Name: e.name}
+		if e.link {
+			hdr.SetMode(os.ModeSymlink | 0777)
+		} else {
+			hdr.SetMode(0644)
+		}
+		w, err := zw.CreateHeader(hdr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(e.body))
+		m.Files = append(m.Files, File{Path: e.name, Size: int64(len(e.body)), SHA256: hashString(e.body), Link: e.link})
+	}
+	data, _ := json.Marshal(m)
+	w, _ := zw.Create(ManifestName)
+	w.Write(data)
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRestoreRefusesEscapingSymlinks(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries func(outside string) []entry
+	}{
+		{"absolute link", func(outside string) []entry {
+			return []entry{{name: "d", body: outside, link: true}, {name: "d/pwned.txt", body: "x"}}
+		}},
+		{"relative link", func(outside string) []entry {
+			return []entry{{name: "d", body: "../../outside", link: true}, {name: "d/pwned.txt", body: "x"}}
+		}},
+		{"link inside the tree", func(outside string) []entry {
+			return []entry{{name: "sub/keep.txt", body: "k"}, {name: "d", body: "sub", link: true}, {name: "d/pwned.txt", body: "x"}}
+		}},
+		{"link through an earlier link", func(outside string) []entry {
+			// b/.. is the folder above the tree when b links to it
+			up := strings.Repeat("b/", 8) + strings.Repeat("../", 8)
+			return []entry{{name: "b", body: ".", link: true}, {name: "d", body: up + "outside", link: true}}
+		}},
+		{"link up through a path made later", func(outside string) []entry {
+			// c/b is made a link to the tree after d is checked
+			return []entry{{name: "d", body: "c/b/../outside", link: true}, {name: "c/b", body: "..", link: true}}
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			outside := filepath.Join(dir, "outside")
+			if err := os.Mkdir(outside, 0755); err != nil {
+				t.Fatal(err)
+			}
+			zipPath := filepath.Join(dir, "evil.zip")
+			writeZip(t, zipPath, tt.entries(outside))
+
+			dest := filepath.Join(dir, "ws", "evil")
+			if _, err := Restore(zipPath, dest); err == nil {
+				t.Fatal("Restore succeeded, want an unsafe path error")
+			}
+			if entries, _ := os.ReadDir(outside); len(entries) > 0 {
+				t.Errorf("wrote %s outside the destination", entries[0].Name())
+			}
+			if _, err := os.Lstat(dest); !os.IsNotExist(err) {
+				t.Errorf("left %s behind", dest)
+			}
+		})
+	}
+}
+
+func TestRestoreKeepsSafeSymlinks(t *testing.T) {
+	dir := t.TempDir()
+	zipPath := filepath.Join(dir, "ok.zip")
+	writeZip(t, zipPath, []entry{
+		{name: "plans/main-plan.md", body: "# plan"},
+		{name: "plan.md", body: "plans/main-plan.md", link: true},
+		{name: "p", body: "plans", link: true},
+		{name: "03-outputs/plan.md", body: "../p/../plans/main-plan.md", link: true},
+	})
+
+	dest := filepath.Join(dir, "ok")
+	if _, err := Restore(zipPath, dest); err != nil {
+		t.Fatal(err)
+	}
+	for _, link := range []string{"plan.md", "03-outputs/plan.md"} {
+		data, err := os.ReadFile(filepath.Join(dest, link))
+		if err != nil || string(data) != "# plan" {
+			t.Errorf("%s = %q, %v", link, data, err)
+		}
+	}
+}
+
+func TestCreateFollowsSymlinkedProject(t *testing.T) {
+	dir := t.TempDir()
+	real := filepath.Join(dir, "real")
+	if err := os.MkdirAll(filepath.Join(real, "plans"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(real, "plans", "main-plan.md"), []byte("# plan"), 0644)
+	link := filepath.Join(dir, "ws", "study")
+	os.MkdirAll(filepath.Dir(link), 0755)
+	if err := os.Symlink(real, link); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := Create(link, filepath.Join(dir, "study.zip"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Files) != 1 || m.Files[0].Path != "plans/main-plan.md" {
+		t.Errorf("archived %+v, want plans/main-plan.md", m.Files)
+	}
+
+	if _, _, err := Archive(link, filepath.Join(dir, "_archive")); err == nil || !strings.Contains(err.Error(), "links to") {
+		t.Errorf("Archive of a symlinked project: err = %v, want a refusal", err)
+	}
+	if _, err := os.Lstat(link); err != nil {
+		t.Errorf("the link was removed: %v", err)
+	}
+}
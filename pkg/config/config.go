@@ -22,6 +22,47 @@ type Config struct {
 	FavoriteEditors  []string `json:"favorite_editors,omitempty"` // Editor cmd names (e.g., "cursor", "code")
 	PlanEditor       string   `json:"plan_editor,omitempty"`      // Plan editor: "nano", "vim", "code", "cursor", "auto"
 	PlanEditorType   string   `json:"plan_editor_type,omitempty"` // "terminal" or "gui"
+	Agents           map[string]string `json:"agents,omitempty"`   // Agent name -> command template for irl run
+	Renderer         string            `json:"renderer,omitempty"` // Command template for irl render
+	TemplateSources  []TemplateSource  `json:"template_sources,omitempty"` // Extra template catalogs, highest precedence first
+	Workspaces       []Workspace       `json:"workspaces,omitempty"`       // Extra roots scanned for projects
+}
+
+// Workspace is a directory tree scanned for projects
+type Workspace struct {
+	Path     string   `json:"path"`
+	Label    string   `json:"label,omitempty"`     // shown next to its projects; defaults to the folder name
+	MaxDepth int      `json:"max_depth,omitempty"` // folder levels searched below Path; 0 means DefaultMaxDepth
+	Ignore   []string `json:"ignore,omitempty"`    // globs matched against folder names and paths relative to Path
+	Project  bool     `json:"project,omitempty"`   // Path is itself a project, adopted in place
+}
+
+// DefaultMaxDepth is how deep workspaces are searched when not configured
+const DefaultMaxDepth = 3
+
+// Name returns the workspace label, or its folder name
+func (w Workspace) Name() string {
+	if w.Label != "" {
+		return w.Label
+	}
+	return filepath.Base(w.Path)
+}
+
+// Depth returns the workspace's max depth, or DefaultMaxDepth
+func (w Workspace) Depth() int {
+	if w.MaxDepth > 0 {
+		return w.MaxDepth
+	}
+	return DefaultMaxDepth
+}
+
+// TemplateSource is a configured template catalog
+type TemplateSource struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`           // "dir", "git", "http" or "github"
+	Location string `json:"location"`       // directory, repository URL, index URL or owner/repo
+	Ref      string `json:"ref,omitempty"`  // git branch, tag or commit
+	Path     string `json:"path,omitempty"` // subdirectory holding the templates
 }
 
 var configPath string
@@ -199,3 +240,144 @@ func SetPlanEditor(editor, editorType string) error {
 func ClearPlanEditor() error {
 	return SetPlanEditor("", "")
 }
+
+// GetAgentCommand returns the configured command template for an agent
+func GetAgentCommand(name string) string {
+	cfg, err := Load()
+	if err != nil {
+		return ""
+	}
+	return cfg.Agents[name]
+}
+
+// SetAgentCommand saves an agent command template. An empty command removes it.
+func SetAgentCommand(name, command string) error {
+	cfg, err := Load()
+	if err != nil {
+		cfg = &Config{}
+	}
+	if command == "" {
+		delete(cfg.Agents, name)
+	} else {
+		if cfg.Agents == nil {
+			cfg.Agents = map[string]string{}
+		}
+		cfg.Agents[name] = command
+	}
+	return cfg.Save()
+}
+
+// GetRenderer returns the configured renderer command template
+func GetRenderer() string {
+	cfg, err := Load()
+	if err != nil {
+		return ""
+	}
+	return cfg.Renderer
+}
+
+// SetRenderer saves the renderer command template. An empty command
+// restores the default.
+func SetRenderer(command string) error {
+	cfg, err := Load()
+	if err != nil {
+		cfg = &Config{}
+	}
+	cfg.Renderer = command
+	return cfg.Save()
+}
+
+// GetTemplateSources returns the configured template sources in precedence order
+func GetTemplateSources() []TemplateSource {
+	cfg, err := Load()
+	if err != nil {
+		return nil
+	}
+	return cfg.TemplateSources
+}
+
+// AddTemplateSource saves a template source, replacing one with the same name
+func AddTemplateSource(src TemplateSource) error {
+	cfg, err := Load()
+	if err != nil {
+		cfg = &Config{}
+	}
+	for i, s := range cfg.TemplateSources {
+		if s.Name == src.Name {
+			cfg.TemplateSources[i] = src
+			return cfg.Save()
+		}
+	}
+	cfg.TemplateSources = append(cfg.TemplateSources, src)
+	return cfg.Save()
+}
+
+// RemoveTemplateSource deletes a template source. It returns false if no
+// source has that name.
+func RemoveTemplateSource(name string) (bool, error) {
+	cfg, err := Load()
+	if err != nil {
+		return false, err
+	}
+	for i, s := range cfg.TemplateSources {
+		if s.Name == name {
+			cfg.TemplateSources = append(cfg.TemplateSources[:i], cfg.TemplateSources[i+1:]...)
+			return true, cfg.Save()
+		}
+	}
+	return false, nil
+}
+
+// GetWorkspaces returns the roots scanned for projects: the default
+// directory first, unless it's also listed, then the configured workspaces
+func GetWorkspaces() []Workspace {
+	cfg, err := Load()
+	if err != nil {
+		return nil
+	}
+	var roots []Workspace
+	if cfg.DefaultDirectory != "" {
+		listed := false
+		for _, w := range cfg.Workspaces {
+			if filepath.Clean(w.Path) == filepath.Clean(cfg.DefaultDirectory) {
+				listed = true
+			}
+		}
+		if !listed {
+			roots = append(roots, Workspace{Path: cfg.DefaultDirectory})
+		}
+	}
+	return append(roots, cfg.Workspaces...)
+}
+
+// AddWorkspace saves a workspace root, replacing one with the same path
+func AddWorkspace(w Workspace) error {
+	cfg, err := Load()
+	if err != nil {
+		cfg = &Config{}
+	}
+	for i, existing := range cfg.Workspaces {
+		if filepath.Clean(existing.Path) == filepath.Clean(w.Path) {
+			cfg.Workspaces[i] = w
+			return cfg.Save()
+		}
+	}
+	cfg.Workspaces = append(cfg.Workspaces, w)
+	return cfg.Save()
+}
+
+// RemoveWorkspace deletes the workspace root with the given path or label.
+// It returns false if none matches.
+func RemoveWorkspace(pathOrLabel string) (bool, error) {
+	cfg, err := Load()
+	if err != nil {
+		return false, err
+	}
+	for i, w := range cfg.Workspaces {
+		if filepath.Clean(w.Path) == filepath.Clean(pathOrLabel) || (w.Label != "" && w.Label == pathOrLabel) {
+			cfg.Workspaces = append(cfg.Workspaces[:i], cfg.Workspaces[i+1:]...)
+			return true, cfg.Save()
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,297 @@
+// Package decisions reads and writes a project's decision log, the CSV
+// that records why the research took the turns it did.
+package decisions
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/drpedapati/irl-template/pkg/gitutil"
+)
+
+// File is the decision log's path relative to the project
+const File = "04-logs/decision_log.csv"
+
+// TimeFormat is how decision timestamps are written
+const TimeFormat = "2006-01-02 15:04"
+
+// Columns is the decision log header. The first five match the log the
+// IRL template ships with; git_hash is added to older logs on first write.
+var Columns = []string{"timestamp", "decision", "rationale", "evidence", "validation", "git_hash"}
+
+// Decision is one row of the log
+type Decision struct {
+	Timestamp  string `json:"timestamp"`
+	Decision   string `json:"decision"`
+	Rationale  string `json:"rationale"`
+	Evidence   string `json:"evidence,omitempty"`
+	Validation string `json:"validation,omitempty"`
+	GitHash    string `json:"git_hash,omitempty"` // HEAD when the decision was logged
+}
+
+func (d Decision) fields() []string {
+	return []string{d.Timestamp, d.Decision, d.Rationale, d.Evidence, d.Validation, d.GitHash}
+}
+
+// row lays the decision out under a log's header. Unknown columns are
+// left empty.
+func (d Decision) row(header []string) []string {
+	values := map[string]string{}
+	for i, v := range d.fields() {
+		values[Columns[i]] = v
+	}
+	out := make([]string, len(header))
+	for i, name := range header {
+		out[i] = values[strings.ToLower(strings.TrimSpace(name))]
+	}
+	return out
+}
+
+// Time parses the timestamp. Rows written by hand may hold only a date.
+func (d Decision) Time() (time.Time, bool) {
+	for _, layout := range []string{TimeFormat, "2006-01-02", time.RFC3339} {
+		if t, err := time.ParseInLocation(layout, strings.TrimSpace(d.Timestamp), time.Local); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// Path returns the decision log's location in a project
+func Path(projectDir string) string {
+	return filepath.Join(projectDir, filepath.FromSlash(File))
+}
+
+// Validate tidies a decision and checks it can be logged. Each field is
+// collapsed onto one line so the log stays greppable.
+func Validate(d *Decision) error {
+	for _, f := range []*string{&d.Decision, &d.Rationale, &d.Evidence, &d.Validation} {
+		*f = strings.Join(strings.Fields(*f), " ")
+	}
+	if d.Decision == "" {
+		return errors.New("decision is required")
+	}
+	if d.Rationale == "" {
+		return errors.New("rationale is required")
+	}
+	if isPlaceholder(d.Decision) {
+		return errors.New("decision can't be a [placeholder]")
+	}
+	return nil
+}
+
+// Append validates a decision, stamps it with the time and the project's
+// HEAD, and adds it to the log, creating the log if needed. The written
+// row is returned.
+func Append(projectDir string, d Decision) (Decision, error) {
+	if err := Validate(&d); err != nil {
+		return d, err
+	}
+	if d.Timestamp == "" {
+		d.Timestamp = time.Now().Format(TimeFormat)
+	}
+	if d.GitHash == "" && gitutil.IsRepo(projectDir) {
+		if head, err := gitutil.Head(projectDir); err == nil {
+			d.GitHash = gitutil.Short(head)
+		}
+	}
+
+	path := Path(projectDir)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return d, err
+	}
+	header, err := upgradeHeader(path)
+	if err != nil {
+		return d, err
+	}
+
+	var b bytes.Buffer
+	w := csv.NewWriter(&b)
+	if header == nil {
+		header = Columns
+		w.Write(header)
+	}
+	w.Write(d.row(header))
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return d, err
+	}
+	return d, appendText(path, b.Bytes())
+}
+
+// Load reads a project's decision log in file order. Columns are matched
+// by header name, so hand-edited logs with reordered or missing columns
+// still load; template placeholder rows are skipped. The error satisfies
+// os.IsNotExist when the project has no log.
+func Load(projectDir string) ([]Decision, error) {
+	records, err := readAll(Path(projectDir))
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	index := map[string]int{}
+	for i, name := range records[0] {
+		index[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := index["decision"]; !ok {
+		return nil, fmt.Errorf("%s has no decision column", File)
+	}
+	get := func(rec []string, name string) string {
+		if i, ok := index[name]; ok && i < len(rec) {
+			return strings.TrimSpace(rec[i])
+		}
+		return ""
+	}
+
+	var list []Decision
+	for _, rec := range records[1:] {
+		d := Decision{
+			Timestamp:  get(rec, "timestamp"),
+			Decision:   get(rec, "decision"),
+			Rationale:  get(rec, "rationale"),
+			Evidence:   get(rec, "evidence"),
+			Validation: get(rec, "validation"),
+			GitHash:    get(rec, "git_hash"),
+		}
+		if d.Decision == "" || isPlaceholder(d.Decision) || isPlaceholder(d.Timestamp) {
+			continue
+		}
+		list = append(list, d)
+	}
+	return list, nil
+}
+
+// Filter keeps the decisions that contain query in any field, ignoring
+// case, and were logged on or after since. An empty query or zero since
+// matches everything; rows without a readable timestamp never match a
+// since filter.
+func Filter(list []Decision, query string, since time.Time) []Decision {
+	query = strings.ToLower(query)
+	var out []Decision
+	for _, d := range list {
+		if !since.IsZero() {
+			t, ok := d.Time()
+			if !ok || t.Before(since) {
+				continue
+			}
+		}
+		if query != "" && !strings.Contains(strings.ToLower(strings.Join(d.fields(), "\n")), query) {
+			continue
+		}
+		out = append(out, d)
+	}
+	return out
+}
+
+// Markdown renders decisions as a markdown document, one section each
+func Markdown(title string, list []Decision) string {
+	var b strings.Builder
+	b.WriteString("# " + title + "\n")
+	if len(list) == 0 {
+		b.WriteString("\nNo decisions recorded.\n")
+	}
+	for _, d := range list {
+		b.WriteString("\n## " + d.Decision + "\n\n")
+		if d.Timestamp != "" {
+			b.WriteString("- **When:** " + d.Timestamp)
+			if d.GitHash != "" {
+				b.WriteString(" (`" + d.GitHash + "`)")
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString("- **Rationale:** " + d.Rationale + "\n")
+		if d.Evidence != "" {
+			b.WriteString("- **Evidence:** " + d.Evidence + "\n")
+		}
+		if d.Validation != "" {
+			b.WriteString("- **Validation:** " + d.Validation + "\n")
+		}
+	}
+	return b.String()
+}
+
+// isPlaceholder matches template rows like "[Add your decisions here.]"
+func isPlaceholder(s string) bool {
+	return strings.HasPrefix(s, "[") && strings.HasSuffix(s, "]")
+}
+
+func readAll(path string) ([][]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	r := csv.NewReader(f)
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", File, err)
+	}
+	return records, nil
+}
+
+// upgradeHeader adds the git_hash column to a log that predates it,
+// padding existing rows so every row has the same number of fields. It
+// returns the log's header, or nil if the log is missing or empty.
+func upgradeHeader(path string) ([]string, error) {
+	records, err := readAll(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+	for _, name := range records[0] {
+		if strings.TrimSpace(name) == "git_hash" {
+			return records[0], nil
+		}
+	}
+
+	records[0] = append(records[0], "git_hash")
+	width := len(records[0])
+	for i, rec := range records[1:] {
+		for len(rec) < width {
+			rec = append(rec, "")
+		}
+		records[i+1] = rec
+	}
+
+	var b bytes.Buffer
+	w := csv.NewWriter(&b)
+	if err := w.WriteAll(records); err != nil {
+		return nil, err
+	}
+	return records[0], os.WriteFile(path, b.Bytes(), 0644)
+}
+
+// appendText appends data, first terminating an unterminated last line
+func appendText(path string, data []byte) error {
+	old, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if len(old) > 0 && old[len(old)-1] != '\n' {
+		data = append([]byte("\n"), data...)
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(data)
+	return err
+}
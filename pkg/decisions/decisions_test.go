@@ -0,0 +1,206 @@
+package decisions
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeLog writes a decision log into a fresh project
+func writeLog(t *testing.T, body string) string {
+	t.Helper()
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Dir(Path(dir)), 0755)
+	if err := os.WriteFile(Path(dir), []byte(body), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func readLog(t *testing.T, dir string) string {
+	t.Helper()
+	data, err := os.ReadFile(Path(dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestAppendQuotesFields(t *testing.T) {
+	dir := t.TempDir()
+	d := Decision{
+		Timestamp:  "2026-03-01 09:30",
+		Decision:   `Use "robust" z-scores, not raw`,
+		Rationale:  "Outliers in\nsubjects 3, 7",
+		Evidence:   `Fig. 2, "QC" tab`,
+		Validation: "Rerun",
+	}
+	got, err := Append(dir, d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Rationale != "Outliers in subjects 3, 7" {
+		t.Errorf("rationale = %q, want it on one line", got.Rationale)
+	}
+	if got.GitHash != "" {
+		t.Errorf("git hash %q outside a repo", got.GitHash)
+	}
+
+	want := "timestamp,decision,rationale,evidence,validation,git_hash\n" +
+		`2026-03-01 09:30,"Use ""robust"" z-scores, not raw","Outliers in subjects 3, 7","Fig. 2, ""QC"" tab",Rerun,` + "\n"
+	if log := readLog(t, dir); log != want {
+		t.Errorf("log =\n%s\nwant\n%s", log, want)
+	}
+
+	list, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 || !reflect.DeepEqual(list[0], got) {
+		t.Errorf("loaded %+v, want %+v", list, got)
+	}
+}
+
+func TestAppendUpgradesTemplateLog(t *testing.T) {
+	// The log the IRL template ships with, without a trailing newline
+	dir := writeLog(t, "timestamp,decision,rationale,evidence,validation\n"+
+		"[YYYY-MM-DD HH:MM],[Add your decisions here.],[Why],[Evidence],[Validation]\n"+
+		"2026-01-05,Exclude pilot data,Different montage,,")
+
+	if _, err := Append(dir, Decision{Timestamp: "2026-02-01 10:00", Decision: "Use ICA", Rationale: "Blinks"}); err != nil {
+		t.Fatal(err)
+	}
+	want := "timestamp,decision,rationale,evidence,validation,git_hash\n" +
+		"[YYYY-MM-DD HH:MM],[Add your decisions here.],[Why],[Evidence],[Validation],\n" +
+		"2026-01-05,Exclude pilot data,Different montage,,,\n" +
+		"2026-02-01 10:00,Use ICA,Blinks,,,\n"
+	if log := readLog(t, dir); log != want {
+		t.Errorf("log =\n%s\nwant\n%s", log, want)
+	}
+
+	// A second write leaves the header alone
+	if _, err := Append(dir, Decision{Timestamp: "2026-02-02 10:00", Decision: "Keep ICA", Rationale: "Worked"}); err != nil {
+		t.Fatal(err)
+	}
+	if log := readLog(t, dir); strings.Count(log, "git_hash") != 1 {
+		t.Errorf("header upgraded twice:\n%s", log)
+	}
+}
+
+func TestAppendFollowsHandEditedHeader(t *testing.T) {
+	dir := writeLog(t, "Decision,Timestamp,git_hash,notes\n")
+	if _, err := Append(dir, Decision{Timestamp: "2026-02-01 10:00", Decision: "Use ICA", Rationale: "Blinks"}); err != nil {
+		t.Fatal(err)
+	}
+	want := "Decision,Timestamp,git_hash,notes\nUse ICA,2026-02-01 10:00,,\n"
+	if log := readLog(t, dir); log != want {
+		t.Errorf("log =\n%s\nwant\n%s", log, want)
+	}
+}
+
+func TestAppendRefusesPlaceholders(t *testing.T) {
+	dir := t.TempDir()
+	for _, d := range []Decision{
+		{Decision: "", Rationale: "why"},
+		{Decision: "Use ICA", Rationale: "  \n "},
+		{Decision: "[Add your decisions here.]", Rationale: "why"},
+	} {
+		if _, err := Append(dir, d); err == nil {
+			t.Errorf("%+v was logged", d)
+		}
+	}
+	if _, err := os.Stat(Path(dir)); !os.IsNotExist(err) {
+		t.Error("a refused decision created the log")
+	}
+}
+
+func TestLoad(t *testing.T) {
+	if _, err := Load(t.TempDir()); !os.IsNotExist(err) {
+		t.Errorf("missing log: err = %v, want not exist", err)
+	}
+
+	dir := writeLog(t, "timestamp,decision,rationale\n"+
+		"[YYYY-MM-DD HH:MM],Example,Shown in the template\n"+
+		"2026-01-05,[Add your decisions here.],x\n"+
+		",,\n"+
+		"2026-01-06, Exclude pilot data ,Different montage\n"+
+		"2026-01-07,Short row\n")
+	list, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Decision{
+		{Timestamp: "2026-01-06", Decision: "Exclude pilot data", Rationale: "Different montage"},
+		{Timestamp: "2026-01-07", Decision: "Short row"},
+	}
+	if !reflect.DeepEqual(list, want) {
+		t.Errorf("loaded %+v, want %+v", list, want)
+	}
+
+	dir = writeLog(t, "when,what\n2026-01-06,x\n")
+	if _, err := Load(dir); err == nil || !strings.Contains(err.Error(), "no decision column") {
+		t.Errorf("err = %v, want a missing column error", err)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	list := []Decision{
+		{Timestamp: "2026-01-05", Decision: "Exclude pilot data", Rationale: "Different montage"},
+		{Timestamp: "2026-02-01 10:00", Decision: "Use ICA", Rationale: "Blinks", Evidence: "Pilot FILTER check"},
+		{Timestamp: "sometime", Decision: "Use 1-40 Hz filter", Rationale: "Prior work"},
+	}
+	since := time.Date(2026, 1, 10, 0, 0, 0, 0, time.Local)
+
+	for _, tc := range []struct {
+		name  string
+		query string
+		since time.Time
+		want  []string
+	}{
+		{"everything", "", time.Time{}, []string{"Exclude pilot data", "Use ICA", "Use 1-40 Hz filter"}},
+		{"any field, any case", "filter", time.Time{}, []string{"Use ICA", "Use 1-40 Hz filter"}},
+		{"since skips unreadable times", "", since, []string{"Use ICA"}},
+		{"both", "pilot", since, []string{"Use ICA"}},
+		{"nothing", "eyes", time.Time{}, nil},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var got []string
+			for _, d := range Filter(list, tc.query, tc.since) {
+				got = append(got, d.Decision)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	got := Markdown("Decisions", []Decision{
+		{Timestamp: "2026-02-01 10:00", Decision: "Use ICA", Rationale: "Blinks", Evidence: "Fig 2", GitHash: "abc1234"},
+		{Decision: "Keep 1-40 Hz", Rationale: "Prior work", Validation: "Compare ERPs"},
+	})
+	want := `# Decisions
+
+## Use ICA
+
+- **When:** 2026-02-01 10:00 (` + "`abc1234`" + `)
+- **Rationale:** Blinks
+- **Evidence:** Fig 2
+
+## Keep 1-40 Hz
+
+- **Rationale:** Prior work
+- **Validation:** Compare ERPs
+`
+	if got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+
+	if got := Markdown("Decisions", nil); got != "# Decisions\n\nNo decisions recorded.\n" {
+		t.Errorf("empty log rendered as %q", got)
+	}
+}
@@ -2,53 +2,102 @@
 package doctor
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
 	"runtime"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 )
 
+// DefaultTimeout bounds each tool's version check
+const DefaultTimeout = 5 * time.Second
+
 // Tool represents a tool to check
 type Tool struct {
-	Name     string
-	Cmd      string
-	Install  string
-	Category string
+	Name        string `json:"name"`
+	Cmd         string `json:"cmd"`
+	Install     string `json:"install"`
+	Category    string `json:"category"`
+	MinVersion  string `json:"min_version"` // oldest supported version; empty when any will do
+	VersionFlag string `json:"-"`           // prints the version, e.g. --version; empty to skip
 }
 
 // ToolResult represents the result of checking a tool
 type ToolResult struct {
-	Tool  Tool
-	Found bool
+	Tool    Tool   `json:"tool"`
+	Found   bool   `json:"found"`
+	Path    string `json:"path"`    // resolved executable or app bundle
+	Version string `json:"version"` // parsed from the version output
+	OK      bool   `json:"ok"`      // found, at MinVersion or newer when it has one
+	Error   string `json:"error"`   // why the version couldn't be read
 }
 
 // SystemInfo holds system information
 type SystemInfo struct {
-	Platform string
-	Cores    int
-	Memory   string
-	Disk     string
+	Platform string `json:"platform"`
+	Cores    int    `json:"cores"`
+	Memory   string `json:"memory"`
+	Disk     string `json:"disk"`
+}
+
+// Report is a complete environment check
+type Report struct {
+	System SystemInfo   `json:"system"`
+	Tools  []ToolResult `json:"tools"`
+	OK     bool         `json:"ok"` // every core tool is found at its minimum version
+}
+
+// Run checks the system and every tool concurrently, giving each command
+// at most timeout
+func Run(timeout time.Duration) Report {
+	var r Report
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		r.System = systemInfo(timeout)
+	}()
+	r.Tools = CheckTools(AllTools(), timeout)
+	wg.Wait()
+
+	r.OK = true
+	for _, t := range r.Tools {
+		if t.Tool.Category == CoreCategory && !t.OK {
+			r.OK = false
+		}
+	}
+	return r
 }
 
 // GetSystemInfo returns system information
 func GetSystemInfo() SystemInfo {
+	return systemInfo(DefaultTimeout)
+}
+
+func systemInfo(timeout time.Duration) SystemInfo {
 	info := SystemInfo{
 		Platform: fmt.Sprintf("%s/%s", runtime.GOOS, runtime.GOARCH),
 		Cores:    runtime.NumCPU(),
 	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
 
 	// Memory
 	if runtime.GOOS == "darwin" {
-		if out, err := exec.Command("sysctl", "-n", "hw.memsize").Output(); err == nil {
+		if out, err := exec.CommandContext(ctx, "sysctl", "-n", "hw.memsize").Output(); err == nil {
 			memBytes := strings.TrimSpace(string(out))
 			if len(memBytes) > 9 {
 				info.Memory = memBytes[:len(memBytes)-9] + " GB"
 			}
 		}
 	} else if runtime.GOOS == "linux" {
-		if out, err := exec.Command("free", "-g").Output(); err == nil {
+		if out, err := exec.CommandContext(ctx, "free", "-g").Output(); err == nil {
 			lines := strings.Split(string(out), "\n")
 			if len(lines) > 1 {
 				fields := strings.Fields(lines[1])
@@ -60,7 +109,7 @@ func GetSystemInfo() SystemInfo {
 	}
 
 	// Disk
-	if out, err := exec.Command("df", "-h", ".").Output(); err == nil {
+	if out, err := exec.CommandContext(ctx, "df", "-h", ".").Output(); err == nil {
 		lines := strings.Split(string(out), "\n")
 		if len(lines) > 1 {
 			fields := strings.Fields(lines[1])
@@ -78,13 +127,16 @@ func (s SystemInfo) String() string {
 	return s.Platform
 }
 
+// CoreCategory is the category of the tools projects depend on
+const CoreCategory = "Core Tools"
+
 // CoreTools returns the list of core tools to check
 func CoreTools() []Tool {
 	return []Tool{
-		{Name: "Git", Cmd: "git", Install: "brew install git", Category: "Core Tools"},
-		{Name: "Quarto", Cmd: "quarto", Install: "brew install --cask quarto", Category: "Core Tools"},
-		{Name: "R", Cmd: "R", Install: "brew install r", Category: "Core Tools"},
-		{Name: "Python", Cmd: "python3", Install: "brew install python", Category: "Core Tools"},
+		{Name: "Git", Cmd: "git", Install: "brew install git", Category: CoreCategory, MinVersion: "2.28", VersionFlag: "--version"},
+		{Name: "Quarto", Cmd: "quarto", Install: "brew install --cask quarto", Category: CoreCategory, MinVersion: "1.3", VersionFlag: "--version"},
+		{Name: "R", Cmd: "R", Install: "brew install r", Category: CoreCategory, MinVersion: "4.1", VersionFlag: "--version"},
+		{Name: "Python", Cmd: "python3", Install: "brew install python", Category: CoreCategory, MinVersion: "3.9", VersionFlag: "--version"},
 	}
 }
 
@@ -126,39 +178,122 @@ func AllTools() []Tool {
 
 // CheckTool checks if a tool is available
 func CheckTool(t Tool) bool {
+	return locate(t) != ""
+}
+
+// Check resolves a tool and, when it has a VersionFlag, reads its version
+// and compares it with MinVersion. The version command is killed when ctx
+// is done.
+func Check(ctx context.Context, t Tool) ToolResult {
+	r := ToolResult{Tool: t, Path: locate(t)}
+	r.Found = r.Path != ""
+	if !r.Found || t.VersionFlag == "" {
+		r.OK = r.Found
+		return r
+	}
+
+	cmd := exec.CommandContext(ctx, r.Path, t.VersionFlag)
+	cmd.WaitDelay = time.Second // R is a script; don't wait on children holding its output
+	out, err := cmd.CombinedOutput()
+	switch {
+	case ctx.Err() != nil:
+		r.Error = "version check timed out"
+	case err != nil:
+		r.Error = fmt.Sprintf("%s %s: %v", t.Cmd, t.VersionFlag, err)
+	default:
+		r.Version = ParseVersion(string(out))
+		if r.Version == "" {
+			r.Error = "no version in " + t.Cmd + " " + t.VersionFlag + " output"
+		}
+	}
+	r.OK = t.MinVersion == "" || (r.Version != "" && AtLeast(r.Version, t.MinVersion))
+	return r
+}
+
+// CheckTools checks tools concurrently, each within timeout, and returns
+// the results in the order given
+func CheckTools(tools []Tool, timeout time.Duration) []ToolResult {
+	results := make([]ToolResult, len(tools))
+	var wg sync.WaitGroup
+	for i, t := range tools {
+		wg.Add(1)
+		go func(i int, t Tool) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+			results[i] = Check(ctx, t)
+		}(i, t)
+	}
+	wg.Wait()
+	return results
+}
+
+// CheckAllTools checks all tools and returns results
+func CheckAllTools() []ToolResult {
+	return CheckTools(AllTools(), DefaultTimeout)
+}
+
+// versionPattern matches the first dotted version number, as in
+// "git version 2.39.2", "R version 4.3.1 (2023-06-16)" or "1.4.550"
+var versionPattern = regexp.MustCompile(`\d+(\.\d+)+`)
+
+// ParseVersion returns the first dotted version number in a tool's
+// version output, or "" when there is none
+func ParseVersion(out string) string {
+	return versionPattern.FindString(out)
+}
+
+// AtLeast reports whether version is min or newer, comparing dotted
+// numbers field by field; missing fields count as zero
+func AtLeast(version, min string) bool {
+	v, m := strings.Split(version, "."), strings.Split(min, ".")
+	for i := 0; i < len(v) || i < len(m); i++ {
+		a, b := field(v, i), field(m, i)
+		if a != b {
+			return a > b
+		}
+	}
+	return true
+}
+
+func field(parts []string, i int) int {
+	if i >= len(parts) {
+		return 0
+	}
+	n, _ := strconv.Atoi(parts[i])
+	return n
+}
+
+// locate returns the tool's executable or app bundle, or "" when it isn't
+// installed
+func locate(t Tool) string {
 	switch t.Cmd {
 	case "positron":
-		return checkApp("Positron")
+		return findApp("Positron")
 	case "cursor":
-		return checkApp("Cursor")
+		return findApp("Cursor")
 	case "rstudio":
-		return checkApp("RStudio")
+		return findApp("RStudio")
 	default:
-		return checkCmd(t.Cmd)
+		return findCmd(t.Cmd)
 	}
 }
 
-// CheckAllTools checks all tools and returns results
-func CheckAllTools() []ToolResult {
-	tools := AllTools()
-	results := make([]ToolResult, len(tools))
-	for i, t := range tools {
-		results[i] = ToolResult{
-			Tool:  t,
-			Found: CheckTool(t),
-		}
+func findCmd(name string) string {
+	path, err := exec.LookPath(name)
+	if err != nil {
+		return ""
 	}
-	return results
+	return path
 }
 
 func checkCmd(name string) bool {
-	_, err := exec.LookPath(name)
-	return err == nil
+	return findCmd(name) != ""
 }
 
-func checkApp(name string) bool {
+func findApp(name string) string {
 	if runtime.GOOS != "darwin" {
-		return checkCmd(strings.ToLower(name))
+		return findCmd(strings.ToLower(name))
 	}
 	paths := []string{
 		filepath.Join("/Applications", name+".app"),
@@ -166,10 +301,10 @@ func checkApp(name string) bool {
 	}
 	for _, p := range paths {
 		if _, err := os.Stat(p); err == nil {
-			return true
+			return p
 		}
 	}
-	return false
+	return ""
 }
 
 // HasDocker returns true if Docker is available
@@ -205,17 +340,5 @@ func PlanEditorGUITools() []Tool {
 
 // CheckPlanEditors checks all plan editors and returns results
 func CheckPlanEditors() (terminal []ToolResult, gui []ToolResult) {
-	for _, t := range PlanEditorTerminalTools() {
-		terminal = append(terminal, ToolResult{
-			Tool:  t,
-			Found: CheckTool(t),
-		})
-	}
-	for _, t := range PlanEditorGUITools() {
-		gui = append(gui, ToolResult{
-			Tool:  t,
-			Found: CheckTool(t),
-		})
-	}
-	return terminal, gui
+	return CheckTools(PlanEditorTerminalTools(), DefaultTimeout), CheckTools(PlanEditorGUITools(), DefaultTimeout)
 }
@@ -0,0 +1,69 @@
+package doctor
+
+import "testing"
+
+func TestParseVersion(t *testing.T) {
+	for _, tc := range []struct {
+		tool, out, want string
+	}{
+		{"git", "git version 2.39.5\n", "2.39.5"},
+		{"git on macOS", "git version 2.39.3 (Apple Git-146)\n", "2.39.3"},
+		{"git on Windows", "git version 2.42.0.windows.2\n", "2.42.0"},
+		{"quarto", "1.4.550\n", "1.4.550"},
+		{"R", "R version 4.3.1 (2023-06-16) -- \"Beagle Scouts\"\nCopyright (C) 2023 The R Foundation for Statistical Computing\nPlatform: aarch64-apple-darwin20 (64-bit)\n", "4.3.1"},
+		{"old R", "R version 3.6.3 (2020-02-29) -- \"Holding the Windsock\"\n", "3.6.3"},
+		{"python3", "Python 3.11.7\n", "3.11.7"},
+		{"python3 release candidate", "Python 3.13.0rc1\n", "3.13.0"},
+		{"no version", "python3: command not found\n", ""},
+		{"single number", "tool 7\n", ""},
+	} {
+		t.Run(tc.tool, func(t *testing.T) {
+			if got := ParseVersion(tc.out); got != tc.want {
+				t.Errorf("ParseVersion(%q) = %q, want %q", tc.out, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestAtLeast(t *testing.T) {
+	for _, tc := range []struct {
+		version, min string
+		want         bool
+	}{
+		{"2.39.5", "2.28", true},
+		{"2.28", "2.28", true},
+		{"2.28.0", "2.28", true},
+		{"2.28", "2.28.0", true}, // missing fields count as zero
+		{"2.27.9", "2.28", false},
+		{"1.10.0", "1.3", true}, // numeric, not lexical
+		{"1.2.475", "1.3", false},
+		{"3.10.1", "3.9", true},
+		{"3.8.18", "3.9", false},
+		{"4", "4.1", false},
+		{"5", "4.1", true},
+		{"4.1", "", true},
+	} {
+		if got := AtLeast(tc.version, tc.min); got != tc.want {
+			t.Errorf("AtLeast(%q, %q) = %v, want %v", tc.version, tc.min, got, tc.want)
+		}
+	}
+}
+
+func TestCheckComparesWithMinVersion(t *testing.T) {
+	for _, tc := range []struct {
+		min  string
+		want bool
+	}{
+		{"", true},
+		{"1.0", true},
+		{"999.0", false},
+	} {
+		r := Check(t.Context(), Tool{Name: "Go", Cmd: "go", MinVersion: tc.min, VersionFlag: "version"})
+		if !r.Found {
+			t.Skip("go not on PATH")
+		}
+		if r.Version == "" || r.OK != tc.want {
+			t.Errorf("min %q: version %q, ok %v, want ok %v (%s)", tc.min, r.Version, r.OK, tc.want, r.Error)
+		}
+	}
+}
@@ -0,0 +1,579 @@
+// Package export bundles a project with its history and logs for sharing,
+// as a zip, a gzipped tar or an RO-Crate.
+package export
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/drpedapati/irl-template/pkg/archive"
+	"github.com/drpedapati/irl-template/pkg/gitutil"
+	"github.com/drpedapati/irl-template/pkg/provenance"
+)
+
+// Bundle formats
+const (
+	FormatZip     = "zip"
+	FormatTar     = "tar"
+	FormatROCrate = "ro-crate"
+)
+
+// Formats lists the bundle formats
+var Formats = []string{FormatZip, FormatTar, FormatROCrate}
+
+// How git history is included
+const (
+	HistoryBundle  = "bundle"
+	HistoryPatches = "patches"
+	HistoryNone    = "none"
+)
+
+// HistoryModes lists the ways git history can be included
+var HistoryModes = []string{HistoryBundle, HistoryPatches, HistoryNone}
+
+// Files generated into every bundle
+const (
+	ReadmeName = "EXPORT-README.md"
+	CrateName  = "ro-crate-metadata.json"
+	HistoryDir = "history"
+	patchesDir = HistoryDir + "/patches"
+)
+
+// generatedMode is the permission of files irl writes into the bundle
+const generatedMode = 0644
+
+// ValidFormat reports whether format is a known bundle format
+func ValidFormat(format string) bool {
+	return contains(Formats, format)
+}
+
+// ValidHistory reports whether mode is a known history mode
+func ValidHistory(mode string) bool {
+	return contains(HistoryModes, mode)
+}
+
+// FileName returns the bundle name for a project exported at t,
+// e.g. my-study_export_20260202.zip
+func FileName(project, format string, t time.Time) string {
+	name := project + "_export_" + t.Format("20060102")
+	switch format {
+	case FormatTar:
+		return name + ".tar.gz"
+	case FormatROCrate:
+		return name + ".crate.zip"
+	}
+	return name + ".zip"
+}
+
+// Options controls what goes into a bundle
+type Options struct {
+	Format  string   // one of Formats; zip when empty
+	History string   // one of HistoryModes; bundle when empty
+	Include []string // when set, only project paths matching one of these are bundled
+	Exclude []string // project paths matching any of these are left out
+	NoRaw   bool     // leave out 02-data/raw, e.g. when the data is restricted
+
+	// ForceHistory exports the history even when it holds excluded files,
+	// which can then be recovered from it
+	ForceHistory bool
+}
+
+// Result describes a written bundle
+type Result struct {
+	Path     string   `json:"path"`
+	Format   string   `json:"format"`
+	Project  string   `json:"project"`
+	Files    int      `json:"files"`
+	Bytes    int64    `json:"bytes"` // uncompressed
+	Excluded int      `json:"excluded"`
+	GitHead  string   `json:"git_head,omitempty"`
+	History  string   `json:"history,omitempty"` // path of the bundle or patch folder inside the export
+	Warnings []string `json:"warnings"`
+}
+
+// Export writes a bundle of projectDir to dest. It holds the project's
+// files less the excluded ones and .git, the git history as a bundle or
+// patch series, a generated EXPORT-README.md describing the loop history,
+// an RO-Crate ro-crate-metadata.json for the ro-crate format, and an
+// irl-manifest.json with the SHA-256 of every file, the same manifest
+// irl archive writes. The bundle is written to a temporary file and
+// renamed into place.
+func Export(projectDir, dest string, opts Options) (*Result, error) {
+	projectDir, err := filepath.Abs(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	dest, err = filepath.Abs(dest)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Format == "" {
+		opts.Format = FormatZip
+	}
+	if opts.History == "" {
+		opts.History = HistoryBundle
+	}
+	if !ValidFormat(opts.Format) {
+		return nil, fmt.Errorf("unknown format %q (use %s)", opts.Format, strings.Join(Formats, ", "))
+	}
+	if !ValidHistory(opts.History) {
+		return nil, fmt.Errorf("unknown history mode %q (use %s)", opts.History, strings.Join(HistoryModes, ", "))
+	}
+	rules := newRules(opts)
+
+	project := filepath.Base(projectDir)
+	res := &Result{Format: opts.Format, Project: project, Warnings: []string{}}
+	m := &archive.Manifest{
+		Version: 1, // the archive manifest format
+		Project: project,
+		Path:    projectDir,
+		Created: time.Now(),
+		Files:   []archive.File{},
+	}
+	isRepo := gitutil.IsRepo(projectDir)
+	if isRepo {
+		m.GitHead, _ = gitutil.Head(projectDir)
+		res.GitHead = m.GitHead
+		if clean, err := gitutil.IsClean(projectDir); err == nil && !clean {
+			res.Warnings = append(res.Warnings, "the project has uncommitted changes; they're exported but not in the history")
+		}
+	}
+	if isRepo && opts.History != HistoryNone {
+		if leaked := trackedExcluded(projectDir, rules); len(leaked) > 0 {
+			what := "excluded " + leaked[0] + " was committed"
+			if len(leaked) > 1 {
+				what = fmt.Sprintf("%d excluded files, e.g. %s, were committed", len(leaked), leaked[0])
+			}
+			if !opts.ForceHistory {
+				return nil, fmt.Errorf("%s and would be recoverable from the history (use --history none, or --force-history to export it anyway)", what)
+			}
+			res.Warnings = append(res.Warnings, what+" and can be recovered from the history")
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return nil, err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(dest), ".export-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name()) // no-op after the rename
+
+	var w bundleWriter
+	if opts.Format == FormatTar {
+		w = newTarWriter(tmp)
+	} else {
+		w = newZipWriter(tmp)
+	}
+	b := &builder{w: w, m: m}
+
+	err = b.addProject(projectDir, rules, map[string]bool{dest: true, tmp.Name(): true}, res)
+	if err == nil && len(opts.Include) > 0 && len(m.Files) == 0 {
+		err = fmt.Errorf("no project files match %s", strings.Join(opts.Include, ", "))
+	}
+	if err == nil && opts.History != HistoryNone {
+		err = b.addHistory(projectDir, opts.History, isRepo, res)
+	}
+	if err == nil {
+		err = b.addBytes(ReadmeName, []byte(readme(projectDir, opts, rules, m, res)))
+	}
+	if err == nil && opts.Format == FormatROCrate {
+		var crate []byte
+		if crate, err = roCrate(projectDir, m); err == nil {
+			err = b.addBytes(CrateName, crate)
+		}
+	}
+	if err == nil {
+		err = b.addManifest()
+	}
+	if cerr := w.Close(); err == nil {
+		err = cerr
+	}
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Rename(tmp.Name(), dest); err != nil {
+		return nil, err
+	}
+
+	res.Path = dest
+	res.Files = len(m.Files)
+	res.Bytes = m.TotalSize()
+	return res, nil
+}
+
+// rules decides which project paths are bundled
+type rules struct {
+	include []string
+	exclude []string
+}
+
+func newRules(opts Options) *rules {
+	r := &rules{include: opts.Include, exclude: append([]string{}, opts.Exclude...)}
+	if opts.NoRaw {
+		r.exclude = append(r.exclude, provenance.RawDir)
+	}
+	return r
+}
+
+// excluded reports whether a slash-separated project path is left out.
+// Include rules pick files; a folder is only left out by them when no
+// include rule could match anything below it.
+func (r *rules) excluded(rel string, dir bool) bool {
+	for _, p := range r.exclude {
+		if matchRule(p, rel) {
+			return true
+		}
+	}
+	if len(r.include) == 0 {
+		return false
+	}
+	for _, p := range r.include {
+		if matchRule(p, rel) || (dir && matchBelow(p, rel)) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchRule matches a glob against a path, the path's folders and its
+// file name, so "02-data/raw", "*.csv" and "03-outputs/*.html" all work
+func matchRule(pattern, rel string) bool {
+	pattern = strings.Trim(filepath.ToSlash(pattern), "/")
+	if ok, _ := path.Match(pattern, path.Base(rel)); ok {
+		return true
+	}
+	for p := rel; p != "." && p != "/"; p = path.Dir(p) {
+		if ok, _ := path.Match(pattern, p); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// matchBelow reports whether a rule could match a path below the folder
+// dir. Rules without a slash match file names anywhere; others must match
+// dir's folders one by one, as path.Match never crosses a slash.
+func matchBelow(pattern, dir string) bool {
+	pattern = strings.Trim(filepath.ToSlash(pattern), "/")
+	if !strings.Contains(pattern, "/") {
+		return true
+	}
+	pp, dp := strings.Split(pattern, "/"), strings.Split(dir, "/")
+	if len(dp) >= len(pp) {
+		return false // matchRule covers dir and its folders
+	}
+	for i, part := range dp {
+		if ok, _ := path.Match(pp[i], part); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// trackedExcluded lists the excluded paths committed anywhere in HEAD's
+// history, which an exported history would carry whatever the file tree
+// leaves out
+func trackedExcluded(projectDir string, r *rules) []string {
+	if top, err := gitutil.Run(projectDir, "rev-parse", "--show-toplevel"); err != nil || !samePath(top, projectDir) {
+		return nil // no history is exported from a nested project
+	}
+	out, err := gitutil.Run(projectDir, "log", "HEAD", "--format=", "--name-only", "-z")
+	if err != nil {
+		return nil
+	}
+	seen := map[string]bool{}
+	var leaked []string
+	for _, f := range strings.Split(out, "\x00") {
+		f = strings.TrimSpace(f)
+		if f != "" && !seen[f] && r.excluded(f, false) {
+			seen[f] = true
+			leaked = append(leaked, f)
+		}
+	}
+	sort.Strings(leaked)
+	return leaked
+}
+
+// builder adds entries to a bundle and records them in its manifest
+type builder struct {
+	w bundleWriter
+	m *archive.Manifest
+}
+
+// reserved names are generated into the bundle, so project files with
+// these names are left out
+func reserved(rel string) bool {
+	return rel == ReadmeName || rel == CrateName || rel == archive.ManifestName ||
+		rel == HistoryDir || strings.HasPrefix(rel, HistoryDir+"/")
+}
+
+func (b *builder) addProject(projectDir string, r *rules, skip map[string]bool, res *Result) error {
+	return filepath.WalkDir(projectDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(projectDir, p)
+		if err != nil || rel == "." {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if d.IsDir() && d.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		if skip[p] || reserved(rel) {
+			return nil
+		}
+		if r.excluded(rel, d.IsDir()) {
+			if d.IsDir() {
+				res.Excluded += countFiles(p)
+				return filepath.SkipDir
+			}
+			res.Excluded++
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if err := b.addPath(p, rel, info); err != nil {
+			return fmt.Errorf("%s: %w", rel, err)
+		}
+		return nil
+	})
+}
+
+// addPath stores a walked file or symlink. Folders are implied by the
+// files in them; other file types are skipped.
+func (b *builder) addPath(src, name string, info fs.FileInfo) error {
+	switch {
+	case info.Mode()&fs.ModeSymlink != 0:
+		target, err := os.Readlink(src)
+		if err != nil {
+			return err
+		}
+		if err := b.w.Link(name, target, info.ModTime()); err != nil {
+			return err
+		}
+		sum := sha256.Sum256([]byte(target))
+		b.m.Files = append(b.m.Files, archive.File{Path: name, Size: int64(len(target)), SHA256: hex.EncodeToString(sum[:]), Link: true})
+		return nil
+
+	case info.Mode().IsRegular():
+		in, err := os.Open(src)
+		if err != nil {
+			return err
+		}
+		defer in.Close()
+		return b.add(name, info.Mode().Perm(), info.ModTime(), info.Size(), in)
+	}
+	return nil
+}
+
+func (b *builder) addBytes(name string, data []byte) error {
+	return b.add(name, generatedMode, b.m.Created, int64(len(data)), bytes.NewReader(data))
+}
+
+func (b *builder) add(name string, perm fs.FileMode, mod time.Time, size int64, r io.Reader) error {
+	h := sha256.New()
+	n, err := b.w.File(name, perm, mod, size, io.TeeReader(r, h))
+	if err != nil {
+		return err
+	}
+	b.m.Files = append(b.m.Files, archive.File{Path: name, Size: n, SHA256: hex.EncodeToString(h.Sum(nil))})
+	return nil
+}
+
+func (b *builder) addManifest() error {
+	data, err := json.MarshalIndent(b.m, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	_, err = b.w.File(archive.ManifestName, generatedMode, b.m.Created, int64(len(data)), bytes.NewReader(data))
+	return err
+}
+
+// addHistory adds the project's git history as a bundle or patch series
+func (b *builder) addHistory(projectDir, mode string, isRepo bool, res *Result) error {
+	if !isRepo {
+		res.Warnings = append(res.Warnings, "not a git repository; no history was exported")
+		return nil
+	}
+	if top, err := gitutil.Run(projectDir, "rev-parse", "--show-toplevel"); err != nil || !samePath(top, projectDir) {
+		res.Warnings = append(res.Warnings, "the project is inside another git repository; no history was exported")
+		return nil
+	}
+	if res.GitHead == "" {
+		res.Warnings = append(res.Warnings, "the repository has no commits; no history was exported")
+		return nil
+	}
+
+	work, err := os.MkdirTemp("", "irl-export-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(work)
+
+	if mode == HistoryBundle {
+		name := HistoryDir + "/" + res.Project + ".bundle"
+		file := filepath.Join(work, "history.bundle")
+		// Only the checked-out history, not stashes or other refs
+		refs := []string{"bundle", "create", "-q", file, "HEAD"}
+		if branch, err := gitutil.Run(projectDir, "symbolic-ref", "-q", "--short", "HEAD"); err == nil && branch != "" {
+			refs = append(refs, branch)
+		}
+		if _, err := gitutil.Run(projectDir, refs...); err != nil {
+			return err
+		}
+		res.History = name
+		return b.addFile(file, name)
+	}
+
+	if _, err := gitutil.Run(projectDir, "format-patch", "-q", "--root", "-o", work, "HEAD"); err != nil {
+		return err
+	}
+	entries, err := os.ReadDir(work)
+	if err != nil {
+		return err
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+	for _, e := range entries {
+		if err := b.addFile(filepath.Join(work, e.Name()), patchesDir+"/"+e.Name()); err != nil {
+			return err
+		}
+	}
+	res.History = patchesDir
+	return nil
+}
+
+// addFile stores a generated file, dated with the export
+func (b *builder) addFile(src, name string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+	return b.add(name, generatedMode, b.m.Created, info.Size(), in)
+}
+
+// bundleWriter writes entries to a zip or a gzipped tar
+type bundleWriter interface {
+	File(name string, perm fs.FileMode, mod time.Time, size int64, r io.Reader) (int64, error)
+	Link(name, target string, mod time.Time) error
+	Close() error
+}
+
+type zipWriter struct{ zw *zip.Writer }
+
+func newZipWriter(w io.Writer) *zipWriter {
+	return &zipWriter{zw: zip.NewWriter(w)}
+}
+
+func (z *zipWriter) File(name string, perm fs.FileMode, mod time.Time, size int64, r io.Reader) (int64, error) {
+	hdr := &zip.FileHeader{Name: name, Method: zip.Deflate, Modified: mod}
+	hdr.SetMode(perm)
+	w, err := z.zw.CreateHeader(hdr)
+	if err != nil {
+		return 0, err
+	}
+	return io.Copy(w, r)
+}
+
+// Link stores a symlink with its target as content, as irl archive does
+func (z *zipWriter) Link(name, target string, mod time.Time) error {
+	hdr := &zip.FileHeader{Name: name, Modified: mod}
+	hdr.SetMode(fs.ModeSymlink | 0777)
+	w, err := z.zw.CreateHeader(hdr)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, target)
+	return err
+}
+
+func (z *zipWriter) Close() error {
+	return z.zw.Close()
+}
+
+type tarWriter struct {
+	gz *gzip.Writer
+	tw *tar.Writer
+}
+
+func newTarWriter(w io.Writer) *tarWriter {
+	gz := gzip.NewWriter(w)
+	return &tarWriter{gz: gz, tw: tar.NewWriter(gz)}
+}
+
+func (t *tarWriter) File(name string, perm fs.FileMode, mod time.Time, size int64, r io.Reader) (int64, error) {
+	hdr := &tar.Header{Name: name, Mode: int64(perm), ModTime: mod, Size: size, Typeflag: tar.TypeReg, Format: tar.FormatPAX}
+	if err := t.tw.WriteHeader(hdr); err != nil {
+		return 0, err
+	}
+	// The header fixed the size; a file that changed since could overrun it
+	return io.CopyN(t.tw, r, size)
+}
+
+func (t *tarWriter) Link(name, target string, mod time.Time) error {
+	return t.tw.WriteHeader(&tar.Header{Name: name, Linkname: target, Mode: 0777, ModTime: mod, Typeflag: tar.TypeSymlink, Format: tar.FormatPAX})
+}
+
+func (t *tarWriter) Close() error {
+	if err := t.tw.Close(); err != nil {
+		return err
+	}
+	return t.gz.Close()
+}
+
+// countFiles counts the files below dir, for reporting what was excluded
+func countFiles(dir string) int {
+	n := 0
+	filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			n++
+		}
+		return nil
+	})
+	return n
+}
+
+func samePath(a, b string) bool {
+	ra, err1 := filepath.EvalSymlinks(a)
+	rb, err2 := filepath.EvalSymlinks(b)
+	if err1 != nil || err2 != nil {
+		return filepath.Clean(a) == filepath.Clean(b)
+	}
+	return ra == rb
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
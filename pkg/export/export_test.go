@@ -0,0 +1,167 @@
+package export
+
+import (
+	"archive/zip"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/drpedapati/irl-template/pkg/gitutil"
+)
+
+func TestMatchRule(t *testing.T) {
+	tests := []struct {
+		pattern, rel string
+		want         bool
+	}{
+		{"02-data/raw", "02-data/raw", true},
+		{"02-data/raw", "02-data/raw/eeg/s01.set", true},
+		{"02-data/raw/", "02-data/raw/s01.csv", true},
+		{"02-data/raw", "02-data/rawish.csv", false},
+		{"*.csv", "02-data/clean/table.csv", true},
+		{"*.csv", "table.csv.bak", false},
+		{"03-outputs/*.html", "03-outputs/report.html", true},
+		{"03-outputs/*.html", "03-outputs/sub/report.html", false},
+		{"plans", "plans/main-plan.md", true},
+		{"plans", "old/plans.md", false},
+	}
+	for _, tt := range tests {
+		if got := matchRule(tt.pattern, tt.rel); got != tt.want {
+			t.Errorf("matchRule(%q, %q) = %v, want %v", tt.pattern, tt.rel, got, tt.want)
+		}
+	}
+}
+
+func TestRulesExcluded(t *testing.T) {
+	r := newRules(Options{Include: []string{"03-outputs/*.html", "plans/*.md"}, Exclude: []string{"*draft*"}, NoRaw: true})
+	tests := []struct {
+		rel  string
+		dir  bool
+		want bool
+	}{
+		{"plans", true, false},
+		{"plans/main-plan.md", false, false},
+		{"plans/draft-plan.md", false, true}, // exclude wins
+		{"03-outputs", true, false},          // an include rule reaches below it
+		{"03-outputs/report.html", false, false},
+		{"03-outputs/report.pdf", false, true},
+		{"03-outputs/figures", true, true}, // nothing included can be below it
+		{"02-data", true, true},
+		{"02-data/raw/s01.csv", false, true},
+		{"README.md", false, true},
+	}
+	for _, tt := range tests {
+		if got := r.excluded(tt.rel, tt.dir); got != tt.want {
+			t.Errorf("excluded(%q, dir=%v) = %v, want %v", tt.rel, tt.dir, got, tt.want)
+		}
+	}
+
+	// Name-only include rules match files anywhere, so every folder is searched
+	r = newRules(Options{Include: []string{"*.html"}})
+	if r.excluded("03-outputs/sub", true) || r.excluded("03-outputs/sub/r.html", false) || !r.excluded("03-outputs/sub/r.pdf", false) {
+		t.Error("*.html doesn't pick HTML files in nested folders")
+	}
+}
+
+// newProject makes a committed project holding files
+func newProject(t *testing.T, files map[string]string) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := filepath.Join(t.TempDir(), "study")
+	for name, body := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		os.MkdirAll(filepath.Dir(p), 0755)
+		if err := os.WriteFile(p, []byte(body), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"config", "user.name", "Test"},
+		{"config", "user.email", "test@example.org"},
+		{"add", "-A"},
+		{"commit", "-qm", "init"},
+	} {
+		if _, err := gitutil.Run(dir, args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func zipNames(t *testing.T, file string) []string {
+	t.Helper()
+	zr, err := zip.OpenReader(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer zr.Close()
+	var names []string
+	for _, f := range zr.File {
+		names = append(names, f.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestExportNoRawRefusesCommittedRawData(t *testing.T) {
+	dir := newProject(t, map[string]string{
+		"plans/main-plan.md":  "# Plan\n",
+		"02-data/raw/s01.csv": "id,value\n1,secret\n",
+	})
+	dest := filepath.Join(t.TempDir(), "out.zip")
+
+	_, err := Export(dir, dest, Options{NoRaw: true})
+	if err == nil || !strings.Contains(err.Error(), "02-data/raw/s01.csv") || !strings.Contains(err.Error(), "--history none") {
+		t.Fatalf("Export with committed raw data: err = %v, want a refusal naming the file", err)
+	}
+	if _, err := os.Stat(dest); !os.IsNotExist(err) {
+		t.Error("the refused export left a file behind")
+	}
+
+	res, err := Export(dir, dest, Options{NoRaw: true, History: HistoryNone})
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := strings.Join(zipNames(t, dest), " ")
+	if strings.Contains(names, "02-data/raw") || strings.Contains(names, HistoryDir+"/") {
+		t.Errorf("export without history holds %s", names)
+	}
+	if !strings.Contains(names, "plans/main-plan.md") || res.Excluded != 1 {
+		t.Errorf("export holds %s with %d excluded, want the plan and 1 excluded", names, res.Excluded)
+	}
+
+	res, err = Export(dir, dest, Options{NoRaw: true, ForceHistory: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.History == "" || len(res.Warnings) == 0 || !strings.Contains(strings.Join(res.Warnings, " "), "recovered") {
+		t.Errorf("forced export: history %q, warnings %q", res.History, res.Warnings)
+	}
+}
+
+func TestExportNoRawKeepsHistoryWhenRawWasNeverCommitted(t *testing.T) {
+	dir := newProject(t, map[string]string{
+		"plans/main-plan.md": "# Plan\n",
+		".gitignore":         "02-data/raw/\n",
+	})
+	os.MkdirAll(filepath.Join(dir, "02-data", "raw"), 0755)
+	os.WriteFile(filepath.Join(dir, "02-data", "raw", "s01.csv"), []byte("secret"), 0644)
+	dest := filepath.Join(t.TempDir(), "out.zip")
+
+	res, err := Export(dir, dest, Options{NoRaw: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.History != HistoryDir+"/study.bundle" {
+		t.Errorf("history = %q", res.History)
+	}
+	if names := strings.Join(zipNames(t, dest), " "); strings.Contains(names, "02-data/raw") {
+		t.Errorf("export holds raw data: %s", names)
+	}
+}
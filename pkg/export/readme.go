@@ -0,0 +1,186 @@
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/drpedapati/irl-template/pkg/archive"
+	"github.com/drpedapati/irl-template/pkg/decisions"
+	"github.com/drpedapati/irl-template/pkg/gitutil"
+	"github.com/drpedapati/irl-template/pkg/plan"
+	"github.com/drpedapati/irl-template/pkg/projects"
+	"github.com/drpedapati/irl-template/pkg/provenance"
+	"github.com/drpedapati/irl-template/pkg/status"
+)
+
+// loopEntry is one row of a plan log
+type loopEntry struct {
+	Date, Summary, Hash string
+}
+
+// readme describes the bundle: the project, its loop history and
+// decisions, what was left out, and how to get the history back
+func readme(projectDir string, opts Options, r *rules, m *archive.Manifest, res *Result) string {
+	var b strings.Builder
+	meta, _ := projects.LoadMeta(projectDir)
+	if meta == nil {
+		meta = &projects.Meta{}
+	}
+
+	b.WriteString("# " + res.Project + "\n\n")
+	b.WriteString("Exported with irl on " + m.Created.Format("2006-01-02 15:04") + ".\n")
+	if meta.Purpose != "" {
+		b.WriteString("\n" + meta.Purpose + "\n")
+	}
+
+	b.WriteString("\n| | |\n|---|---|\n")
+	row := func(k, v string) {
+		if v != "" {
+			b.WriteString("| " + k + " | " + strings.ReplaceAll(v, "|", "\\|") + " |\n")
+		}
+	}
+	row("Owner", meta.Owner)
+	row("Collaborators", strings.Join(meta.Collaborators, ", "))
+	row("Status", meta.Status)
+	row("Tags", strings.Join(meta.Tags, ", "))
+	row("Template", meta.Template)
+	if res.GitHead != "" {
+		row("Git commit", "`"+gitutil.Short(res.GitHead)+"`")
+	}
+	row("Files", fmt.Sprintf("%d (%s)", len(m.Files), status.FormatSize(m.TotalSize())))
+
+	// Loop history from every plan log next to the main plan
+	b.WriteString("\n## Loop history\n")
+	logs := planLogs(projectDir)
+	if len(logs) == 0 {
+		b.WriteString("\nNo loops were logged.\n")
+	}
+	for _, name := range sortedKeys(logs) {
+		if len(logs) > 1 {
+			b.WriteString("\n### " + name + "\n")
+		}
+		b.WriteString("\n| Date | Change | Commit |\n|---|---|---|\n")
+		for _, e := range logs[name] {
+			b.WriteString(fmt.Sprintf("| %s | %s | `%s` |\n", e.Date, strings.ReplaceAll(e.Summary, "|", "\\|"), e.Hash))
+		}
+	}
+
+	b.WriteString("\n## Decisions\n\n")
+	if list, _ := decisions.Load(projectDir); len(list) == 0 {
+		b.WriteString("No decisions were logged.\n")
+	} else {
+		for _, d := range list {
+			b.WriteString("- " + d.Timestamp + " — **" + d.Decision + "** " + d.Rationale + "\n")
+		}
+		b.WriteString("\nSee `" + decisions.File + "` for evidence and validation.\n")
+	}
+
+	b.WriteString("\n## Contents\n\n")
+	if len(r.exclude) > 0 || len(r.include) > 0 {
+		if res.Excluded == 1 {
+			b.WriteString("1 project file was left out of this export")
+		} else {
+			b.WriteString(fmt.Sprintf("%d project files were left out of this export", res.Excluded))
+		}
+		if len(r.include) > 0 {
+			b.WriteString(", which holds only " + codeList(r.include))
+			if len(r.exclude) > 0 {
+				b.WriteString(" less " + codeList(r.exclude))
+			}
+		} else {
+			b.WriteString(": " + codeList(r.exclude))
+		}
+		b.WriteString(".\n")
+		if opts.NoRaw && !r.excluded(provenance.ManifestFile, false) {
+			if _, err := os.Stat(provenance.ManifestPath(projectDir)); err == nil {
+				b.WriteString("The raw data isn't included, but `" + provenance.ManifestFile +
+					"` records the SHA-256 of every raw file so a copy obtained separately can be checked with `irl data verify`.\n")
+			}
+		}
+		b.WriteString("\n")
+	}
+	switch {
+	case res.History != "" && opts.History == HistoryBundle:
+		b.WriteString("The git history is in `" + res.History + "`. Recover it with:\n\n")
+		b.WriteString("```bash\ngit clone " + res.History + " " + res.Project + "\n```\n")
+	case res.History != "":
+		b.WriteString("The git history is a patch series in `" + res.History + "`. Replay it with:\n\n")
+		b.WriteString("```bash\ngit init " + res.Project + " && cd " + res.Project + "\ngit am ../" + res.History + "/*.patch\n```\n")
+	default:
+		b.WriteString("The git history isn't included.\n")
+	}
+	b.WriteString("\n`" + archive.ManifestName + "` lists the SHA-256 of every file.")
+	if opts.Format != FormatTar {
+		b.WriteString(" Check them with `irl restore <bundle> --check`.")
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// planLogs reads the loop logs next to the project's plan, keyed by plan
+// name, e.g. "main-plan"
+func planLogs(projectDir string) map[string][]loopEntry {
+	planPath, err := plan.Find(projectDir)
+	if err != nil {
+		return nil
+	}
+	files, _ := filepath.Glob(filepath.Join(filepath.Dir(planPath), "*-log.csv"))
+	logs := map[string][]loopEntry{}
+	for _, f := range files {
+		entries := readPlanLog(f)
+		if len(entries) > 0 {
+			logs[strings.TrimSuffix(filepath.Base(f), "-log.csv")] = entries
+		}
+	}
+	return logs
+}
+
+// readPlanLog reads the date,change_summary,git_hash rows irl loop writes
+func readPlanLog(path string) []loopEntry {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	r := csv.NewReader(f)
+	r.FieldsPerRecord = -1
+	records, err := r.ReadAll()
+	if err != nil || len(records) < 2 {
+		return nil
+	}
+	var entries []loopEntry
+	for _, rec := range records[1:] {
+		if len(rec) < 3 {
+			continue
+		}
+		entries = append(entries, loopEntry{Date: rec[0], Summary: rec[1], Hash: rec[2]})
+	}
+	return entries
+}
+
+func codeList(list []string) string {
+	quoted := make([]string, len(list))
+	for i, s := range list {
+		quoted[i] = "`" + s + "`"
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// sortedKeys orders plan names with the main plan first
+func sortedKeys(m map[string][]loopEntry) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i] == "main-plan" || keys[j] == "main-plan" {
+			return keys[i] == "main-plan"
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
+}
@@ -0,0 +1,122 @@
+package export
+
+import (
+	"encoding/json"
+	"mime"
+	"path"
+	"strings"
+
+	"github.com/drpedapati/irl-template/pkg/archive"
+	"github.com/drpedapati/irl-template/pkg/config"
+	"github.com/drpedapati/irl-template/pkg/naming"
+	"github.com/drpedapati/irl-template/pkg/projects"
+)
+
+const (
+	crateContext = "https://w3id.org/ro/crate/1.1/context"
+	crateSpec    = "https://w3id.org/ro/crate/1.1"
+)
+
+// entity is a node in the RO-Crate JSON-LD graph
+type entity map[string]any
+
+func ref(id string) entity {
+	return entity{"@id": id}
+}
+
+// roCrate builds ro-crate-metadata.json describing the bundle: the
+// project as the root dataset, its owner and collaborators as people
+// with the profile's affiliation for the owner, and every bundled file
+func roCrate(projectDir string, m *archive.Manifest) ([]byte, error) {
+	meta, _ := projects.LoadMeta(projectDir)
+	if meta == nil {
+		meta = &projects.Meta{}
+	}
+	profile := config.GetProfile()
+
+	root := entity{
+		"@id":           "./",
+		"@type":         "Dataset",
+		"name":          m.Project,
+		"datePublished": m.Created.Format("2006-01-02"),
+	}
+	if meta.Purpose != "" {
+		root["description"] = meta.Purpose
+	}
+	if len(meta.Tags) > 0 {
+		root["keywords"] = strings.Join(meta.Tags, ", ")
+	}
+	if !meta.Created.IsZero() {
+		root["dateCreated"] = meta.Created.Format("2006-01-02")
+	}
+	if m.GitHead != "" {
+		root["version"] = m.GitHead
+	}
+
+	graph := []entity{
+		{
+			"@id":        "ro-crate-metadata.json",
+			"@type":      "CreativeWork",
+			"conformsTo": ref(crateSpec),
+			"about":      ref("./"),
+		},
+		root,
+	}
+
+	// The owner is the author; the profile fills in their details when
+	// it's theirs, or stands in when the project has no owner
+	owner := meta.Owner
+	if owner == "" {
+		owner = profile.Name
+	}
+	if owner != "" {
+		person := entity{"@id": personID(owner), "@type": "Person", "name": owner}
+		if owner == profile.Name {
+			if profile.Email != "" {
+				person["email"] = profile.Email
+			}
+			if profile.Title != "" {
+				person["jobTitle"] = profile.Title
+			}
+			if profile.Institution != "" {
+				org := profile.Institution
+				if profile.Department != "" {
+					org = profile.Department + ", " + profile.Institution
+				}
+				graph = append(graph, entity{"@id": "#" + naming.Slugify(org), "@type": "Organization", "name": org})
+				person["affiliation"] = ref("#" + naming.Slugify(org))
+			}
+		}
+		root["author"] = ref(personID(owner))
+		graph = append(graph, person)
+	}
+	var contributors []entity
+	for _, c := range meta.Collaborators {
+		contributors = append(contributors, ref(personID(c)))
+		graph = append(graph, entity{"@id": personID(c), "@type": "Person", "name": c})
+	}
+	if len(contributors) > 0 {
+		root["contributor"] = contributors
+	}
+
+	parts := make([]entity, 0, len(m.Files))
+	for _, f := range m.Files {
+		parts = append(parts, ref(f.Path))
+		file := entity{"@id": f.Path, "@type": "File", "name": path.Base(f.Path), "contentSize": f.Size, "sha256": f.SHA256}
+		if t := mime.TypeByExtension(path.Ext(f.Path)); t != "" {
+			file["encodingFormat"] = strings.SplitN(t, ";", 2)[0]
+		}
+		graph = append(graph, file)
+	}
+	root["hasPart"] = parts
+
+	data, err := json.MarshalIndent(entity{"@context": crateContext, "@graph": graph}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+func personID(name string) string {
+	return "#" + naming.Slugify(name)
+}
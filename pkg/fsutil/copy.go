@@ -0,0 +1,405 @@
+// Package fsutil copies folder trees without shelling out to cp.
+package fsutil
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// SymlinkPolicy says what Copy does with symbolic links
+type SymlinkPolicy string
+
+const (
+	SymlinksKeep   SymlinkPolicy = "keep"   // recreate the link as it is
+	SymlinksFollow SymlinkPolicy = "follow" // copy what the link points to
+	SymlinksSkip   SymlinkPolicy = "skip"   // leave links out
+)
+
+// LinkMode says how Copy writes regular files
+type LinkMode string
+
+const (
+	LinkNone    LinkMode = ""        // copy the bytes
+	LinkHard    LinkMode = "hard"    // hard link to the source file
+	LinkReflink LinkMode = "reflink" // copy-on-write clone (APFS, Btrfs, XFS)
+)
+
+// DefaultExcludes are names that rarely belong in a copied project:
+// environments and caches that can be rebuilt
+var DefaultExcludes = []string{".venv", "venv", "node_modules", "__pycache__", ".DS_Store"}
+
+// DefaultLinkMin is the smallest file worth hard linking. Smaller files
+// are copied, so editing plans and scripts in the copy leaves the source
+// alone.
+const DefaultLinkMin = 1 << 20
+
+// progressEvery is how many bytes of a large file are copied between
+// progress reports
+const progressEvery = 4 << 20
+
+// Options control Copy
+type Options struct {
+	Exclude  []string      // globs matched against names and slash-separated relative paths
+	Symlinks SymlinkPolicy // default SymlinksKeep
+	Link     LinkMode      // hard link or clone files instead of copying them
+	LinkMin  int64         // files smaller than this are copied even with Link set
+	Progress func(Progress)
+}
+
+// Progress is reported as Copy works through the tree
+type Progress struct {
+	Files      int
+	TotalFiles int
+	Bytes      int64
+	TotalBytes int64
+	Path       string // file being copied, relative to the source
+}
+
+// Percent returns how far the copy is, by bytes, from 0 to 1
+func (p Progress) Percent() float64 {
+	if p.TotalBytes == 0 {
+		if p.TotalFiles == 0 {
+			return 1
+		}
+		return float64(p.Files) / float64(p.TotalFiles)
+	}
+	return float64(p.Bytes) / float64(p.TotalBytes)
+}
+
+// Result summarizes a finished copy
+type Result struct {
+	Files    int   // files and links written
+	Bytes    int64 // size of the files written
+	Linked   int   // files hard linked or cloned instead of copied
+	Excluded int   // entries left out by Options.Exclude
+	Skipped  int   // symlinks and special files (sockets, devices) left out
+}
+
+type item struct {
+	src  string // path to read, with followed links resolved by Stat
+	rel  string // slash-separated path below the source
+	info fs.FileInfo
+}
+
+type copier struct {
+	opts  Options
+	items []item
+	res   Result
+	prog  Progress
+}
+
+// Copy copies the folder src to dst, which must not exist. File modes
+// and modification times are preserved. If the copy fails, the partial
+// copy is removed.
+func Copy(src, dst string, opts Options) (*Result, error) {
+	if _, err := os.Lstat(dst); err == nil {
+		return nil, fmt.Errorf("%s already exists", dst)
+	}
+	c, info, err := scanTree(src, opts)
+	if err != nil {
+		return nil, err
+	}
+	c.report("")
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return nil, err
+	}
+	if err := os.Mkdir(dst, 0700); err != nil {
+		return nil, err
+	}
+	if err := c.copyAll(dst); err != nil {
+		os.RemoveAll(dst)
+		return nil, err
+	}
+	if err := setDirMeta(dst, info); err != nil {
+		os.RemoveAll(dst)
+		return nil, err
+	}
+	return &c.res, nil
+}
+
+// Count returns the result Copy would have, without writing anything
+func Count(src string, opts Options) (*Result, error) {
+	c, _, err := scanTree(src, opts)
+	if err != nil {
+		return nil, err
+	}
+	res := c.res
+	res.Files, res.Bytes = c.prog.TotalFiles, c.prog.TotalBytes
+	if opts.Link != LinkNone {
+		for _, it := range c.items {
+			if it.info.Mode().IsRegular() && it.info.Size() >= opts.LinkMin {
+				res.Linked++
+			}
+		}
+	}
+	return &res, nil
+}
+
+// List returns the files and links Copy would write, as slash-separated
+// paths relative to src in walk order
+func List(src string, opts Options) ([]string, error) {
+	c, _, err := scanTree(src, opts)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, it := range c.items {
+		if !it.info.IsDir() {
+			files = append(files, it.rel)
+		}
+	}
+	return files, nil
+}
+
+// scanTree checks the options and lists the tree below src
+func scanTree(src string, opts Options) (*copier, fs.FileInfo, error) {
+	if opts.Symlinks == "" {
+		opts.Symlinks = SymlinksKeep
+	}
+	switch opts.Symlinks {
+	case SymlinksKeep, SymlinksFollow, SymlinksSkip:
+	default:
+		return nil, nil, fmt.Errorf("unknown symlink policy %q (use keep, follow or skip)", opts.Symlinks)
+	}
+	switch opts.Link {
+	case LinkNone, LinkHard, LinkReflink:
+	default:
+		return nil, nil, fmt.Errorf("unknown link mode %q (use hard or reflink)", opts.Link)
+	}
+	for _, pattern := range opts.Exclude {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, nil, fmt.Errorf("bad exclude pattern %q: %w", pattern, err)
+		}
+	}
+
+	info, err := os.Stat(src)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !info.IsDir() {
+		return nil, nil, fmt.Errorf("not a directory: %s", src)
+	}
+
+	c := &copier{opts: opts}
+	real, _ := filepath.EvalSymlinks(src)
+	if err := c.scan(src, ".", map[string]bool{real: true}); err != nil {
+		return nil, nil, err
+	}
+	return c, info, nil
+}
+
+// scan lists what will be copied so progress can report totals. ancestors
+// holds the real paths of the folders above, to stop followed links from
+// looping.
+func (c *copier) scan(dir, rel string, ancestors map[string]bool) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("%s: %w", rel, err)
+	}
+	for _, e := range entries {
+		r := path.Join(rel, e.Name())
+		if c.excluded(e.Name(), r) {
+			c.res.Excluded++
+			continue
+		}
+		p := filepath.Join(dir, e.Name())
+		info, err := os.Lstat(p)
+		if err != nil {
+			return fmt.Errorf("%s: %w", r, err)
+		}
+
+		if info.Mode()&fs.ModeSymlink != 0 {
+			switch c.opts.Symlinks {
+			case SymlinksSkip:
+				c.res.Skipped++
+				continue
+			case SymlinksFollow:
+				// Broken links have nothing to follow and are kept as links
+				if target, err := os.Stat(p); err == nil {
+					info = target
+				}
+			}
+		}
+
+		switch {
+		case info.IsDir():
+			real, err := filepath.EvalSymlinks(p)
+			if err != nil {
+				return fmt.Errorf("%s: %w", r, err)
+			}
+			if ancestors[real] {
+				return fmt.Errorf("%s: symlink loop", r)
+			}
+			c.items = append(c.items, item{src: p, rel: r, info: info})
+			ancestors[real] = true
+			err = c.scan(p, r, ancestors)
+			delete(ancestors, real)
+			if err != nil {
+				return err
+			}
+		case info.Mode().IsRegular():
+			c.items = append(c.items, item{src: p, rel: r, info: info})
+			c.prog.TotalFiles++
+			c.prog.TotalBytes += info.Size()
+		case info.Mode()&fs.ModeSymlink != 0:
+			c.items = append(c.items, item{src: p, rel: r, info: info})
+			c.prog.TotalFiles++
+		default:
+			c.res.Skipped++
+		}
+	}
+	return nil
+}
+
+func (c *copier) excluded(name, rel string) bool {
+	for _, pattern := range c.opts.Exclude {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+		if ok, _ := path.Match(pattern, rel); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *copier) copyAll(dst string) error {
+	var dirs []item
+	for _, it := range c.items {
+		target := filepath.Join(dst, filepath.FromSlash(it.rel))
+		mode := it.info.Mode()
+		var err error
+		switch {
+		case mode.IsDir():
+			// Writable until its contents are in; the real mode is set last
+			err = os.Mkdir(target, 0700)
+			dirs = append(dirs, it)
+		case mode&fs.ModeSymlink != 0:
+			err = copyLink(it.src, target)
+			c.res.Files++
+			c.prog.Files++
+			c.report(it.rel)
+		default:
+			err = c.copyFile(it, target)
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", it.rel, err)
+		}
+	}
+	// Deepest folders first, so setting a parent's time isn't undone
+	for i := len(dirs) - 1; i >= 0; i-- {
+		it := dirs[i]
+		if err := setDirMeta(filepath.Join(dst, filepath.FromSlash(it.rel)), it.info); err != nil {
+			return fmt.Errorf("%s: %w", it.rel, err)
+		}
+	}
+	return nil
+}
+
+func (c *copier) copyFile(it item, target string) error {
+	size := it.info.Size()
+	defer func() {
+		c.res.Files++
+		c.prog.Files++
+		c.report(it.rel)
+	}()
+
+	if c.opts.Link != LinkNone && size >= c.opts.LinkMin {
+		if c.link(it, target) == nil {
+			c.res.Linked++
+			c.res.Bytes += size
+			c.prog.Bytes += size
+			return nil
+		}
+		// Fall back to copying, e.g. across filesystems
+	}
+
+	in, err := os.Open(it.src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(target, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	w := &progressWriter{c: c, rel: it.rel}
+	n, err := io.Copy(io.MultiWriter(out, w), in)
+	c.res.Bytes += n
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	return setFileMeta(target, it.info)
+}
+
+func (c *copier) link(it item, target string) error {
+	switch c.opts.Link {
+	case LinkHard:
+		return os.Link(it.src, target)
+	case LinkReflink:
+		if err := reflink(it.src, target); err != nil {
+			os.Remove(target)
+			return err
+		}
+		return setFileMeta(target, it.info)
+	}
+	return errors.ErrUnsupported
+}
+
+func (c *copier) report(rel string) {
+	if c.opts.Progress != nil {
+		c.prog.Path = rel
+		c.opts.Progress(c.prog)
+	}
+}
+
+// progressWriter counts copied bytes and reports every few megabytes
+type progressWriter struct {
+	c       *copier
+	rel     string
+	pending int64
+}
+
+func (w *progressWriter) Write(p []byte) (int, error) {
+	w.c.prog.Bytes += int64(len(p))
+	w.pending += int64(len(p))
+	if w.pending >= progressEvery {
+		w.pending = 0
+		w.c.report(w.rel)
+	}
+	return len(p), nil
+}
+
+func copyLink(src, target string) error {
+	link, err := os.Readlink(src)
+	if err != nil {
+		return err
+	}
+	return os.Symlink(link, target)
+}
+
+// permBits are the mode bits Copy preserves
+const permBits = fs.ModePerm | fs.ModeSetuid | fs.ModeSetgid | fs.ModeSticky
+
+func setFileMeta(target string, info fs.FileInfo) error {
+	if err := os.Chmod(target, info.Mode()&permBits); err != nil {
+		return err
+	}
+	return os.Chtimes(target, info.ModTime(), info.ModTime())
+}
+
+func setDirMeta(target string, info fs.FileInfo) error {
+	if err := os.Chtimes(target, info.ModTime(), info.ModTime()); err != nil {
+		return err
+	}
+	return os.Chmod(target, info.Mode()&permBits)
+}
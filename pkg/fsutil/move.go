@@ -0,0 +1,46 @@
+package fsutil
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// Move moves the folder src to dst, which must not exist. Within one
+// filesystem the folder is renamed and the result is nil. Across
+// filesystems everything, links included, is copied and the source is
+// removed once the copy succeeds.
+func Move(src, dst string, progress func(Progress)) (*Result, error) {
+	info, err := os.Stat(src)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("not a directory: %s", src)
+	}
+	if _, err := os.Lstat(dst); err == nil {
+		return nil, fmt.Errorf("%s already exists", dst)
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return nil, err
+	}
+
+	err = os.Rename(src, dst)
+	if err == nil {
+		return nil, nil
+	}
+	if !errors.Is(err, syscall.EXDEV) {
+		return nil, err
+	}
+
+	res, err := Copy(src, dst, Options{Symlinks: SymlinksKeep, Progress: progress})
+	if err != nil {
+		return nil, err
+	}
+	if err := os.RemoveAll(src); err != nil {
+		return res, fmt.Errorf("copied, but couldn't remove %s: %w", src, err)
+	}
+	return res, nil
+}
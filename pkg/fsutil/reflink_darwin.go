@@ -0,0 +1,8 @@
+package fsutil
+
+import "golang.org/x/sys/unix"
+
+// reflink clones src to dst with clonefile(2) (APFS)
+func reflink(src, dst string) error {
+	return unix.Clonefile(src, dst, unix.CLONE_NOFOLLOW)
+}
@@ -0,0 +1,10 @@
+//go:build !linux && !darwin
+
+package fsutil
+
+import "errors"
+
+// reflink isn't available here; Copy falls back to copying
+func reflink(src, dst string) error {
+	return errors.ErrUnsupported
+}
@@ -0,0 +1,236 @@
+// Package gitutil wraps the git command line for project repositories.
+package gitutil
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Run executes git in dir and returns its trimmed stdout.
+// On failure the error includes git's stderr.
+func Run(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		return "", fmt.Errorf("git %s: %s", args[0], msg)
+	}
+	return strings.TrimRight(stdout.String(), "\n"), nil
+}
+
+// IsRepo reports whether dir is inside a git work tree
+func IsRepo(dir string) bool {
+	out, err := Run(dir, "rev-parse", "--is-inside-work-tree")
+	return err == nil && out == "true"
+}
+
+// GitDir returns the absolute path of the repository's .git directory
+func GitDir(dir string) (string, error) {
+	return Run(dir, "rev-parse", "--absolute-git-dir")
+}
+
+// Head returns the full hash of HEAD
+func Head(dir string) (string, error) {
+	return Run(dir, "rev-parse", "HEAD")
+}
+
+// Short abbreviates a commit hash to 7 characters
+func Short(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
+
+// Change is one entry of git status. Paths are relative to the
+// repository root and never quoted.
+type Change struct {
+	Code string // two-letter porcelain status, e.g. " M", "??" or "R "
+	Path string
+	From string // the original path of a rename or copy
+}
+
+// String formats the change as a porcelain status line
+func (c Change) String() string {
+	if c.From != "" {
+		return c.Code + " " + c.From + " -> " + c.Path
+	}
+	return c.Code + " " + c.Path
+}
+
+// Status returns the porcelain status lines for the work tree, or for
+// the given paths when there are any
+func Status(dir string, paths ...string) ([]string, error) {
+	changes, err := status(dir, nil, paths)
+	if err != nil {
+		return nil, err
+	}
+	lines := make([]string, len(changes))
+	for i, c := range changes {
+		lines[i] = c.String()
+	}
+	return lines, nil
+}
+
+// Changes lists the changed files below the given paths, or in the whole
+// work tree, with the files of untracked folders listed one by one
+func Changes(dir string, paths ...string) ([]Change, error) {
+	return status(dir, []string{"--untracked-files=all"}, paths)
+}
+
+// status runs git status in its NUL-separated porcelain form, where a
+// rename or copy is followed by a second field holding its original path
+func status(dir string, args, paths []string) ([]Change, error) {
+	args = append(append([]string{"status", "--porcelain", "-z"}, args...), "--")
+	out, err := Run(dir, append(args, paths...)...)
+	if err != nil {
+		return nil, err
+	}
+	fields := splitNUL(out)
+	var changes []Change
+	for i := 0; i < len(fields); i++ {
+		f := fields[i]
+		if len(f) < 4 {
+			continue
+		}
+		c := Change{Code: f[:2], Path: f[3:]}
+		if strings.ContainsAny(c.Code, "RC") && i+1 < len(fields) {
+			i++
+			c.From = fields[i]
+		}
+		changes = append(changes, c)
+	}
+	return changes, nil
+}
+
+// IsClean reports whether the work tree, or the given paths, have no
+// uncommitted changes (untracked files that aren't ignored count as changes)
+func IsClean(dir string, paths ...string) (bool, error) {
+	lines, err := Status(dir, paths...)
+	if err != nil {
+		return false, err
+	}
+	return len(lines) == 0, nil
+}
+
+// ChangedSince returns paths that differ from base in the work tree,
+// including untracked files that aren't ignored
+func ChangedSince(dir, base string) ([]string, error) {
+	diff, err := Run(dir, "diff", "--name-only", "--relative", "-z", base)
+	if err != nil {
+		return nil, err
+	}
+	untracked, err := Run(dir, "ls-files", "--others", "--exclude-standard", "-z")
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{}
+	var files []string
+	for _, f := range append(splitNUL(diff), splitNUL(untracked)...) {
+		if !seen[f] {
+			seen[f] = true
+			files = append(files, f)
+		}
+	}
+	return files, nil
+}
+
+// CommitFiles stages exactly the given paths and commits only those,
+// leaving anything else in the index untouched. It returns the new HEAD.
+func CommitFiles(dir, message string, files []string) (string, error) {
+	if len(files) == 0 {
+		return "", fmt.Errorf("no files to commit")
+	}
+	if _, err := Run(dir, append([]string{"add", "-A", "--"}, files...)...); err != nil {
+		return "", err
+	}
+	if _, err := Run(dir, append([]string{"commit", "-q", "-m", message, "--"}, files...)...); err != nil {
+		return "", err
+	}
+	return Head(dir)
+}
+
+// splitNUL splits git's -z output, which ends each field with a NUL
+func splitNUL(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool { return r == 0 })
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
+
+// Commit is one entry from git log
+type Commit struct {
+	Hash    string
+	Short   string
+	Date    time.Time
+	Author  string
+	Subject string
+	Path    string // for FileLog, the file's path at this commit, relative to dir
+}
+
+// FileLog returns the commits that touched path, newest first,
+// following renames
+func FileLog(dir, path string) ([]Commit, error) {
+	out, err := Run(dir, "log", "--follow", "--relative", "--name-only",
+		"--format=%x1e%H%x1f%h%x1f%aI%x1f%an%x1f%s", "--", path)
+	if err != nil {
+		return nil, err
+	}
+
+	var commits []Commit
+	for _, record := range strings.Split(out, "\x1e") {
+		lines := splitLines(strings.TrimSpace(record))
+		if len(lines) == 0 {
+			continue
+		}
+		f := strings.Split(lines[0], "\x1f")
+		if len(f) != 5 {
+			continue
+		}
+		date, _ := time.Parse(time.RFC3339, f[2])
+		c := Commit{Hash: f[0], Short: f[1], Date: date, Author: f[3], Subject: f[4], Path: path}
+		if n := len(lines); n > 1 && lines[n-1] != "" {
+			c.Path = lines[n-1]
+		}
+		commits = append(commits, c)
+	}
+	return commits, nil
+}
+
+// DiffFile returns the unified diff between two revisions of a file that
+// may have been renamed between them; paths holds its names at both
+func DiffFile(dir, from, to string, paths ...string) (string, error) {
+	return Run(dir, append([]string{"diff", "-M", "--no-color", from, to, "--"}, paths...)...)
+}
+
+// ShowCommitFile returns the diff a single commit made to a file; paths
+// holds its names before and after the commit, which differ on a rename
+func ShowCommitFile(dir, rev string, paths ...string) (string, error) {
+	return Run(dir, append([]string{"show", "-M", "--no-color", "--format=", rev, "--"}, paths...)...)
+}
+
+// ShowFile returns the content of path (relative to dir) at a revision
+func ShowFile(dir, rev, path string) (string, error) {
+	cmd := exec.Command("git", "show", rev+":./"+filepath.ToSlash(path))
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git show %s:%s: %w", Short(rev), path, err)
+	}
+	return string(out), nil
+}
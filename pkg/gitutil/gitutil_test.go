@@ -0,0 +1,142 @@
+package gitutil
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newRepo makes an empty repository with a committer configured
+func newRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	git(t, dir, "init", "-q")
+	git(t, dir, "config", "user.name", "Test")
+	git(t, dir, "config", "user.email", "test@example.org")
+	return dir
+}
+
+func git(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	if _, err := Run(dir, args...); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func write(t *testing.T, dir, name, body string) {
+	t.Helper()
+	p := filepath.Join(dir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(p, []byte(body), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFileLogFollowsRenames(t *testing.T) {
+	dir := newRepo(t)
+	write(t, dir, "main-plan.md", "a\nb\nc\nd\n")
+	git(t, dir, "add", "-A")
+	git(t, dir, "commit", "-qm", "one")
+	write(t, dir, "main-plan.md", "a\nb\nc\nd\ne\n")
+	git(t, dir, "commit", "-qam", "two")
+	os.Mkdir(filepath.Join(dir, "plans"), 0755)
+	git(t, dir, "mv", "main-plan.md", "plans/main-plan.md")
+	git(t, dir, "commit", "-qm", "move")
+	write(t, dir, "plans/main-plan.md", "a\nb\nc\nd\ne\nf\n")
+	git(t, dir, "commit", "-qam", "four")
+
+	commits, err := FileLog(dir, "plans/main-plan.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, c := range commits {
+		got = append(got, c.Subject+":"+c.Path)
+	}
+	want := "four:plans/main-plan.md move:plans/main-plan.md two:main-plan.md one:main-plan.md"
+	if strings.Join(got, " ") != want {
+		t.Fatalf("FileLog = %v, want %s", got, want)
+	}
+
+	old, err := ShowFile(dir, commits[2].Hash, commits[2].Path)
+	if err != nil || old != "a\nb\nc\nd\ne\n" {
+		t.Errorf("ShowFile at two = %q, %v", old, err)
+	}
+	diff, err := DiffFile(dir, commits[3].Hash, commits[0].Hash, commits[3].Path, commits[0].Path)
+	if err != nil || !strings.Contains(diff, "+e\n+f") || strings.Contains(diff, "-a") {
+		t.Errorf("DiffFile across the rename:\n%s (%v)", diff, err)
+	}
+	show, err := ShowCommitFile(dir, commits[1].Hash, commits[1].Path, commits[2].Path)
+	if err != nil || !strings.Contains(show, "rename from main-plan.md") {
+		t.Errorf("ShowCommitFile of the rename:\n%s (%v)", show, err)
+	}
+}
+
+func TestIsCleanForPaths(t *testing.T) {
+	dir := newRepo(t)
+	write(t, dir, "plan.md", "plan\n")
+	write(t, dir, "notes.md", "notes\n")
+	git(t, dir, "add", "-A")
+	git(t, dir, "commit", "-qm", "init")
+
+	write(t, dir, "notes.md", "edited\n")
+	if clean, err := IsClean(dir, "plan.md"); err != nil || !clean {
+		t.Errorf("IsClean(plan.md) = %v, %v with only notes.md edited", clean, err)
+	}
+	if clean, _ := IsClean(dir); clean {
+		t.Error("IsClean() = true with notes.md edited")
+	}
+	write(t, dir, "plan.md", "edited\n")
+	if clean, _ := IsClean(dir, "plan.md"); clean {
+		t.Error("IsClean(plan.md) = true with plan.md edited")
+	}
+}
+
+func TestStatusKeepsOddPathsWhole(t *testing.T) {
+	dir := newRepo(t)
+	write(t, dir, "a.txt", "a\n")
+	write(t, dir, "out/sp ace.txt", "x\n")
+	git(t, dir, "add", "-A")
+	git(t, dir, "commit", "-qm", "init")
+	base, _ := Head(dir)
+
+	git(t, dir, "mv", "a.txt", "b -> c.txt")
+	write(t, dir, "out/sp ace.txt", "edited\n")
+	write(t, dir, "out/new/ü \"q\".csv", "n\n")
+
+	lines, err := Status(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{`R  a.txt -> b -> c.txt`, ` M out/sp ace.txt`, `?? out/new/`}
+	if strings.Join(lines, "|") != strings.Join(want, "|") {
+		t.Errorf("Status = %q, want %q", lines, want)
+	}
+
+	changes, err := Changes(dir, "out")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var paths []string
+	for _, c := range changes {
+		paths = append(paths, c.Path)
+	}
+	if got := strings.Join(paths, "|"); got != `out/sp ace.txt|out/new/ü "q".csv` {
+		t.Errorf("Changes(out) paths = %s", got)
+	}
+
+	changed, err := ChangedSince(dir, base)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(changed, "|"); got != `b -> c.txt|out/sp ace.txt|out/new/ü "q".csv` {
+		t.Errorf("ChangedSince = %s", got)
+	}
+}
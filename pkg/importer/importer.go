@@ -0,0 +1,408 @@
+// Package importer brings a project shared by someone else into the
+// workspace: an irl export or archive, a git bundle, a git remote or a
+// project folder.
+package importer
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/drpedapati/irl-template/pkg/archive"
+	"github.com/drpedapati/irl-template/pkg/export"
+	"github.com/drpedapati/irl-template/pkg/fsutil"
+	"github.com/drpedapati/irl-template/pkg/gitutil"
+	"github.com/drpedapati/irl-template/pkg/projects"
+)
+
+// Source kinds
+const (
+	KindBundle    = "bundle"     // zip or tar from irl export or irl archive
+	KindGitBundle = "git-bundle" // file from git bundle create
+	KindGit       = "git"        // remote URL or local repository
+	KindFolder    = "folder"     // project folder, copied as is
+)
+
+// Staged is a project unpacked next to its destination and checked to be
+// an IRL project. Commit moves it into place; Discard removes it.
+type Staged struct {
+	Dir      string   // the unpacked project
+	Kind     string   // one of the source kinds
+	Name     string   // the project's name at its source
+	Files    int      // files verified against a bundle's manifest
+	History  bool     // git history was rebuilt from a bundle's history/
+	Warnings []string // problems that didn't stop the import
+	tmp      string
+}
+
+// Detect works out what kind of source src is
+func Detect(src string) (string, error) {
+	if IsRemote(src) {
+		return KindGit, nil
+	}
+	info, err := os.Stat(src)
+	if err != nil {
+		return "", err
+	}
+	if info.IsDir() {
+		if out, err := gitutil.Run(src, "rev-parse", "--is-bare-repository"); err == nil && out == "true" {
+			return KindGit, nil
+		}
+		return KindFolder, nil
+	}
+
+	f, err := os.Open(src)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	head := make([]byte, 16)
+	n, _ := io.ReadFull(f, head)
+	head = head[:n]
+	switch {
+	case bytes.HasPrefix(head, []byte("PK\x03\x04")), bytes.HasPrefix(head, []byte("\x1f\x8b")):
+		return KindBundle, nil
+	case bytes.HasPrefix(head, []byte("# v2 git bundle")), bytes.HasPrefix(head, []byte("# v3 git bundle")):
+		return KindGitBundle, nil
+	}
+	return "", fmt.Errorf("%s isn't a zip, tar.gz or git bundle", filepath.Base(src))
+}
+
+// scpLike matches git's user@host:path remote form
+var scpLike = regexp.MustCompile(`^[\w.-]+@[\w.-]+:`)
+
+// IsRemote reports whether src names a git remote rather than a local path
+func IsRemote(src string) bool {
+	return strings.Contains(src, "://") || scpLike.MatchString(src)
+}
+
+// Stage fetches src into a hidden folder in parent and checks it's an IRL
+// project. Nothing is left behind if it fails.
+func Stage(src, parent string) (*Staged, error) {
+	kind, err := Detect(src)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(parent, 0755); err != nil {
+		return nil, err
+	}
+	tmp, err := os.MkdirTemp(parent, ".import-*")
+	if err != nil {
+		return nil, err
+	}
+	s := &Staged{Dir: filepath.Join(tmp, "project"), Kind: kind, Name: sourceName(src), Warnings: []string{}, tmp: tmp}
+
+	switch kind {
+	case KindBundle:
+		err = s.unpack(src)
+	case KindGitBundle:
+		if _, err = gitutil.Run(tmp, "clone", "-q", src, s.Dir); err == nil {
+			// The bundle file isn't somewhere to pull from later
+			_, err = gitutil.Run(s.Dir, "remote", "remove", "origin")
+		}
+	case KindGit:
+		_, err = gitutil.Run(tmp, "clone", "-q", src, s.Dir)
+	case KindFolder:
+		_, err = fsutil.Copy(src, s.Dir, fsutil.Options{Exclude: fsutil.DefaultExcludes})
+	}
+	if err != nil {
+		s.Discard()
+		return nil, err
+	}
+
+	if _, ok := projects.Detect(s.Dir); !ok {
+		s.Discard()
+		return nil, fmt.Errorf("%s isn't an IRL project (no plans/main-plan.md, main-plan.md or 01-plans/main-plan.md)", filepath.Base(strings.TrimRight(src, "/")))
+	}
+	return s, nil
+}
+
+// Commit moves the staged project to dest, which must not exist
+func (s *Staged) Commit(dest string) error {
+	defer s.Discard()
+	if _, err := os.Lstat(dest); err == nil {
+		return fmt.Errorf("%s already exists", dest)
+	}
+	if err := os.Chmod(s.Dir, 0755); err != nil {
+		return err
+	}
+	return os.Rename(s.Dir, dest)
+}
+
+// Discard removes whatever is left of the staging folder
+func (s *Staged) Discard() {
+	os.RemoveAll(s.tmp)
+}
+
+// ValidName checks that a project name is a single folder name, so
+// joining it to the workspace stays inside it
+func ValidName(name string) error {
+	if name == "." || !filepath.IsLocal(name) || strings.ContainsAny(name, `/\`) {
+		return fmt.Errorf("%q isn't a folder name", name)
+	}
+	return nil
+}
+
+// sourceName guesses the project name from the source's path or URL
+func sourceName(src string) string {
+	name := path.Base(strings.TrimRight(filepath.ToSlash(src), "/"))
+	if i := strings.LastIndex(name, ":"); i >= 0 {
+		name = name[i+1:]
+	}
+	for _, ext := range []string{".git", ".bundle", ".zip", ".crate", ".tar.gz", ".tgz"} {
+		name = strings.TrimSuffix(name, ext)
+	}
+	if i := strings.Index(name, "_export_"); i > 0 {
+		name = name[:i]
+	}
+	return name
+}
+
+// unpack extracts an irl export or archive, checking every file against
+// its manifest, then rebuilds the git history an export carries
+func (s *Staged) unpack(src string) error {
+	var (
+		m   *archive.Manifest
+		err error
+	)
+	if isZip(src) {
+		m, err = archive.Restore(src, s.Dir)
+	} else {
+		m, err = untar(src, s.Dir)
+	}
+	if err != nil {
+		return err
+	}
+	if m.Project != "" {
+		// The name comes from the bundle, so it mustn't lead out of the workspace
+		if err := ValidName(m.Project); err != nil {
+			return fmt.Errorf("the manifest's project name: %w", err)
+		}
+		s.Name = m.Project
+	}
+	s.Files = len(m.Files)
+
+	if _, err := os.Stat(filepath.Join(s.Dir, ".git")); err == nil {
+		return nil // irl archive keeps .git as it was
+	}
+	if err := s.restoreHistory(); err != nil {
+		s.Warnings = append(s.Warnings, "couldn't rebuild the git history: "+err.Error())
+	}
+	return nil
+}
+
+// restoreHistory turns an export's history/ folder back into the
+// project's .git, leaving the unpacked files as the work tree. The
+// files the export generated are removed once the history is back.
+func (s *Staged) restoreHistory() error {
+	histDir := filepath.Join(s.Dir, export.HistoryDir)
+	bundles, _ := filepath.Glob(filepath.Join(histDir, "*.bundle"))
+	patches, _ := filepath.Glob(filepath.Join(histDir, "patches", "*.patch"))
+	if len(bundles) == 0 && len(patches) == 0 {
+		return nil
+	}
+
+	repo := filepath.Join(s.tmp, "history")
+	if len(bundles) > 0 {
+		if _, err := gitutil.Run(s.tmp, "clone", "-q", "--no-checkout", bundles[0], repo); err != nil {
+			return err
+		}
+		if _, err := gitutil.Run(repo, "remote", "remove", "origin"); err != nil {
+			return err
+		}
+	} else {
+		if _, err := gitutil.Run(s.tmp, "init", "-q", repo); err != nil {
+			return err
+		}
+		args := append([]string{"am", "-q", "--committer-date-is-author-date"}, patches...)
+		if _, err := gitutil.Run(repo, args...); err != nil {
+			return err
+		}
+	}
+	if err := os.Rename(filepath.Join(repo, ".git"), filepath.Join(s.Dir, ".git")); err != nil {
+		return err
+	}
+	// Match the index to HEAD without touching the unpacked files
+	if _, err := gitutil.Run(s.Dir, "reset", "-q"); err != nil {
+		return err
+	}
+
+	s.History = true
+	os.RemoveAll(histDir)
+	os.Remove(filepath.Join(s.Dir, export.ReadmeName))
+	os.Remove(filepath.Join(s.Dir, export.CrateName))
+	return nil
+}
+
+func isZip(file string) bool {
+	f, err := os.Open(file)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	head := make([]byte, 4)
+	_, err = io.ReadFull(f, head)
+	return err == nil && string(head) == "PK\x03\x04"
+}
+
+// untar extracts a gzipped tar from irl export into dest, which must not
+// exist. The whole tar is checked against its manifest before anything is
+// written, and entries are only written below dest.
+func untar(src, dest string) (*archive.Manifest, error) {
+	m, err := verifyTar(src)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Mkdir(dest, 0755); err != nil {
+		return nil, err
+	}
+	err = walkTar(src, func(hdr *tar.Header, name string, r io.Reader) error {
+		if name == archive.ManifestName {
+			return nil
+		}
+		target, err := archive.SafePath(dest, name)
+		if err != nil {
+			return fmt.Errorf("%s: %w", hdr.Name, err)
+		}
+		if hdr.Typeflag == tar.TypeDir {
+			return os.MkdirAll(target, 0755)
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		switch hdr.Typeflag {
+		case tar.TypeSymlink:
+			if err := archive.CheckLink(dest, name, hdr.Linkname); err != nil {
+				return fmt.Errorf("%s: %w", hdr.Name, err)
+			}
+			return os.Symlink(hdr.Linkname, target)
+		case tar.TypeReg:
+			if _, err := writeFile(target, r, fs.FileMode(hdr.Mode).Perm()|0600); err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+			os.Chtimes(target, hdr.ModTime, hdr.ModTime)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// verifyTar reads a tar's manifest and checks every entry against it
+// without extracting anything
+func verifyTar(src string) (*archive.Manifest, error) {
+	var m *archive.Manifest
+	sums := map[string]string{}
+	err := walkTar(src, func(hdr *tar.Header, name string, r io.Reader) error {
+		switch {
+		case name == archive.ManifestName:
+			m = &archive.Manifest{}
+			if err := json.NewDecoder(r).Decode(m); err != nil {
+				return fmt.Errorf("invalid %s: %w", archive.ManifestName, err)
+			}
+		case hdr.Typeflag == tar.TypeSymlink:
+			sums[name] = hashString(hdr.Linkname)
+		case hdr.Typeflag == tar.TypeReg:
+			h := sha256.New()
+			if _, err := io.Copy(h, r); err != nil {
+				return err
+			}
+			sums[name] = hex.EncodeToString(h.Sum(nil))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if m == nil {
+		return nil, fmt.Errorf("not an irl bundle (no %s)", archive.ManifestName)
+	}
+
+	var problems []string
+	for _, want := range m.Files {
+		got, ok := sums[want.Path]
+		switch {
+		case !ok:
+			problems = append(problems, want.Path+": missing")
+		case got != want.SHA256:
+			problems = append(problems, want.Path+": checksum mismatch")
+		}
+		delete(sums, want.Path)
+	}
+	for name := range sums {
+		problems = append(problems, name+": not in manifest")
+	}
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		if len(problems) > 5 {
+			problems = append(problems[:5], fmt.Sprintf("and %d more", len(problems)-5))
+		}
+		return nil, fmt.Errorf("bundle failed verification: %s", strings.Join(problems, "; "))
+	}
+	return m, nil
+}
+
+// walkTar calls fn for each entry of a gzipped tar with its cleaned name,
+// refusing names that leave the tree
+func walkTar(src string, fn func(hdr *tar.Header, name string, r io.Reader) error) error {
+	f, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		name := path.Clean(hdr.Name)
+		if path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+			return fmt.Errorf("%s: unsafe path", hdr.Name)
+		}
+		if err := fn(hdr, name, tr); err != nil {
+			return err
+		}
+	}
+}
+
+func writeFile(target string, r io.Reader, perm fs.FileMode) (string, error) {
+	out, err := os.OpenFile(target, os.O_CREATE|os.O_EXCL|os.O_WRONLY, perm)
+	if err != nil {
+		return "", err
+	}
+	h := sha256.New()
+	_, err = io.Copy(io.MultiWriter(out, h), r)
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	return hex.EncodeToString(h.Sum(nil)), err
+}
+
+func hashString(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
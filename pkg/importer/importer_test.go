@@ -0,0 +1,323 @@
+package importer
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/drpedapati/irl-template/pkg/archive"
+	"github.com/drpedapati/irl-template/pkg/export"
+	"github.com/drpedapati/irl-template/pkg/gitutil"
+)
+
+// entry is one file of a hand-built bundle
+type entry struct {
+	name, body string
+	link       bool
+}
+
+// manifest describes entries the way a crafted bundle would, so the
+// checksums match and only the paths are hostile
+func manifest(project string, entries []entry) []byte {
+	m := archive.Manifest{Version: 1, Project: project, Created: time.Now(), Files: []archive.File{}}
+	for _, e := range entries {
+		m.Files = append(m.Files, archive.File{Path: e.name, Size: int64(len(e.body)), SHA256: hashString(e.body), Link: e.link})
+	}
+	data, _ := json.Marshal(m)
+	return data
+}
+
+func writeTar(t *testing.T, file, project string, entries []entry) {
+	t.Helper()
+	out, err := os.Create(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer out.Close()
+	gz := gzip.NewWriter(out)
+	tw := tar.NewWriter(gz)
+	for _, e := range entries {
+		hdr := &tar.Header{Name: e.name, Mode: 0644, Size: int64(len(e.body)), Typeflag: tar.TypeReg}
+		if e.link {
+			hdr = &tar.Header{Name: e.name, Mode: 0777, Linkname: e.body, Typeflag: tar.TypeSymlink}
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		if !e.link {
+			tw.Write([]byte(e.body))
+		}
+	}
+	data := manifest(project, entries)
+	tw.WriteHeader(&tar.Header{Name: archive.ManifestName, Mode: 0644, Size: int64(len(data)), Typeflag: tar.TypeReg})
+	tw.Write(data)
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func writeZip(t *testing.T, file, project string, entries []entry) {
+	t.Helper()
+	out, err := os.Create(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer out.Close()
+	zw := zip.NewWriter(out)
+	for _, e := range entries {
+		hdr := &zip.FileHeader{Name: e.name}
+		if e.link {
+			hdr.SetMode(os.ModeSymlink | 0777)
+		} else {
+			hdr.SetMode(0644)
+		}
+		w, err := zw.CreateHeader(hdr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(e.body))
+	}
+	w, _ := zw.Create(archive.ManifestName)
+	w.Write(manifest(project, entries))
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStageRefusesBundlesThatEscape(t *testing.T) {
+	plan := entry{name: "plans/main-plan.md", body: "# plan"}
+	hostile := []struct {
+		name, project string
+		entries       func(outside string) []entry
+	}{
+		{"absolute link", "evil", func(outside string) []entry {
+			return []entry{plan, {name: "d", body: outside, link: true}, {name: "d/pwned.txt", body: "x"}}
+		}},
+		{"relative link", "evil", func(outside string) []entry {
+			return []entry{plan, {name: "d", body: "../../../outside", link: true}, {name: "d/pwned.txt", body: "x"}}
+		}},
+		{"write through a link", "evil", func(outside string) []entry {
+			return []entry{plan, {name: "d", body: "plans", link: true}, {name: "d/pwned.txt", body: "x"}}
+		}},
+		{"link through an earlier link", "evil", func(outside string) []entry {
+			up := strings.Repeat("b/", 8) + strings.Repeat("../", 8)
+			return []entry{plan, {name: "b", body: ".", link: true}, {name: "d", body: up + "outside", link: true}}
+		}},
+		{"project name", "../outside", func(outside string) []entry {
+			return []entry{plan}
+		}},
+	}
+	formats := map[string]func(*testing.T, string, string, []entry){"tar": writeTar, "zip": writeZip}
+
+	for format, write := range formats {
+		for _, tt := range hostile {
+			t.Run(format+"/"+tt.name, func(t *testing.T) {
+				dir := t.TempDir()
+				outside := filepath.Join(dir, "outside")
+				ws := filepath.Join(dir, "ws")
+				for _, d := range []string{outside, ws} {
+					if err := os.Mkdir(d, 0755); err != nil {
+						t.Fatal(err)
+					}
+				}
+				src := filepath.Join(dir, "evil."+format)
+				write(t, src, tt.project, tt.entries(outside))
+
+				if s, err := Stage(src, ws); err == nil {
+					s.Discard()
+					t.Fatal("Stage succeeded, want an unsafe path error")
+				}
+				if got, _ := os.ReadDir(outside); len(got) > 0 {
+					t.Errorf("wrote %s outside the workspace", got[0].Name())
+				}
+				if got, _ := os.ReadDir(ws); len(got) > 0 {
+					t.Errorf("left %s in the workspace", got[0].Name())
+				}
+			})
+		}
+	}
+}
+
+func TestStageChecksTarBeforeExtracting(t *testing.T) {
+	dir := t.TempDir()
+	ws := filepath.Join(dir, "ws")
+	src := filepath.Join(dir, "tampered.tar.gz")
+	entries := []entry{{name: "plans/main-plan.md", body: "# plan"}}
+	writeTar(t, src, "evil", entries)
+
+	// Rewrite the bundle with different content under the same manifest
+	out, _ := os.Create(src)
+	gz := gzip.NewWriter(out)
+	tw := tar.NewWriter(gz)
+	tw.WriteHeader(&tar.Header{Name: "plans/main-plan.md", Mode: 0644, Size: 7, Typeflag: tar.TypeReg})
+	tw.Write([]byte("# other"))
+	data := manifest("evil", entries)
+	tw.WriteHeader(&tar.Header{Name: archive.ManifestName, Mode: 0644, Size: int64(len(data)), Typeflag: tar.TypeReg})
+	tw.Write(data)
+	tw.Close()
+	gz.Close()
+	out.Close()
+
+	if _, err := untar(src, filepath.Join(ws, "project")); err == nil {
+		t.Fatal("untar succeeded, want a checksum mismatch")
+	}
+	if _, err := os.Stat(ws); !os.IsNotExist(err) {
+		t.Error("untar wrote files before checking the manifest")
+	}
+}
+
+func TestStageExtractsTar(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "study_export_20260202.tar.gz")
+	writeTar(t, src, "evil", []entry{
+		{name: "plans/main-plan.md", body: "# plan"},
+		{name: "plan.md", body: "plans/main-plan.md", link: true},
+	})
+
+	s, err := Stage(src, filepath.Join(dir, "ws"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Discard()
+	if s.Kind != KindBundle || s.Name != "evil" || s.Files != 2 {
+		t.Errorf("staged %+v", s)
+	}
+	if data, err := os.ReadFile(filepath.Join(s.Dir, "plan.md")); err != nil || string(data) != "# plan" {
+		t.Errorf("plan.md = %q, %v", data, err)
+	}
+}
+
+// gitProject makes a committed project with a second commit, as a
+// project with some history
+func gitProject(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := filepath.Join(t.TempDir(), "260101-eeg-study")
+	os.MkdirAll(filepath.Join(dir, "plans"), 0755)
+	os.WriteFile(filepath.Join(dir, "plans", "main-plan.md"), []byte("# Plan\n"), 0644)
+	run := func(args ...string) {
+		t.Helper()
+		if _, err := gitutil.Run(dir, args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	run("init", "-q")
+	run("config", "user.name", "Test")
+	run("config", "user.email", "test@example.org")
+	run("add", "-A")
+	run("commit", "-qm", "init")
+	os.WriteFile(filepath.Join(dir, "plans", "main-plan.md"), []byte("# Plan\n\nLoop 1\n"), 0644)
+	run("commit", "-qam", "loop 1")
+	return dir
+}
+
+func TestExportRoundTripRebuildsHistory(t *testing.T) {
+	dir := gitProject(t)
+	head, _ := gitutil.Head(dir)
+	// git am commits the patches, so it needs someone to commit as
+	t.Setenv("GIT_COMMITTER_NAME", "Test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.org")
+
+	for _, format := range []string{export.FormatZip, export.FormatTar} {
+		for _, history := range []string{export.HistoryBundle, export.HistoryPatches} {
+			t.Run(format+"/"+history, func(t *testing.T) {
+				out := t.TempDir()
+				bundle := filepath.Join(out, export.FileName("260101-eeg-study", format, time.Now()))
+				if _, err := export.Export(dir, bundle, export.Options{Format: format, History: history}); err != nil {
+					t.Fatal(err)
+				}
+
+				s, err := Stage(bundle, filepath.Join(out, "ws"))
+				if err != nil {
+					t.Fatal(err)
+				}
+				defer s.Discard()
+				if !s.History || s.Name != "260101-eeg-study" {
+					t.Errorf("staged %+v, want the history rebuilt", s)
+				}
+				log, err := gitutil.Run(s.Dir, "log", "--format=%s")
+				if err != nil || log != "loop 1\ninit" {
+					t.Errorf("rebuilt log = %q, %v", log, err)
+				}
+				if history == export.HistoryBundle {
+					if got, _ := gitutil.Head(s.Dir); got != head {
+						t.Errorf("HEAD = %s, want %s", got, head)
+					}
+				}
+				if clean, err := gitutil.IsClean(s.Dir); err != nil || !clean {
+					t.Errorf("the rebuilt work tree isn't clean: %v", err)
+				}
+				for _, generated := range []string{export.ReadmeName, export.HistoryDir, archive.ManifestName} {
+					if _, err := os.Stat(filepath.Join(s.Dir, generated)); !os.IsNotExist(err) {
+						t.Errorf("%s was left in the project", generated)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestStageGitSources(t *testing.T) {
+	dir := gitProject(t)
+	out := t.TempDir()
+	bundle := filepath.Join(out, "260101-eeg-study.bundle")
+	if _, err := gitutil.Run(dir, "bundle", "create", bundle, "--all"); err != nil {
+		t.Fatal(err)
+	}
+	bare := filepath.Join(out, "260101-eeg-study.git")
+	if _, err := gitutil.Run(out, "clone", "-q", "--bare", dir, bare); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		src, kind, remote string
+	}{
+		{bundle, KindGitBundle, ""}, // the bundle file isn't kept as a remote
+		{bare, KindGit, bare},
+	} {
+		t.Run(tc.kind, func(t *testing.T) {
+			s, err := Stage(tc.src, filepath.Join(out, "ws"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer s.Discard()
+			if s.Kind != tc.kind || s.Name != "260101-eeg-study" {
+				t.Errorf("staged as %s %q", s.Kind, s.Name)
+			}
+			if log, err := gitutil.Run(s.Dir, "log", "--format=%s"); err != nil || log != "loop 1\ninit" {
+				t.Errorf("cloned log = %q, %v", log, err)
+			}
+			remote, _ := gitutil.Run(s.Dir, "remote", "get-url", "origin")
+			if remote != tc.remote {
+				t.Errorf("origin = %q, want %q", remote, tc.remote)
+			}
+		})
+	}
+}
+
+func TestStageRefusesNonProjects(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "notes")
+	os.MkdirAll(src, 0755)
+	os.WriteFile(filepath.Join(src, "README.md"), []byte("# Notes\n"), 0644)
+
+	parent := t.TempDir()
+	_, err := Stage(src, parent)
+	if err == nil || !strings.Contains(err.Error(), "01-plans/main-plan.md") {
+		t.Errorf("err = %v, want it to name every plan location", err)
+	}
+	if left, _ := os.ReadDir(parent); len(left) != 0 {
+		t.Errorf("left %d entries behind", len(left))
+	}
+}
@@ -0,0 +1,245 @@
+// Package lint checks IRL plans for structural problems that break the loop.
+package lint
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/drpedapati/irl-template/pkg/plan"
+)
+
+// Severity levels
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// Issue is a single lint finding
+type Issue struct {
+	Severity string `json:"severity"`
+	Rule     string `json:"rule"`
+	Message  string `json:"message"`
+	Line     int    `json:"line,omitempty"`
+}
+
+// Result holds all findings for one project
+type Result struct {
+	Project  string  `json:"project"`
+	Plan     string  `json:"plan,omitempty"`
+	Template string  `json:"template,omitempty"`
+	Issues   []Issue `json:"issues"`
+}
+
+// Errors returns the number of error-level issues
+func (r *Result) Errors() int {
+	return r.count(SeverityError)
+}
+
+// Warnings returns the number of warning-level issues
+func (r *Result) Warnings() int {
+	return r.count(SeverityWarning)
+}
+
+func (r *Result) count(severity string) int {
+	n := 0
+	for _, i := range r.Issues {
+		if i.Severity == severity {
+			n++
+		}
+	}
+	return n
+}
+
+// OK reports whether the project passes. In strict mode warnings also fail.
+func (r *Result) OK(strict bool) bool {
+	if strict {
+		return len(r.Issues) == 0
+	}
+	return r.Errors() == 0
+}
+
+func (r *Result) add(severity, rule string, line int, msg string) {
+	r.Issues = append(r.Issues, Issue{Severity: severity, Rule: rule, Message: msg, Line: line})
+}
+
+// Options configures a lint run
+type Options struct {
+	TemplateName    string // for reporting only
+	TemplateContent string // source template; structural checks are skipped when empty
+}
+
+// Check lints the plan in projectDir
+func Check(projectDir string, opts Options) *Result {
+	r := &Result{Project: projectDir, Template: opts.TemplateName, Issues: []Issue{}}
+
+	planPath, err := plan.Find(projectDir)
+	if err != nil {
+		r.add(SeverityError, "plan-missing", 0, "no plans/main-plan.md found")
+		return r
+	}
+	r.Plan = planPath
+
+	p, err := plan.ParseFile(planPath)
+	if err != nil {
+		r.add(SeverityError, "plan-unreadable", 0, err.Error())
+		return r
+	}
+
+	checkFrontMatter(r, p)
+	checkDuplicateHeadings(r, p)
+	if opts.TemplateContent != "" {
+		checkTemplate(r, p, plan.Parse(opts.TemplateContent))
+	}
+	checkDataPaths(r, p, projectDir)
+	checkLogFiles(r, p, projectDir)
+
+	return r
+}
+
+// checkFrontMatter flags unterminated blocks, non key/value lines and repeated keys
+func checkFrontMatter(r *Result, p *plan.Plan) {
+	if p.FrontMatter == nil {
+		if len(p.Preamble) > 0 && p.Preamble[0].Kind == plan.NodeRule && p.Preamble[0].Line == 1 {
+			r.add(SeverityError, "front-matter", 1, "front matter is opened with --- but never closed")
+		}
+		return
+	}
+
+	for _, line := range p.FrontMatter.Malformed() {
+		r.add(SeverityError, "front-matter", 0, "malformed front matter line: "+strings.TrimSpace(line))
+	}
+
+	seen := map[string]bool{}
+	for _, f := range p.FrontMatter.Fields {
+		if f.Key == "" {
+			continue
+		}
+		if seen[f.Key] {
+			r.add(SeverityError, "front-matter", 0, "duplicate front matter key: "+f.Key)
+		}
+		seen[f.Key] = true
+	}
+}
+
+// checkDuplicateHeadings flags headings whose titles appear more than once
+func checkDuplicateHeadings(r *Result, p *plan.Plan) {
+	seen := map[string]int{}
+	for _, s := range p.AllSections() {
+		key := strings.ToLower(s.Title())
+		if first, ok := seen[key]; ok {
+			r.add(SeverityError, "duplicate-heading", s.Heading.Line,
+				fmt.Sprintf("duplicate heading %q (first at line %d)", s.Title(), first))
+			continue
+		}
+		seen[key] = s.Heading.Line
+	}
+}
+
+// checkTemplate compares the plan with its source template. Every template
+// section must be present, and sections without an AUTHOR AREA must not be
+// edited (skill lines may be toggled).
+func checkTemplate(r *Result, p, tmpl *plan.Plan) {
+	for _, ts := range tmpl.AllSections() {
+		// The document title is free to change
+		if ts.Level() == 1 {
+			continue
+		}
+
+		s := p.SectionByTitle(ts.Title())
+		if s == nil {
+			r.add(SeverityError, "missing-section", 0, "required section missing: "+ts.Title())
+			continue
+		}
+
+		if fixedContent(ts) != fixedContent(s) {
+			r.add(SeverityError, "fixed-section-modified", s.Heading.Line,
+				fmt.Sprintf("%q differs from the template outside an AUTHOR AREA", s.Title()))
+		}
+	}
+}
+
+// fixedContent returns the template-owned part of a section body: everything
+// before the AUTHOR AREA marker, ignoring blank lines and skill toggles
+func fixedContent(s *plan.Section) string {
+	var parts []string
+	for _, n := range s.Body {
+		if n.Kind == plan.NodeAuthorArea {
+			break
+		}
+		if n.Kind == plan.NodeBlank || n.Kind == plan.NodeSkill {
+			continue
+		}
+		parts = append(parts, strings.TrimSpace(n.Raw))
+	}
+	return strings.Join(parts, "\n")
+}
+
+var rawDataPath = regexp.MustCompile("02-data/raw\\b(?:/[^\\s)\\]\"'`<>*]*)?")
+
+// checkDataPaths flags references to files under 02-data/raw that don't exist
+func checkDataPaths(r *Result, p *plan.Plan, projectDir string) {
+	for _, n := range allNodes(p) {
+		for _, ref := range rawDataPath.FindAllString(n.Raw, -1) {
+			ref = strings.TrimRight(ref, ".,;:/")
+			if _, err := os.Stat(filepath.Join(projectDir, filepath.FromSlash(ref))); err != nil {
+				r.add(SeverityError, "dead-data-path", n.Line, "referenced path does not exist: "+ref)
+			}
+		}
+	}
+}
+
+var logFileRef = regexp.MustCompile(`plans/main-plan-[\w-]+\.(?:md|csv)`)
+
+// checkLogFiles flags log files the After Each Loop section asks agents to
+// maintain but that don't exist. Before the first loop has written any of
+// them there is nothing to flag. Commented-out options are ignored.
+func checkLogFiles(r *Result, p *plan.Plan, projectDir string) {
+	s := p.Section(plan.SectionAfterEachLoop)
+	if s == nil {
+		return
+	}
+
+	type ref struct {
+		path string
+		line int
+	}
+	var missing []ref
+	seen := map[string]bool{}
+	logged := false
+	for _, n := range s.Body {
+		if n.Kind == plan.NodeComment {
+			continue
+		}
+		for _, path := range logFileRef.FindAllString(n.Raw, -1) {
+			if seen[path] {
+				continue
+			}
+			seen[path] = true
+			if _, err := os.Stat(filepath.Join(projectDir, filepath.FromSlash(path))); err != nil {
+				missing = append(missing, ref{path, n.Line})
+			} else {
+				logged = true
+			}
+		}
+	}
+	if !logged {
+		return
+	}
+	for _, m := range missing {
+		r.add(SeverityWarning, "missing-log", m.line, "log file not found: "+m.path)
+	}
+}
+
+// allNodes returns every node of the plan in document order
+func allNodes(p *plan.Plan) []*plan.Node {
+	nodes := append([]*plan.Node{}, p.Preamble...)
+	p.Walk(func(s *plan.Section) bool {
+		nodes = append(nodes, s.Heading)
+		nodes = append(nodes, s.Body...)
+		return true
+	})
+	return nodes
+}
@@ -0,0 +1,285 @@
+// Package loop tracks IRL loop iterations and writes the audit trail that
+// the "After Each Loop" checklist asks for.
+package loop
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/drpedapati/irl-template/pkg/gitutil"
+	"github.com/drpedapati/irl-template/pkg/plan"
+)
+
+// State is an in-progress loop, stored in <git-dir>/irl/loop.json so it
+// never shows up as a change in the work tree
+type State struct {
+	Baseline string    `json:"baseline"`
+	Started  time.Time `json:"started"`
+}
+
+// Result describes a finished loop
+type Result struct {
+	Baseline   string   // commit recorded by Start
+	WorkCommit string   // commit holding the loop's changes (HEAD if nothing changed)
+	LogCommit  string   // commit holding the log updates
+	Files      []string // files committed with the work
+	LogFiles   []string // log files that were updated
+}
+
+// LogPaths returns the CSV log and activity log paths for a plan file,
+// e.g. plans/main-plan-log.csv and plans/main-plan-activity.md
+func LogPaths(planPath string) (csvPath, activityPath string) {
+	base := strings.TrimSuffix(planPath, filepath.Ext(planPath))
+	return base + "-log.csv", base + "-activity.md"
+}
+
+func statePath(projectDir string) (string, error) {
+	gitDir, err := gitutil.GitDir(projectDir)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(gitDir, "irl", "loop.json"), nil
+}
+
+// Current returns the in-progress loop, or nil if none is running
+func Current(projectDir string) (*State, error) {
+	path, err := statePath(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var s State
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("invalid loop state %s: %w", path, err)
+	}
+	return &s, nil
+}
+
+// Start verifies the work tree is clean and records HEAD as the baseline
+func Start(projectDir string) (*State, error) {
+	if !gitutil.IsRepo(projectDir) {
+		return nil, fmt.Errorf("%s is not a git repository", projectDir)
+	}
+
+	if cur, err := Current(projectDir); err != nil {
+		return nil, err
+	} else if cur != nil {
+		return nil, fmt.Errorf("a loop is already in progress (started %s at %s)",
+			cur.Started.Format("2006-01-02 15:04"), gitutil.Short(cur.Baseline))
+	}
+
+	status, err := gitutil.Status(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	if len(status) > 0 {
+		return nil, &DirtyError{Files: status}
+	}
+
+	head, err := gitutil.Head(projectDir)
+	if err != nil {
+		return nil, fmt.Errorf("no baseline commit: %w", err)
+	}
+
+	s := &State{Baseline: head, Started: time.Now()}
+	if err := s.save(projectDir); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// DirtyError is returned by Start when the work tree has uncommitted changes
+type DirtyError struct {
+	Files []string // porcelain status lines
+}
+
+func (e *DirtyError) Error() string {
+	return fmt.Sprintf("work tree is not clean (%d uncommitted changes)", len(e.Files))
+}
+
+func (s *State) save(projectDir string) error {
+	path, err := statePath(projectDir)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// Abort discards the in-progress loop without touching the work tree
+func Abort(projectDir string) error {
+	path, err := statePath(projectDir)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("no loop in progress")
+		}
+		return err
+	}
+	return nil
+}
+
+// FinishOptions configures Finish
+type FinishOptions struct {
+	Summary string   // one-line change summary (required)
+	Files   []string // files to commit; defaults to everything changed since the baseline
+}
+
+// Finish commits the loop's changes, appends to the plan's CSV and activity
+// logs with the resulting hash, and commits the logs
+func Finish(projectDir string, opts FinishOptions) (*Result, error) {
+	summary := strings.TrimSpace(opts.Summary)
+	if summary == "" {
+		return nil, fmt.Errorf("a change summary is required")
+	}
+
+	state, err := Current(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	if state == nil {
+		return nil, fmt.Errorf("no loop in progress (run 'irl loop start' first)")
+	}
+
+	planPath, err := plan.Find(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	csvPath, activityPath := LogPaths(planPath)
+	csvRel, _ := filepath.Rel(projectDir, csvPath)
+	activityRel, _ := filepath.Rel(projectDir, activityPath)
+
+	files := opts.Files
+	if len(files) == 0 {
+		files, err = gitutil.ChangedSince(projectDir, state.Baseline)
+		if err != nil {
+			return nil, err
+		}
+	}
+	// Logs are committed separately so they can reference the work commit
+	files = without(files, filepath.ToSlash(csvRel), filepath.ToSlash(activityRel))
+
+	res := &Result{Baseline: state.Baseline, Files: files}
+
+	if len(files) > 0 {
+		res.WorkCommit, err = gitutil.CommitFiles(projectDir, summary, files)
+		if err != nil {
+			return nil, fmt.Errorf("failed to commit changes: %w", err)
+		}
+	} else {
+		res.WorkCommit, err = gitutil.Head(projectDir)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	hash := gitutil.Short(res.WorkCommit)
+	if err := appendCSV(csvPath, now, summary, hash); err != nil {
+		return nil, fmt.Errorf("failed to update %s: %w", csvRel, err)
+	}
+	if err := appendActivity(activityPath, now, summary, hash); err != nil {
+		return nil, fmt.Errorf("failed to update %s: %w", activityRel, err)
+	}
+	res.LogFiles = []string{csvRel, activityRel}
+
+	res.LogCommit, err = gitutil.CommitFiles(projectDir, "Log loop: "+summary, res.LogFiles)
+	if err != nil {
+		return nil, fmt.Errorf("failed to commit logs: %w", err)
+	}
+
+	if err := Abort(projectDir); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// appendCSV adds a row to the plan log, creating it with a header if needed
+func appendCSV(path string, t time.Time, summary, hash string) error {
+	var b strings.Builder
+	w := csv.NewWriter(&b)
+	if !exists(path) {
+		w.Write([]string{"date", "change_summary", "git_hash"})
+	}
+	w.Write([]string{t.Format("2006-01-02"), summary, hash})
+	w.Flush()
+	return appendText(path, b.String())
+}
+
+// appendActivity adds a bullet to the activity log, creating it if needed
+func appendActivity(path string, t time.Time, summary, hash string) error {
+	text := fmt.Sprintf("- %s (%s): %s\n", t.Format("2006-01-02 15:04"), hash, summary)
+	if !exists(path) {
+		title := strings.TrimSuffix(filepath.Base(path), "-activity.md")
+		text = "# " + titleCase(title) + " Activity\n\n" + text
+	}
+	return appendText(path, text)
+}
+
+// appendText appends text, first terminating an unterminated last line
+func appendText(path, text string) error {
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if len(data) > 0 && data[len(data)-1] != '\n' {
+		text = "\n" + text
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.WriteString(text)
+	return err
+}
+
+// titleCase turns "main-plan" into "Main Plan"
+func titleCase(s string) string {
+	words := strings.Fields(strings.ReplaceAll(s, "-", " "))
+	for i, w := range words {
+		words[i] = strings.ToUpper(w[:1]) + w[1:]
+	}
+	return strings.Join(words, " ")
+}
+
+func without(files []string, drop ...string) []string {
+	var out []string
+	for _, f := range files {
+		keep := true
+		for _, d := range drop {
+			if f == d {
+				keep = false
+			}
+		}
+		if keep {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,129 @@
+package plan
+
+import (
+	"strings"
+)
+
+// FrontMatter is the YAML block at the top of a plan, as written by
+// scaffold.InjectProfile. Only flat "key: value" pairs are interpreted;
+// any other line is kept verbatim as a Field with an empty Key.
+type FrontMatter struct {
+	Fields []Field
+
+	open  string // opening "---" line
+	close string // closing "---" line
+}
+
+// Field is one line of front matter
+type Field struct {
+	Key   string
+	Value string
+	raw   string
+}
+
+// Malformed returns the front matter lines that are not "key: value" pairs
+func (f *FrontMatter) Malformed() []string {
+	var lines []string
+	for _, fl := range f.Fields {
+		if fl.Key == "" && strings.TrimSpace(fl.raw) != "" {
+			lines = append(lines, strings.TrimRight(fl.raw, "\r\n"))
+		}
+	}
+	return lines
+}
+
+// Get returns the value for key and whether it was present
+func (f *FrontMatter) Get(key string) (string, bool) {
+	for _, fl := range f.Fields {
+		if fl.Key == key {
+			return fl.Value, true
+		}
+	}
+	return "", false
+}
+
+// Set updates key in place, or appends it if missing. The new line
+// ends the way the opening delimiter does.
+func (f *FrontMatter) Set(key, value string) {
+	eol := "\n"
+	if strings.HasSuffix(f.open, "\r\n") {
+		eol = "\r\n"
+	}
+	raw := key + ": " + value + eol
+	for i := range f.Fields {
+		if f.Fields[i].Key == key {
+			f.Fields[i].Value = value
+			f.Fields[i].raw = raw
+			return
+		}
+	}
+	f.Fields = append(f.Fields, Field{Key: key, Value: value, raw: raw})
+}
+
+// Delete removes key, reporting whether it was present
+func (f *FrontMatter) Delete(key string) bool {
+	for i := range f.Fields {
+		if f.Fields[i].Key == key {
+			f.Fields = append(f.Fields[:i], f.Fields[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// String serializes the front matter including its delimiters
+func (f *FrontMatter) String() string {
+	var b strings.Builder
+	open, close := f.open, f.close
+	if open == "" {
+		open = "---\n"
+	}
+	if close == "" {
+		close = "---\n"
+	}
+	b.WriteString(open)
+	for _, fl := range f.Fields {
+		b.WriteString(fl.raw)
+	}
+	b.WriteString(close)
+	return b.String()
+}
+
+// NewFrontMatter returns an empty front matter block
+func NewFrontMatter() *FrontMatter {
+	return &FrontMatter{}
+}
+
+// parseFrontMatter parses a leading "---" block. It returns nil if src
+// doesn't start with one or the block is never closed; otherwise it also
+// returns the number of bytes consumed.
+func parseFrontMatter(src string) (*FrontMatter, int) {
+	lines := splitLines(src)
+	if len(lines) == 0 || strings.TrimRight(lines[0], "\r\n") != "---" {
+		return nil, 0
+	}
+
+	fm := &FrontMatter{open: lines[0]}
+	consumed := len(lines[0])
+	for _, line := range lines[1:] {
+		consumed += len(line)
+		if strings.TrimRight(line, "\r\n") == "---" {
+			fm.close = line
+			return fm, consumed
+		}
+		fm.Fields = append(fm.Fields, parseField(line))
+	}
+
+	// Unterminated: not front matter
+	return nil, 0
+}
+
+func parseField(line string) Field {
+	text := strings.TrimRight(line, "\r\n")
+	key, value, ok := strings.Cut(text, ":")
+	key = strings.TrimSpace(key)
+	if !ok || key == "" || strings.ContainsAny(key, " \t") || strings.HasPrefix(text, " ") || strings.HasPrefix(key, "#") {
+		return Field{raw: line}
+	}
+	return Field{Key: key, Value: strings.TrimSpace(value), raw: line}
+}
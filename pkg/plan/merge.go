@@ -0,0 +1,233 @@
+package plan
+
+import "strings"
+
+// Conflict is a section changed both in the plan and in its template
+type Conflict struct {
+	Section string
+	Reason  string
+}
+
+// MergeResult describes a three-way merge of template changes into a plan
+type MergeResult struct {
+	Content   string
+	Updated   []string // sections whose template text was updated
+	Added     []string // sections new in the template
+	Removed   []string // sections dropped from the template
+	Conflicts []Conflict
+}
+
+// Changed reports whether the merge changes the plan
+func (r *MergeResult) Changed() bool {
+	return len(r.Updated)+len(r.Added)+len(r.Removed)+len(r.Conflicts) > 0
+}
+
+// Conflict markers written into section bodies that changed on both sides
+const (
+	ConflictStart = "<<<<<<< plan"
+	ConflictSep   = "======="
+	ConflictEnd   = ">>>>>>> template"
+)
+
+// Merge applies the changes between base (the template the plan was
+// created from) and theirs (the template now) to ours (the plan).
+// Sections are matched by title. Text before a section's AUTHOR AREA
+// marker follows the template unless the plan changed it too, which is
+// a conflict marked in the body; author content is always kept.
+// Sections new in the template are added next to their template
+// neighbours, and sections dropped from it are removed unless the plan
+// added content to them.
+func Merge(base, ours, theirs string) *MergeResult {
+	bp, op, tp := Parse(base), Parse(ours), Parse(theirs)
+	res := &MergeResult{}
+
+	op.Sections = mergeSections(op.Sections, bp, tp, res)
+	addNewSections(op, bp, tp, nil, tp.Sections, res)
+
+	res.Content = op.String()
+	return res
+}
+
+func mergeSections(secs []*Section, bp, tp *Plan, res *MergeResult) []*Section {
+	var kept []*Section
+	for _, s := range secs {
+		title := s.Title()
+		b, t := bp.SectionByTitle(title), tp.SectionByTitle(title)
+
+		switch {
+		case b == nil:
+			// Added to the plan by its author
+		case t == nil:
+			pre, _, post := splitAuthor(s.Body)
+			bpre, _, _ := splitAuthor(b.Body)
+			if sameText(pre, bpre) && isBlankNodes(post) && len(s.Children) == 0 {
+				res.Removed = append(res.Removed, title)
+				continue
+			}
+			res.Conflicts = append(res.Conflicts, Conflict{title, "removed from the template but has content in the plan"})
+		default:
+			mergeBody(s, b, t, res)
+		}
+
+		s.Children = mergeSections(s.Children, bp, tp, res)
+		kept = append(kept, s)
+	}
+	return kept
+}
+
+// mergeBody three-way merges the fixed text of one section and keeps the
+// plan's author content
+func mergeBody(s, b, t *Section, res *MergeResult) {
+	title := s.Title()
+	opre, omarker, opost := splitAuthor(s.Body)
+	bpre, _, _ := splitAuthor(b.Body)
+	tpre, tmarker, _ := splitAuthor(t.Body)
+
+	if s.Heading.Raw == b.Heading.Raw && t.Heading.Raw != b.Heading.Raw {
+		s.Heading = t.Heading
+		res.Updated = append(res.Updated, title)
+	}
+
+	var pre []*Node
+	switch {
+	case sameText(opre, bpre):
+		pre = tpre
+		if !sameText(tpre, bpre) && !containsString(res.Updated, title) {
+			res.Updated = append(res.Updated, title)
+		}
+	case sameText(tpre, bpre), sameText(opre, tpre):
+		pre = opre
+	default:
+		pre = conflictNodes(opre, tpre)
+		res.Conflicts = append(res.Conflicts, Conflict{title, "changed in both the plan and the template"})
+	}
+
+	marker := tmarker
+	if marker == nil {
+		marker = omarker
+	}
+
+	body := append([]*Node(nil), pre...)
+	if marker != nil {
+		body = append(body, marker)
+	}
+	s.Body = append(body, opost...)
+}
+
+// addNewSections inserts template sections that neither the base nor the
+// plan has, next to the section that precedes them in the template
+func addNewSections(op, bp, tp *Plan, parent *Section, secs []*Section, res *MergeResult) {
+	for i, t := range secs {
+		title := t.Title()
+		if bp.SectionByTitle(title) != nil || op.SectionByTitle(title) != nil {
+			addNewSections(op, bp, tp, t, t.Children, res)
+			continue
+		}
+
+		res.Added = append(res.Added, title)
+		if i > 0 {
+			if prev := op.SectionByTitle(secs[i-1].Title()); prev != nil {
+				if list, idx := op.locate(prev); list != nil {
+					ensureBlankEnd(prev)
+					*list = insertSection(*list, idx+1, t)
+					continue
+				}
+			}
+		}
+		if parent != nil {
+			if p := op.SectionByTitle(parent.Title()); p != nil {
+				if i == 0 {
+					p.Children = insertSection(p.Children, 0, t)
+				} else {
+					p.Children = append(p.Children, t)
+				}
+				continue
+			}
+		}
+		if len(op.Sections) > 0 {
+			ensureBlankEnd(op.Sections[len(op.Sections)-1])
+		}
+		op.Sections = append(op.Sections, t)
+	}
+}
+
+// locate returns the slice holding target and its index
+func (p *Plan) locate(target *Section) (*[]*Section, int) {
+	var find func(list *[]*Section) (*[]*Section, int)
+	find = func(list *[]*Section) (*[]*Section, int) {
+		for i, s := range *list {
+			if s == target {
+				return list, i
+			}
+			if l, idx := find(&s.Children); l != nil {
+				return l, idx
+			}
+		}
+		return nil, 0
+	}
+	return find(&p.Sections)
+}
+
+func insertSection(list []*Section, i int, s *Section) []*Section {
+	list = append(list, nil)
+	copy(list[i+1:], list[i:])
+	list[i] = s
+	return list
+}
+
+// ensureBlankEnd makes a section (or its last descendant) end with a blank
+// line so a following heading stays separated
+func ensureBlankEnd(s *Section) {
+	for len(s.Children) > 0 {
+		s = s.Children[len(s.Children)-1]
+	}
+	if n := len(s.Body); n > 0 && s.Body[n-1].Kind == NodeBlank {
+		return
+	}
+	s.Append("\n")
+}
+
+// splitAuthor splits a body at its AUTHOR AREA marker
+func splitAuthor(body []*Node) (pre []*Node, marker *Node, post []*Node) {
+	for i, n := range body {
+		if n.Kind == NodeAuthorArea {
+			return body[:i], n, body[i+1:]
+		}
+	}
+	return body, nil, nil
+}
+
+func conflictNodes(ours, theirs []*Node) []*Node {
+	var b strings.Builder
+	b.WriteString(ConflictStart + "\n")
+	b.WriteString(strings.TrimRight(nodesText(ours), "\n") + "\n")
+	b.WriteString(ConflictSep + "\n")
+	b.WriteString(strings.TrimRight(nodesText(theirs), "\n") + "\n")
+	b.WriteString(ConflictEnd + "\n\n")
+	return parseNodes(b.String())
+}
+
+func nodesText(nodes []*Node) string {
+	var b strings.Builder
+	for _, n := range nodes {
+		b.WriteString(n.Raw)
+	}
+	return b.String()
+}
+
+func sameText(a, b []*Node) bool {
+	return strings.TrimSpace(nodesText(a)) == strings.TrimSpace(nodesText(b))
+}
+
+func isBlankNodes(nodes []*Node) bool {
+	return strings.TrimSpace(nodesText(nodes)) == ""
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,260 @@
+package plan
+
+import (
+	"strings"
+	"unicode"
+)
+
+// NodeKind identifies the type of a plan node
+type NodeKind int
+
+const (
+	NodeText       NodeKind = iota // Any other line of markdown
+	NodeBlank                      // Empty or whitespace-only line
+	NodeHeading                    // ATX heading (# ... ######)
+	NodeRule                       // Horizontal rule (---)
+	NodeComment                    // HTML comment, possibly spanning lines
+	NodeAuthorArea                 // <!-- 👤 AUTHOR AREA: ... --> marker
+	NodeSkill                      // Skill install line, active or commented out
+	NodeCode                       // Fenced code block
+)
+
+// Node is one lexical unit of a plan. Raw always holds the exact source
+// bytes, including the trailing newline when present.
+type Node struct {
+	Kind NodeKind
+	Raw  string
+	Line int // 1-based line in the source file (0 for nodes added programmatically)
+
+	// Headings
+	Level       int
+	Emoji       string
+	Title       string
+	Description string
+
+	// Comments and AUTHOR AREA markers: the text inside <!-- -->
+	Text string
+
+	// Skill lines
+	Skill *Skill
+}
+
+// Skill is an "Install ..." line from a skill library
+type Skill struct {
+	Name    string
+	URL     string
+	Enabled bool // false when the line is commented out
+}
+
+// parseNodes splits markdown into nodes. Concatenating the Raw fields of
+// the result yields the input unchanged.
+func parseNodes(src string) []*Node {
+	return parseNodesAt(src, 0)
+}
+
+func parseNodesAt(src string, firstLine int) []*Node {
+	lines := splitLines(src)
+	var nodes []*Node
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+		lineNo := 0
+		if firstLine > 0 {
+			lineNo = firstLine + i
+		}
+
+		switch {
+		case trimmed == "":
+			nodes = append(nodes, &Node{Kind: NodeBlank, Raw: line, Line: lineNo})
+
+		case isFence(trimmed):
+			// Consume through the closing fence so headings inside code are ignored
+			fence := trimmed[:3]
+			end := i + 1
+			for end < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[end]), fence) {
+				end++
+			}
+			if end >= len(lines) {
+				end = len(lines) - 1
+			}
+			nodes = append(nodes, &Node{Kind: NodeCode, Raw: strings.Join(lines[i:end+1], ""), Line: lineNo})
+			i = end
+
+		case isHeading(line):
+			nodes = append(nodes, parseHeading(line, lineNo))
+
+		case isRule(trimmed):
+			nodes = append(nodes, &Node{Kind: NodeRule, Raw: line, Line: lineNo})
+
+		case strings.HasPrefix(trimmed, "<!--"):
+			end := i
+			for end < len(lines)-1 && !strings.Contains(lines[end], "-->") {
+				end++
+			}
+			raw := strings.Join(lines[i:end+1], "")
+			n := parseComment(raw, lineNo)
+
+			// A commented "Install X" followed by a commented URL is one skill
+			if n.Kind == NodeSkill && n.Skill.URL == "" && end+1 < len(lines) {
+				if url, ok := commentedURL(lines[end+1]); ok {
+					n.Skill.URL = url
+					n.Raw += lines[end+1]
+					end++
+				}
+			}
+
+			nodes = append(nodes, n)
+			i = end
+
+		case isActiveSkill(trimmed):
+			name, url := splitSkill(strings.TrimPrefix(trimmed, "Install "))
+			nodes = append(nodes, &Node{
+				Kind:  NodeSkill,
+				Raw:   line,
+				Line:  lineNo,
+				Skill: &Skill{Name: name, URL: url, Enabled: true},
+			})
+
+		default:
+			nodes = append(nodes, &Node{Kind: NodeText, Raw: line, Line: lineNo})
+		}
+	}
+
+	return nodes
+}
+
+// splitLines splits s into lines, keeping line terminators
+func splitLines(s string) []string {
+	var lines []string
+	for len(s) > 0 {
+		i := strings.IndexByte(s, '\n')
+		if i < 0 {
+			lines = append(lines, s)
+			break
+		}
+		lines = append(lines, s[:i+1])
+		s = s[i+1:]
+	}
+	return lines
+}
+
+func isFence(trimmed string) bool {
+	return strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~")
+}
+
+func isHeading(line string) bool {
+	level := 0
+	for level < len(line) && line[level] == '#' {
+		level++
+	}
+	if level == 0 || level > 6 {
+		return false
+	}
+	rest := strings.TrimRight(line[level:], "\r\n")
+	return rest == "" || rest[0] == ' ' || rest[0] == '\t'
+}
+
+func isRule(trimmed string) bool {
+	if len(trimmed) < 3 {
+		return false
+	}
+	c := trimmed[0]
+	if c != '-' && c != '*' && c != '_' {
+		return false
+	}
+	for i := 0; i < len(trimmed); i++ {
+		if trimmed[i] != c {
+			return false
+		}
+	}
+	return true
+}
+
+// parseHeading splits "## 🔧 First Time Setup — Run once" into its parts
+func parseHeading(line string, lineNo int) *Node {
+	level := 0
+	for line[level] == '#' {
+		level++
+	}
+	text := strings.TrimSpace(line[level:])
+
+	n := &Node{Kind: NodeHeading, Raw: line, Line: lineNo, Level: level}
+
+	// Leading emoji: a first word with no letters or digits
+	if fields := strings.Fields(text); len(fields) > 1 && !hasAlnum(fields[0]) {
+		n.Emoji = fields[0]
+		text = strings.TrimSpace(strings.TrimPrefix(text, fields[0]))
+	}
+
+	if title, desc, ok := strings.Cut(text, " — "); ok {
+		n.Title = strings.TrimSpace(title)
+		n.Description = strings.TrimSpace(desc)
+	} else {
+		n.Title = text
+	}
+	return n
+}
+
+func hasAlnum(s string) bool {
+	for _, r := range s {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseComment classifies an HTML comment as an AUTHOR AREA marker,
+// a commented-out skill, or a plain comment
+func parseComment(raw string, lineNo int) *Node {
+	inner := strings.TrimSpace(raw)
+	inner = strings.TrimPrefix(inner, "<!--")
+	inner = strings.TrimSuffix(inner, "-->")
+	inner = strings.TrimSpace(inner)
+
+	label := strings.TrimSpace(strings.TrimPrefix(inner, "👤"))
+	if strings.HasPrefix(label, "AUTHOR AREA") {
+		text := strings.TrimPrefix(label, "AUTHOR AREA")
+		text = strings.TrimSpace(strings.TrimPrefix(text, ":"))
+		return &Node{Kind: NodeAuthorArea, Raw: raw, Line: lineNo, Text: text}
+	}
+
+	if !strings.Contains(inner, "\n") && strings.HasPrefix(inner, "Install ") {
+		name, url := splitSkill(strings.TrimPrefix(inner, "Install "))
+		return &Node{
+			Kind:  NodeSkill,
+			Raw:   raw,
+			Line:  lineNo,
+			Text:  inner,
+			Skill: &Skill{Name: name, URL: url},
+		}
+	}
+
+	return &Node{Kind: NodeComment, Raw: raw, Line: lineNo, Text: inner}
+}
+
+func isActiveSkill(trimmed string) bool {
+	return strings.HasPrefix(trimmed, "Install ") && strings.Contains(trimmed, "://")
+}
+
+// splitSkill splits "Quarto Skill: https://..." into name and URL
+func splitSkill(s string) (name, url string) {
+	if i := strings.Index(s, "http"); i >= 0 {
+		return strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(s[:i]), ":")), strings.TrimSpace(s[i:])
+	}
+	return strings.TrimSpace(s), ""
+}
+
+// commentedURL matches a line of the form "<!-- https://... -->"
+func commentedURL(line string) (string, bool) {
+	t := strings.TrimSpace(line)
+	if !strings.HasPrefix(t, "<!--") || !strings.HasSuffix(t, "-->") {
+		return "", false
+	}
+	inner := strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(t, "<!--"), "-->"))
+	if strings.HasPrefix(inner, "http://") || strings.HasPrefix(inner, "https://") {
+		return inner, true
+	}
+	return "", false
+}
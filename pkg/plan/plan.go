@@ -0,0 +1,312 @@
+// Package plan parses IRL plan files (main-plan.md) into a typed tree.
+//
+// The parser is lossless: every byte of the source is kept on some node,
+// so String() on an unmodified Plan returns the original input exactly.
+// Tools can therefore edit one section of a plan without disturbing the
+// formatting of the rest.
+package plan
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FileName is the conventional name of a plan file
+const FileName = "main-plan.md"
+
+// Plan is a parsed main-plan.md
+type Plan struct {
+	FrontMatter *FrontMatter // nil when the plan has no front matter
+	Preamble    []*Node      // content before the first heading
+	Sections    []*Section   // top-level sections (children nest by heading level)
+}
+
+// SectionKind identifies the well-known sections of the irl-basic template
+type SectionKind int
+
+const (
+	SectionOther SectionKind = iota
+	SectionFirstTimeSetup
+	SectionBeforeEachLoop
+	SectionInstructionLoop
+	SectionOneTimeInstructions
+	SectionFormattingGuidelines
+	SectionAfterEachLoop
+	SectionSkillLibrary
+)
+
+// sectionTitles maps heading titles to their well-known kinds
+var sectionTitles = map[string]SectionKind{
+	"First Time Setup":      SectionFirstTimeSetup,
+	"Before Each Loop":      SectionBeforeEachLoop,
+	"Instruction Loop":      SectionInstructionLoop,
+	"One-Time Instructions": SectionOneTimeInstructions,
+	"Formatting Guidelines": SectionFormattingGuidelines,
+	"After Each Loop":       SectionAfterEachLoop,
+	"Skill Library":         SectionSkillLibrary,
+}
+
+func (k SectionKind) String() string {
+	for title, kind := range sectionTitles {
+		if kind == k {
+			return title
+		}
+	}
+	return "Other"
+}
+
+// Section is a heading plus everything up to the next heading of the same
+// or a higher level
+type Section struct {
+	Heading  *Node
+	Kind     SectionKind
+	Body     []*Node
+	Children []*Section
+}
+
+// Title returns the section's heading title (without emoji or description)
+func (s *Section) Title() string {
+	return s.Heading.Title
+}
+
+// Level returns the heading level (1 for "#", 2 for "##", ...)
+func (s *Section) Level() int {
+	return s.Heading.Level
+}
+
+// BodyString returns the section body without its heading or children
+func (s *Section) BodyString() string {
+	var b strings.Builder
+	for _, n := range s.Body {
+		b.WriteString(n.Raw)
+	}
+	return b.String()
+}
+
+// String returns the section source, including its heading and children
+func (s *Section) String() string {
+	var b strings.Builder
+	s.write(&b)
+	return b.String()
+}
+
+func (s *Section) write(b *strings.Builder) {
+	b.WriteString(s.Heading.Raw)
+	for _, n := range s.Body {
+		b.WriteString(n.Raw)
+	}
+	for _, c := range s.Children {
+		c.write(b)
+	}
+}
+
+// SetBody replaces the section body with the given markdown. Children are kept.
+func (s *Section) SetBody(text string) {
+	s.Body = parseNodes(text)
+}
+
+// Append adds markdown to the end of the section body, before any children.
+// A newline is inserted first if the current body doesn't end with one.
+func (s *Section) Append(text string) {
+	if len(s.Body) > 0 {
+		last := s.Body[len(s.Body)-1]
+		if !strings.HasSuffix(last.Raw, "\n") {
+			last.Raw += "\n"
+		}
+	} else if !strings.HasSuffix(s.Heading.Raw, "\n") {
+		s.Heading.Raw += "\n"
+	}
+	s.Body = append(s.Body, parseNodes(text)...)
+}
+
+// AuthorArea returns the section's AUTHOR AREA marker and the author content
+// that follows it. The marker is nil when the section has none.
+func (s *Section) AuthorArea() (*Node, string) {
+	for i, n := range s.Body {
+		if n.Kind != NodeAuthorArea {
+			continue
+		}
+		var b strings.Builder
+		for _, rest := range s.Body[i+1:] {
+			b.WriteString(rest.Raw)
+		}
+		return n, b.String()
+	}
+	return nil, ""
+}
+
+// SetAuthorContent replaces everything after the AUTHOR AREA marker.
+// It returns false if the section has no marker.
+func (s *Section) SetAuthorContent(text string) bool {
+	for i, n := range s.Body {
+		if n.Kind != NodeAuthorArea {
+			continue
+		}
+		if !strings.HasSuffix(n.Raw, "\n") {
+			n.Raw += "\n"
+		}
+		s.Body = append(s.Body[:i+1], parseNodes(text)...)
+		return true
+	}
+	return false
+}
+
+// Parse parses plan source into a Plan
+func Parse(src string) *Plan {
+	p := &Plan{}
+
+	rest := src
+	firstLine := 1
+	if fm, n := parseFrontMatter(src); fm != nil {
+		p.FrontMatter = fm
+		rest = src[n:]
+		firstLine += strings.Count(src[:n], "\n")
+	}
+
+	nodes := parseNodesAt(rest, firstLine)
+
+	// Build the section tree with a stack of open sections
+	var stack []*Section
+	for _, n := range nodes {
+		if n.Kind != NodeHeading {
+			if len(stack) == 0 {
+				p.Preamble = append(p.Preamble, n)
+			} else {
+				top := stack[len(stack)-1]
+				top.Body = append(top.Body, n)
+			}
+			continue
+		}
+
+		sec := &Section{Heading: n, Kind: sectionTitles[n.Title]}
+		for len(stack) > 0 && stack[len(stack)-1].Level() >= n.Level {
+			stack = stack[:len(stack)-1]
+		}
+		if len(stack) == 0 {
+			p.Sections = append(p.Sections, sec)
+		} else {
+			parent := stack[len(stack)-1]
+			parent.Children = append(parent.Children, sec)
+		}
+		stack = append(stack, sec)
+	}
+
+	return p
+}
+
+// ParseFile reads and parses a plan file
+func ParseFile(path string) (*Plan, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(string(data)), nil
+}
+
+// WriteFile serializes the plan to path
+func (p *Plan) WriteFile(path string) error {
+	return os.WriteFile(path, []byte(p.String()), 0644)
+}
+
+// String serializes the plan back to markdown
+func (p *Plan) String() string {
+	var b strings.Builder
+	if p.FrontMatter != nil {
+		b.WriteString(p.FrontMatter.String())
+	}
+	for _, n := range p.Preamble {
+		b.WriteString(n.Raw)
+	}
+	for _, s := range p.Sections {
+		s.write(&b)
+	}
+	return b.String()
+}
+
+// Walk visits every section depth-first in document order.
+// Returning false from fn stops the walk.
+func (p *Plan) Walk(fn func(*Section) bool) {
+	var visit func([]*Section) bool
+	visit = func(secs []*Section) bool {
+		for _, s := range secs {
+			if !fn(s) || !visit(s.Children) {
+				return false
+			}
+		}
+		return true
+	}
+	visit(p.Sections)
+}
+
+// AllSections returns every section in document order
+func (p *Plan) AllSections() []*Section {
+	var all []*Section
+	p.Walk(func(s *Section) bool {
+		all = append(all, s)
+		return true
+	})
+	return all
+}
+
+// Section returns the first section of the given kind, or nil
+func (p *Plan) Section(kind SectionKind) *Section {
+	var found *Section
+	p.Walk(func(s *Section) bool {
+		if s.Kind == kind {
+			found = s
+			return false
+		}
+		return true
+	})
+	return found
+}
+
+// SectionByTitle returns the first section whose title matches (case-insensitive), or nil
+func (p *Plan) SectionByTitle(title string) *Section {
+	var found *Section
+	p.Walk(func(s *Section) bool {
+		if strings.EqualFold(s.Title(), title) {
+			found = s
+			return false
+		}
+		return true
+	})
+	return found
+}
+
+// Skills returns every skill line in the plan, enabled or commented out
+func (p *Plan) Skills() []*Node {
+	var skills []*Node
+	collect := func(nodes []*Node) {
+		for _, n := range nodes {
+			if n.Kind == NodeSkill {
+				skills = append(skills, n)
+			}
+		}
+	}
+	collect(p.Preamble)
+	p.Walk(func(s *Section) bool {
+		collect(s.Body)
+		return true
+	})
+	return skills
+}
+
+// Find returns the plan file path for a project directory.
+// It checks plans/main-plan.md (current standard), then the legacy
+// main-plan.md and 01-plans/main-plan.md locations.
+func Find(projectDir string) (string, error) {
+	candidates := []string{
+		filepath.Join(projectDir, "plans", FileName),
+		filepath.Join(projectDir, FileName),
+		filepath.Join(projectDir, "01-plans", FileName),
+	}
+	for _, c := range candidates {
+		if info, err := os.Stat(c); err == nil && !info.IsDir() {
+			return c, nil
+		}
+	}
+	return "", fmt.Errorf("no %s found in %s", FileName, projectDir)
+}
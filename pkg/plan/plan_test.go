@@ -0,0 +1,69 @@
+package plan_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/drpedapati/irl-template/pkg/plan"
+	"github.com/drpedapati/irl-template/pkg/templates"
+)
+
+// roundTrip checks that src comes back byte for byte
+func roundTrip(t *testing.T, src string) *plan.Plan {
+	t.Helper()
+	p := plan.Parse(src)
+	if got := p.String(); got != src {
+		t.Errorf("round trip changed the plan\n got: %q\nwant: %q", got, src)
+	}
+	return p
+}
+
+func TestRoundTripEmbeddedTemplate(t *testing.T) {
+	src := templates.EmbeddedTemplates["irl-basic"].Content
+	p := roundTrip(t, src)
+	if p.Section(plan.SectionFirstTimeSetup) == nil {
+		t.Error("the setup section wasn't recognised")
+	}
+	if len(p.Skills()) == 0 {
+		t.Error("no skills found in the template")
+	}
+
+	// The same template saved on Windows
+	roundTrip(t, strings.ReplaceAll(src, "\n", "\r\n"))
+}
+
+func TestRoundTripEdgeCases(t *testing.T) {
+	for _, tc := range []struct {
+		name, src string
+		sections  int
+	}{
+		{"empty", "", 0},
+		{"no trailing newline", "# Plan\n\n## Notes\nlast line", 2},
+		{"crlf", "---\r\ntitle: Study\r\n---\r\n# Plan\r\n\r\n## Notes\r\n- one\r\n", 2},
+		{"crlf front matter without newline after", "---\r\ntitle: Study\r\n---", 0},
+		{"unterminated front matter", "---\ntitle: Study\n# Plan\n", 1},
+		{"malformed front matter", "---\n  indented: value\nno colon\n---\n# Plan\n", 1},
+		{"unclosed fence", "# Plan\n\n```r\n## not a heading\nx <- 1\n", 1},
+		{"unclosed tilde fence", "# Plan\n~~~\n# inside\n", 1},
+		{"unclosed comment", "# Plan\n<!-- 👤 AUTHOR AREA\n## still a comment\n", 1},
+		{"commented skill", "## Setup\n<!-- Install Quarto Skill -->\n<!-- https://example.org/quarto -->\n", 1},
+		{"blank lines with spaces", "# Plan\n  \n\t\n## Notes\n", 2},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p := roundTrip(t, tc.src)
+			if got := len(p.AllSections()); got != tc.sections {
+				t.Errorf("%d sections, want %d", got, tc.sections)
+			}
+		})
+	}
+}
+
+func TestRoundTripAfterFrontMatterEdit(t *testing.T) {
+	src := "---\r\ntitle: Study\r\n---\r\n# Plan\r\n"
+	p := plan.Parse(src)
+	p.FrontMatter.Set("status", "active")
+	want := "---\r\ntitle: Study\r\nstatus: active\r\n---\r\n# Plan\r\n"
+	if got := p.String(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,30 @@
+package projects
+
+import "github.com/drpedapati/irl-template/pkg/config"
+
+// Adopt modes say how a folder is brought into the workspace
+const (
+	AdoptCopy    = "copy"     // copy it into the workspace
+	AdoptMove    = "move"     // move it into the workspace
+	AdoptInPlace = "in-place" // leave it where it is and scan it as its own root
+	AdoptSymlink = "symlink"  // leave it where it is and link to it from the workspace
+)
+
+// AdoptModes lists the adopt modes, default first
+var AdoptModes = []string{AdoptCopy, AdoptMove, AdoptInPlace, AdoptSymlink}
+
+// ValidAdoptMode reports whether mode is a known adopt mode
+func ValidAdoptMode(mode string) bool {
+	for _, m := range AdoptModes {
+		if mode == m {
+			return true
+		}
+	}
+	return false
+}
+
+// RegisterInPlace records a project folder outside the workspace as a
+// root of its own, so scans list it where it is
+func RegisterInPlace(dir string) error {
+	return config.AddWorkspace(config.Workspace{Path: dir, Project: true})
+}
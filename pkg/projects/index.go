@@ -0,0 +1,280 @@
+package projects
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/drpedapati/irl-template/pkg/config"
+)
+
+// indexVersion changes when the index format does; older indexes are rebuilt
+const indexVersion = 1
+
+// scanWorkers bounds how many folders are read at once
+const scanWorkers = 8
+
+// Index caches what a scan learned about each folder so later scans only
+// re-read folders that changed. It's stored in ~/.irl/index.json.
+type Index struct {
+	Version int                    `json:"version"`
+	Folders map[string]*indexEntry `json:"folders"`
+
+	path string
+	next map[string]*indexEntry // folders seen by the current scan
+	mu   sync.Mutex
+}
+
+// indexEntry records one folder. A folder is unchanged while the mtimes
+// in Stamps match: its own, which moves when entries are added, removed
+// or renamed, and its plan folders', which move when a plan appears.
+type indexEntry struct {
+	Stamps   map[string]int64 `json:"stamps"`
+	Project  *Project         `json:"project,omitempty"`
+	Plan     string           `json:"plan,omitempty"`
+	MetaTime int64            `json:"meta_mtime,omitempty"`
+	Listed   bool             `json:"listed,omitempty"` // Subdirs was read
+	Subdirs  []string         `json:"subdirs,omitempty"`
+}
+
+// IndexPath returns the location of the workspace index
+func IndexPath() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".irl", "index.json")
+}
+
+// LoadIndex reads the workspace index. A missing, unreadable or outdated
+// index is returned empty.
+func LoadIndex() *Index {
+	idx := &Index{path: IndexPath()}
+	if data, err := os.ReadFile(idx.path); err == nil {
+		if json.Unmarshal(data, idx) != nil || idx.Version != indexVersion {
+			idx.Folders = nil
+		}
+	}
+	idx.Version = indexVersion
+	if idx.Folders == nil {
+		idx.Folders = map[string]*indexEntry{}
+	}
+	return idx
+}
+
+// Save writes the index, replacing the file atomically
+func (idx *Index) Save() error {
+	if err := os.MkdirAll(filepath.Dir(idx.path), 0755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(idx)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(idx.path), ".index-*.json")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), idx.path)
+}
+
+// Reindex discards the index and rescans the roots from the filesystem
+func Reindex(roots []config.Workspace) ([]Project, error) {
+	idx := &Index{path: IndexPath(), Version: indexVersion, Folders: map[string]*indexEntry{}}
+	found, err := idx.scan(roots)
+	if saveErr := idx.Save(); saveErr != nil && err == nil {
+		err = saveErr
+	}
+	return found, err
+}
+
+// scan walks the roots concurrently and replaces their folders in the index
+// with what it saw
+func (idx *Index) scan(roots []config.Workspace) ([]Project, error) {
+	var (
+		wg    sync.WaitGroup
+		found []Project
+		errs  []error
+		sem   = make(chan struct{}, scanWorkers)
+	)
+	idx.next = map[string]*indexEntry{}
+
+	var visit func(root config.Workspace, dir string, depth int)
+	visit = func(root config.Workspace, dir string, depth int) {
+		defer wg.Done()
+
+		sem <- struct{}{}
+		e, err := idx.entry(root, dir, depth)
+		<-sem
+
+		idx.mu.Lock()
+		defer idx.mu.Unlock()
+		if err != nil {
+			if depth == 0 {
+				errs = append(errs, err)
+			}
+			return
+		}
+		idx.next[dir] = e
+
+		if e.Project != nil && (depth > 0 || root.Project) {
+			p := *e.Project
+			p.Root = root.Name()
+			found = append(found, p)
+			return
+		}
+		for _, name := range e.Subdirs {
+			if skipFolder(root, dir, name) {
+				continue
+			}
+			wg.Add(1)
+			go visit(root, filepath.Join(dir, name), depth+1)
+		}
+	}
+
+	for _, root := range roots {
+		wg.Add(1)
+		go visit(root, filepath.Clean(root.Path), 0)
+	}
+	wg.Wait()
+
+	// Keep folders of roots that weren't scanned; drop the rest as stale
+	for dir, e := range idx.Folders {
+		if !underAny(dir, roots) {
+			idx.next[dir] = e
+		}
+	}
+	idx.Folders, idx.next = idx.next, nil
+
+	// Sort by modified time, most recent first
+	sort.Slice(found, func(i, j int) bool {
+		return found[i].Modified.After(found[j].Modified)
+	})
+
+	return found, errors.Join(errs...)
+}
+
+// entry returns a folder's index entry, reusing the recorded one when the
+// folder hasn't changed
+func (idx *Index) entry(root config.Workspace, dir string, depth int) (*indexEntry, error) {
+	// Search below the folder unless it's at the depth limit or _-prefixed.
+	// Projects adopted in place are the root itself.
+	wantList := depth < root.Depth() && !(depth > 0 && strings.HasPrefix(filepath.Base(dir), "_")) && !root.Project
+
+	idx.mu.Lock()
+	old := idx.Folders[dir]
+	idx.mu.Unlock()
+
+	if old != nil && ((old.Project != nil && depth > 0) || old.Listed || !wantList) {
+		if e, ok := refresh(dir, old); ok {
+			return e, nil
+		}
+	}
+	return buildEntry(dir, depth, wantList)
+}
+
+// buildEntry reads a folder from the filesystem
+func buildEntry(dir string, depth int, wantList bool) (*indexEntry, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, err
+	}
+	// Stamps are taken before reading so changes made meanwhile show up next time
+	e := &indexEntry{Stamps: map[string]int64{dir: info.ModTime().UnixNano()}}
+	for _, sub := range []string{"plans", "01-plans"} {
+		if info, err := os.Stat(filepath.Join(dir, sub)); err == nil {
+			e.Stamps[filepath.Join(dir, sub)] = info.ModTime().UnixNano()
+		}
+	}
+
+	if p, plan, ok := loadProject(dir); ok {
+		e.Project, e.Plan = &p, plan
+		e.MetaTime = mtime(MetaPath(dir))
+		if depth > 0 {
+			return e, nil
+		}
+	}
+
+	if wantList {
+		entries, err := os.ReadDir(dir)
+		if err != nil && depth == 0 {
+			return nil, err
+		}
+		e.Listed = true
+		for _, entry := range entries {
+			if strings.HasPrefix(entry.Name(), ".") {
+				continue
+			}
+			// Follow links to folders, e.g. projects adopted as symlinks
+			isDir := entry.IsDir()
+			if entry.Type()&os.ModeSymlink != 0 {
+				info, err := os.Stat(filepath.Join(dir, entry.Name()))
+				isDir = err == nil && info.IsDir()
+			}
+			if isDir {
+				e.Subdirs = append(e.Subdirs, entry.Name())
+			}
+		}
+	}
+	return e, nil
+}
+
+// refresh checks a recorded folder against the filesystem. Project
+// folders have their plan and metadata restatted; anything else that
+// changed means the folder must be read again.
+func refresh(dir string, old *indexEntry) (*indexEntry, bool) {
+	for path, stamp := range old.Stamps {
+		if mtime(path) != stamp {
+			return nil, false
+		}
+	}
+	if old.Project == nil {
+		return old, true
+	}
+
+	info, err := os.Stat(old.Plan)
+	if err != nil {
+		return nil, false
+	}
+	e := *old
+	p := *old.Project
+	p.Modified = info.ModTime()
+	if t := mtime(MetaPath(dir)); t != old.MetaTime {
+		p = Project{Name: p.Name, Path: p.Path, Modified: p.Modified}
+		meta, _ := LoadMeta(dir)
+		p.applyMeta(meta)
+		e.MetaTime = t
+	}
+	e.Project = &p
+	return &e, true
+}
+
+// mtime returns a file's modification time in nanoseconds, or 0 if it's missing
+func mtime(path string) int64 {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0
+	}
+	return info.ModTime().UnixNano()
+}
+
+// underAny reports whether dir is one of the roots or inside one
+func underAny(dir string, roots []config.Workspace) bool {
+	for _, root := range roots {
+		base := filepath.Clean(root.Path)
+		if dir == base || strings.HasPrefix(dir, base+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
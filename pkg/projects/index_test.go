@@ -0,0 +1,190 @@
+package projects
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/drpedapati/irl-template/pkg/config"
+)
+
+// newWorkspace points the index at a fresh home and returns an empty root
+func newWorkspace(t *testing.T) string {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	ws := filepath.Join(t.TempDir(), "ws")
+	if err := os.Mkdir(ws, 0755); err != nil {
+		t.Fatal(err)
+	}
+	return ws
+}
+
+func makeProject(t *testing.T, dir string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Join(dir, "plans"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "plans", "main-plan.md"), []byte("# Plan\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tick(t, filepath.Dir(dir))
+}
+
+// tick moves a folder's mtime forward, so changes register on filesystems
+// with coarse timestamps
+func tick(t *testing.T, dir string) {
+	t.Helper()
+	info, err := os.Stat(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	later := info.ModTime().Add(time.Second)
+	if err := os.Chtimes(dir, later, later); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func scanNames(t *testing.T, roots ...config.Workspace) []string {
+	t.Helper()
+	found, err := ScanRoots(roots)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return projectNames(found)
+}
+
+func projectNames(found []Project) []string {
+	names := []string{}
+	for _, p := range found {
+		names = append(names, p.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestScanFollowsAddRemoveRename(t *testing.T) {
+	ws := newWorkspace(t)
+	root := config.Workspace{Path: ws}
+
+	if got := scanNames(t, root); len(got) != 0 {
+		t.Fatalf("empty workspace: found %v", got)
+	}
+
+	makeProject(t, filepath.Join(ws, "alpha"))
+	makeProject(t, filepath.Join(ws, "group", "beta"))
+	if got := strings.Join(scanNames(t, root), ","); got != "alpha,beta" {
+		t.Errorf("after add: found %s, want alpha,beta", got)
+	}
+	if len(LoadIndex().Folders) == 0 {
+		t.Error("the scan saved no index")
+	}
+
+	if err := os.RemoveAll(filepath.Join(ws, "alpha")); err != nil {
+		t.Fatal(err)
+	}
+	tick(t, ws)
+	if got := strings.Join(scanNames(t, root), ","); got != "beta" {
+		t.Errorf("after remove: found %s, want beta", got)
+	}
+	if _, ok := LoadIndex().Folders[filepath.Join(ws, "alpha")]; ok {
+		t.Error("the index kept the removed folder")
+	}
+
+	group := filepath.Join(ws, "group")
+	if err := os.Rename(filepath.Join(group, "beta"), filepath.Join(group, "gamma")); err != nil {
+		t.Fatal(err)
+	}
+	tick(t, group)
+	found, err := ScanRoots([]config.Workspace{root})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(projectNames(found), ","); got != "gamma" {
+		t.Errorf("after rename: found %s, want gamma", got)
+	}
+	if found[0].Path != filepath.Join(group, "gamma") {
+		t.Errorf("renamed project path = %s", found[0].Path)
+	}
+}
+
+func TestScanPicksUpPlanAndMetaChanges(t *testing.T) {
+	ws := newWorkspace(t)
+	root := config.Workspace{Path: ws}
+	dir := filepath.Join(ws, "alpha")
+	makeProject(t, dir)
+	meta := NewMeta("testing")
+	if err := meta.Save(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	found, err := ScanRoots([]config.Workspace{root})
+	if err != nil || len(found) != 1 {
+		t.Fatalf("found %v, %v", found, err)
+	}
+	if found[0].Status != StatusActive {
+		t.Errorf("status = %q, want %q", found[0].Status, StatusActive)
+	}
+
+	// Edit the plan and metadata in place: the folders' mtimes stay put, so
+	// the recorded entry is refreshed rather than rebuilt
+	plan := filepath.Join(dir, "plans", "main-plan.md")
+	edited := found[0].Modified.Add(time.Hour)
+	if err := os.Chtimes(plan, edited, edited); err != nil {
+		t.Fatal(err)
+	}
+	meta.Status = StatusArchived
+	meta.Tags = []string{"eeg"}
+	if err := meta.Save(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	found, err = ScanRoots([]config.Workspace{root})
+	if err != nil || len(found) != 1 {
+		t.Fatalf("found %v, %v", found, err)
+	}
+	p := found[0]
+	if !p.Modified.Equal(edited) {
+		t.Errorf("modified = %s, want %s", p.Modified, edited)
+	}
+	if p.Status != StatusArchived || p.Purpose != "testing" || !p.HasTag("eeg") {
+		t.Errorf("metadata not refreshed: %+v", p)
+	}
+}
+
+func TestScanDropsRemovedRoots(t *testing.T) {
+	ws := newWorkspace(t)
+	other := filepath.Join(filepath.Dir(ws), "other")
+	makeProject(t, filepath.Join(ws, "alpha"))
+	makeProject(t, filepath.Join(other, "beta"))
+	roots := []config.Workspace{{Path: ws}, {Path: other}}
+
+	if got := strings.Join(scanNames(t, roots...), ","); got != "alpha,beta" {
+		t.Fatalf("found %s, want alpha,beta", got)
+	}
+
+	// A root left out of a scan keeps its folders for the next full scan
+	scanNames(t, roots[0])
+	if _, ok := LoadIndex().Folders[filepath.Join(other, "beta")]; !ok {
+		t.Error("scanning one root dropped another root's folders")
+	}
+
+	// A root that's gone is reported and forgotten; the others still scan
+	if err := os.RemoveAll(other); err != nil {
+		t.Fatal(err)
+	}
+	found, err := ScanRoots(roots)
+	if err == nil {
+		t.Error("scanning a missing root returned no error")
+	}
+	if got := strings.Join(projectNames(found), ","); got != "alpha" {
+		t.Errorf("found %s, want alpha", got)
+	}
+	for dir := range LoadIndex().Folders {
+		if dir == other || strings.HasPrefix(dir, other+string(filepath.Separator)) {
+			t.Errorf("the index kept %s from the removed root", dir)
+		}
+	}
+}
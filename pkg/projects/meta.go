@@ -0,0 +1,155 @@
+package projects
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/drpedapati/irl-template/pkg/config"
+)
+
+// MetaDir holds per-project irl state that is committed with the project
+const MetaDir = ".irl"
+
+const (
+	metaFile = "project.json"
+	baseFile = "template.md"
+)
+
+// Project statuses
+const (
+	StatusActive   = "active"
+	StatusPaused   = "paused"
+	StatusArchived = "archived"
+)
+
+// Statuses lists the valid project statuses
+var Statuses = []string{StatusActive, StatusPaused, StatusArchived}
+
+// Meta is the project metadata written by irl init and irl adopt: what the
+// project is about, plus which template produced the plan and with what
+// values, so the plan can be upgraded later
+type Meta struct {
+	Purpose       string            `json:"purpose,omitempty"`
+	Status        string            `json:"status,omitempty"`
+	Tags          []string          `json:"tags,omitempty"`
+	Owner         string            `json:"owner,omitempty"`
+	Collaborators []string          `json:"collaborators,omitempty"`
+	Template      string            `json:"template,omitempty"`
+	Source        string            `json:"source,omitempty"`        // template source, e.g. "default" or "custom"
+	Hash          string            `json:"template_hash,omitempty"` // hash of the resolved template before rendering
+	Vars          map[string]string `json:"vars,omitempty"`          // variable values the plan was rendered with
+	PlanHash      string            `json:"plan_hash,omitempty"`     // hash of the plan as irl last wrote it, see HashPlan
+	IRLVersion    string            `json:"irl_version"`
+	Created       time.Time         `json:"created"`
+	Upgraded      *time.Time        `json:"upgraded,omitempty"`
+}
+
+// NewMeta returns metadata for a new project owned by the profile's user
+func NewMeta(purpose string) *Meta {
+	return &Meta{
+		Purpose: purpose,
+		Status:  StatusActive,
+		Owner:   config.GetProfile().Name,
+		Created: time.Now(),
+	}
+}
+
+// ValidStatus reports whether s is a known project status
+func ValidStatus(s string) bool {
+	for _, v := range Statuses {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
+// MetaPath returns the path of a project's metadata file
+func MetaPath(projectDir string) string {
+	return filepath.Join(projectDir, MetaDir, metaFile)
+}
+
+// BasePath returns the path of the rendered template the plan started from,
+// the common ancestor for upgrades
+func BasePath(projectDir string) string {
+	return filepath.Join(projectDir, MetaDir, baseFile)
+}
+
+// LoadMeta reads a project's metadata. The error satisfies os.IsNotExist when
+// the project has none.
+func LoadMeta(projectDir string) (*Meta, error) {
+	data, err := os.ReadFile(MetaPath(projectDir))
+	if err != nil {
+		return nil, err
+	}
+	var m Meta
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", filepath.Join(MetaDir, metaFile), err)
+	}
+	return &m, nil
+}
+
+// Save writes the metadata into the project
+func (m *Meta) Save(projectDir string) error {
+	if err := os.MkdirAll(filepath.Join(projectDir, MetaDir), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(MetaPath(projectDir), append(data, '\n'), 0644)
+}
+
+// ReadBase returns the rendered template the plan started from
+func ReadBase(projectDir string) (string, error) {
+	data, err := os.ReadFile(BasePath(projectDir))
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// WriteBase stores the rendered template the plan started from
+func WriteBase(projectDir, content string) error {
+	if err := os.MkdirAll(filepath.Join(projectDir, MetaDir), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(BasePath(projectDir), []byte(content), 0644)
+}
+
+// Lock writes the project metadata and, when it names a template, a copy
+// of the rendered template for later three-way merges
+func Lock(projectDir string, m *Meta, rendered string) error {
+	if m.Created.IsZero() {
+		m.Created = time.Now()
+	}
+	if err := m.Save(projectDir); err != nil {
+		return fmt.Errorf("failed to write project metadata: %w", err)
+	}
+	if m.Template == "" {
+		return nil
+	}
+	if err := WriteBase(projectDir, rendered); err != nil {
+		return fmt.Errorf("failed to write template base: %w", err)
+	}
+	return nil
+}
+
+// HashContent returns the content hash stored in the lock
+func HashContent(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// HashPlan returns the hash recorded for a plan irl wrote, ignoring
+// leading and trailing whitespace
+func HashPlan(content string) string {
+	return HashContent(strings.TrimSpace(content))
+}
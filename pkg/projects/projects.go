@@ -3,84 +3,178 @@ package projects
 import (
 	"os"
 	"path/filepath"
-	"sort"
+	"strings"
 	"time"
 
 	"github.com/drpedapati/irl-template/pkg/config"
 )
 
-// Project represents a discovered IRL project in the workspace
+// Project represents a discovered IRL project in the workspace. Fields after
+// Modified come from .irl/project.json when the project has one.
 type Project struct {
-	Name     string    `json:"name"`
-	Path     string    `json:"path"`
-	Modified time.Time `json:"modified"`
+	Name          string     `json:"name"`
+	Path          string     `json:"path"`
+	Modified      time.Time  `json:"modified"`
+	Root          string     `json:"root,omitempty"` // label of the workspace root it was found in
+	Purpose       string     `json:"purpose,omitempty"`
+	Status        string     `json:"status"`
+	Tags          []string   `json:"tags,omitempty"`
+	Owner         string     `json:"owner,omitempty"`
+	Collaborators []string   `json:"collaborators,omitempty"`
+	Template      string     `json:"template,omitempty"`
+	Created       *time.Time `json:"created,omitempty"`
 }
 
-// Scan discovers IRL projects in the configured workspace directory.
+// HasTag reports whether the project is tagged with tag (case-insensitive)
+func (p Project) HasTag(tag string) bool {
+	for _, t := range p.Tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// Filter returns the projects with the given status and all of the given
+// tags. An empty status matches any status.
+func Filter(list []Project, status string, tags []string) []Project {
+	var out []Project
+	for _, p := range list {
+		if status != "" && p.Status != status {
+			continue
+		}
+		match := true
+		for _, t := range tags {
+			if !p.HasTag(t) {
+				match = false
+				break
+			}
+		}
+		if match {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// applyMeta copies metadata into the project. Projects without metadata
+// or a status are active.
+func (p *Project) applyMeta(m *Meta) {
+	p.Status = StatusActive
+	if m == nil {
+		return
+	}
+	if m.Status != "" {
+		p.Status = m.Status
+	}
+	p.Purpose = m.Purpose
+	p.Tags = m.Tags
+	p.Owner = m.Owner
+	p.Collaborators = m.Collaborators
+	p.Template = m.Template
+	if !m.Created.IsZero() {
+		created := m.Created
+		p.Created = &created
+	}
+}
+
+// Scan discovers IRL projects in the configured workspace roots.
 // A folder is considered a project if it contains main-plan.md in one of:
 //   - plans/main-plan.md (current standard)
 //   - main-plan.md (legacy root level)
 //   - 01-plans/main-plan.md (legacy IRL structure)
 func Scan() ([]Project, error) {
-	baseDir := config.GetDefaultDirectory()
-	if baseDir == "" {
+	roots := config.GetWorkspaces()
+	if len(roots) == 0 {
 		return []Project{}, nil
 	}
-	return ScanDir(baseDir)
+	return ScanRoots(roots)
 }
 
 // ScanDir discovers IRL projects in the given directory.
 func ScanDir(baseDir string) ([]Project, error) {
-	var projects []Project
+	return ScanRoots([]config.Workspace{{Path: baseDir}})
+}
 
-	entries, err := os.ReadDir(baseDir)
+// ScanRoots discovers IRL projects under each root, searching up to the
+// root's max depth. Project folders aren't searched further, nor are
+// _-prefixed folders (_templates, _backups, ...); hidden folders and those
+// matching the root's ignore globs are skipped.
+// Roots are scanned concurrently; projects found in readable roots are
+// returned along with the errors of the unreadable ones. Folders that
+// haven't changed since the last scan are read from the workspace index.
+func ScanRoots(roots []config.Workspace) ([]Project, error) {
+	idx := LoadIndex()
+	found, err := idx.scan(roots)
+	idx.Save() // The index is only a cache
+	return found, err
+}
+
+// skipFolder reports whether a folder below a root is left out of the scan
+func skipFolder(root config.Workspace, dir, name string) bool {
+	// IRL internal folders
+	if name == "01-plans" || name == "02-data" || name == "03-outputs" || name == "04-logs" {
+		return true
+	}
+	if strings.HasPrefix(name, ".") {
+		return true
+	}
+	rel, err := filepath.Rel(root.Path, filepath.Join(dir, name))
 	if err != nil {
-		return nil, err
+		rel = name
 	}
-
-	for _, entry := range entries {
-		if !entry.IsDir() {
-			continue
+	for _, glob := range root.Ignore {
+		if ok, _ := filepath.Match(glob, name); ok {
+			return true
 		}
-
-		// Skip IRL internal folders
-		name := entry.Name()
-		if name == "01-plans" || name == "02-data" || name == "03-outputs" || name == "04-logs" {
-			continue
+		if ok, _ := filepath.Match(glob, filepath.ToSlash(rel)); ok {
+			return true
 		}
+	}
+	return false
+}
 
-		projectDir := filepath.Join(baseDir, name)
+// planPaths lists where a project folder may keep its plan
+func planPaths(dir string) []string {
+	return []string{
+		filepath.Join(dir, "plans", "main-plan.md"),    // current standard
+		filepath.Join(dir, "main-plan.md"),             // legacy root level
+		filepath.Join(dir, "01-plans", "main-plan.md"), // legacy IRL structure
+	}
+}
 
-		// Check for main-plan.md in multiple locations
-		planPaths := []string{
-			filepath.Join(projectDir, "plans", "main-plan.md"),
-			filepath.Join(projectDir, "main-plan.md"),
-			filepath.Join(projectDir, "01-plans", "main-plan.md"),
-		}
+// Detect reports whether dir is an IRL project, by the same plan check
+// the workspace scan uses, and loads it if so
+func Detect(dir string) (Project, bool) {
+	p, _, ok := loadProject(dir)
+	return p, ok
+}
 
-		var planInfo os.FileInfo
-		for _, planPath := range planPaths {
-			if info, err := os.Stat(planPath); err == nil {
-				planInfo = info
-				break
-			}
-		}
+// InWorkspace reports whether dir is a workspace root or inside one
+func InWorkspace(dir string) bool {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return false
+	}
+	return underAny(abs, config.GetWorkspaces())
+}
 
-		if planInfo == nil {
+// loadProject checks a folder for a plan and loads its metadata. It
+// returns the plan's path too.
+func loadProject(dir string) (Project, string, bool) {
+	for _, planPath := range planPaths(dir) {
+		info, err := os.Stat(planPath)
+		if err != nil {
 			continue
 		}
-
-		projects = append(projects, Project{
-			Name:     name,
-			Path:     projectDir,
-			Modified: planInfo.ModTime(),
-		})
+		p := Project{
+			Name:     filepath.Base(dir),
+			Path:     dir,
+			Modified: info.ModTime(),
+		}
+		meta, _ := LoadMeta(dir) // Missing or invalid metadata leaves the defaults
+		p.applyMeta(meta)
+		return p, planPath, true
 	}
-
-	// Sort by modified time, most recent first
-	sort.Slice(projects, func(i, j int) bool {
-		return projects[i].Modified.After(projects[j].Modified)
-	})
-
-	return projects, nil
+	return Project{}, "", false
 }
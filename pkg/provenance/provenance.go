@@ -0,0 +1,281 @@
+// Package provenance records the state of a project's raw data so silent
+// changes to it can be caught.
+package provenance
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/drpedapati/irl-template/pkg/projects"
+)
+
+// RawDir is the folder the manifest covers, relative to the project
+const RawDir = "02-data/raw"
+
+// ManifestFile is the manifest's path relative to the project. It lives
+// with the other committed irl state rather than in 02-data/raw, which is
+// often kept out of git.
+var ManifestFile = projects.MetaDir + "/raw-data.json"
+
+// manifestVersion changes when the manifest format does
+const manifestVersion = 1
+
+const workers = 8
+
+// placeholders are files that only keep folders in git
+var placeholders = map[string]bool{".gitkeep": true, ".keep": true, ".DS_Store": true}
+
+// Manifest records every file under 02-data/raw
+type Manifest struct {
+	Version int       `json:"version"`
+	Created time.Time `json:"created"`
+	Files   []Entry   `json:"files"` // sorted by path
+}
+
+// Entry is one raw data file
+type Entry struct {
+	Path     string    `json:"path"` // slash-separated, relative to 02-data/raw
+	Size     int64     `json:"size"`
+	SHA256   string    `json:"sha256"`
+	Modified time.Time `json:"modified"`
+}
+
+// Diff is how the raw data differs from the manifest
+type Diff struct {
+	Added     []string  `json:"added"`
+	Removed   []string  `json:"removed"`
+	Changed   []string  `json:"changed"` // content differs
+	Touched   []string  `json:"touched"` // same content, new modification time
+	Unchanged int       `json:"unchanged"`
+	Snapshot  time.Time `json:"snapshot"` // when the manifest was made
+}
+
+// Clean reports whether no raw file was added, removed or changed
+func (d *Diff) Clean() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// ManifestPath returns the manifest's location in a project
+func ManifestPath(projectDir string) string {
+	return filepath.Join(projectDir, filepath.FromSlash(ManifestFile))
+}
+
+// Load reads a project's manifest. The error satisfies os.IsNotExist when
+// the project has none.
+func Load(projectDir string) (*Manifest, error) {
+	data, err := os.ReadFile(ManifestPath(projectDir))
+	if err != nil {
+		return nil, err
+	}
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", ManifestFile, err)
+	}
+	if m.Version != manifestVersion {
+		return nil, fmt.Errorf("%s has unsupported version %d", ManifestFile, m.Version)
+	}
+	return &m, nil
+}
+
+// Save writes the manifest into the project
+func (m *Manifest) Save(projectDir string) error {
+	path := ManifestPath(projectDir)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// SameFiles reports whether two manifests record the same files with the
+// same sizes, hashes and times
+func (m *Manifest) SameFiles(other *Manifest) bool {
+	if len(m.Files) != len(other.Files) {
+		return false
+	}
+	for i, e := range m.Files {
+		o := other.Files[i]
+		if e.Path != o.Path || e.Size != o.Size || e.SHA256 != o.SHA256 || !e.Modified.Equal(o.Modified) {
+			return false
+		}
+	}
+	return true
+}
+
+// Snapshot hashes every file under the project's 02-data/raw. A missing
+// folder gives an empty manifest.
+func Snapshot(projectDir string) (*Manifest, error) {
+	files, err := scan(filepath.Join(projectDir, filepath.FromSlash(RawDir)))
+	if err != nil {
+		return nil, err
+	}
+	if err := hashAll(filepath.Join(projectDir, filepath.FromSlash(RawDir)), files); err != nil {
+		return nil, err
+	}
+	return &Manifest{Version: manifestVersion, Created: time.Now().UTC(), Files: files}, nil
+}
+
+// Verify compares the project's raw data with its manifest. With quick
+// set, files whose size and modification time match are trusted without
+// being hashed.
+func Verify(projectDir string, quick bool) (*Diff, error) {
+	m, err := Load(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	root := filepath.Join(projectDir, filepath.FromSlash(RawDir))
+	current, err := scan(root)
+	if err != nil {
+		return nil, err
+	}
+
+	recorded := make(map[string]Entry, len(m.Files))
+	for _, e := range m.Files {
+		recorded[e.Path] = e
+	}
+
+	d := &Diff{
+		Added:    []string{},
+		Removed:  []string{},
+		Changed:  []string{},
+		Touched:  []string{},
+		Snapshot: m.Created,
+	}
+	var toHash []Entry
+	seen := map[string]bool{}
+	for _, e := range current {
+		old, ok := recorded[e.Path]
+		if !ok {
+			d.Added = append(d.Added, e.Path)
+			continue
+		}
+		seen[e.Path] = true
+		if e.Size != old.Size {
+			d.Changed = append(d.Changed, e.Path)
+			continue
+		}
+		if quick && e.Modified.Equal(old.Modified) {
+			d.Unchanged++
+			continue
+		}
+		toHash = append(toHash, e)
+	}
+	for path := range recorded {
+		if !seen[path] {
+			d.Removed = append(d.Removed, path)
+		}
+	}
+
+	if err := hashAll(root, toHash); err != nil {
+		return nil, err
+	}
+	for _, e := range toHash {
+		old := recorded[e.Path]
+		switch {
+		case e.SHA256 != old.SHA256:
+			d.Changed = append(d.Changed, e.Path)
+		case !e.Modified.Equal(old.Modified):
+			d.Touched = append(d.Touched, e.Path)
+			d.Unchanged++
+		default:
+			d.Unchanged++
+		}
+	}
+
+	sort.Strings(d.Removed)
+	sort.Strings(d.Changed)
+	sort.Strings(d.Touched)
+	return d, nil
+}
+
+// scan lists the regular files under root, sorted by path, without
+// hashing them. Links to files are followed, so data kept elsewhere is
+// covered.
+func scan(root string) ([]Entry, error) {
+	var files []Entry
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if p == root && os.IsNotExist(err) {
+				return filepath.SkipAll
+			}
+			return err
+		}
+		if d.IsDir() || placeholders[d.Name()] {
+			return nil
+		}
+		info, err := os.Stat(p)
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		files = append(files, Entry{
+			Path:     filepath.ToSlash(rel),
+			Size:     info.Size(),
+			Modified: info.ModTime().UTC(),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+	return files, nil
+}
+
+// hashAll fills in the SHA-256 of each file, a few at a time
+func hashAll(root string, files []Entry) error {
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+		sem      = make(chan struct{}, workers)
+	)
+	for i := range files {
+		wg.Add(1)
+		go func(e *Entry) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			sum, err := hashFile(filepath.Join(root, filepath.FromSlash(e.Path)))
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil && firstErr == nil {
+				firstErr = fmt.Errorf("%s: %w", e.Path, err)
+			}
+			e.SHA256 = sum
+		}(&files[i])
+	}
+	wg.Wait()
+	return firstErr
+}
+
+func hashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,443 @@
+// Package render renders a project's Quarto, R Markdown and Markdown
+// outputs, skipping sources that haven't changed since they were last
+// rendered.
+package render
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/drpedapati/irl-template/pkg/agent"
+	"github.com/drpedapati/irl-template/pkg/config"
+	"github.com/drpedapati/irl-template/pkg/projects"
+	"github.com/drpedapati/irl-template/pkg/status"
+)
+
+// DefaultCommand renders with Quarto. Placeholders:
+//
+//	{input}   absolute path to the source
+//	{name}    the source's file name without its extension
+//	{dir}     absolute path to the source's folder
+//	{project} absolute path to the project directory
+//
+// The renderer runs in the source's folder.
+const DefaultCommand = "quarto render {input}"
+
+// StateFile records what was rendered, relative to the project
+var StateFile = projects.MetaDir + "/render.json"
+
+// stateVersion changes when the state format does
+const stateVersion = 1
+
+// Extensions are the source types rendered
+var Extensions = []string{".qmd", ".Rmd", ".md"}
+
+// State records the last successful render of each source
+type State struct {
+	Version int               `json:"version"`
+	Sources map[string]Record `json:"sources"` // keyed by slash-separated project path
+}
+
+// Record is one source's last render
+type Record struct {
+	SHA256    string     `json:"sha256"`  // of the source
+	Command   string     `json:"command"` // the renderer template used
+	Rendered  time.Time  `json:"rendered"`
+	Artifacts []Artifact `json:"artifacts"` // files the render wrote
+}
+
+// Artifact is a file a render wrote
+type Artifact struct {
+	Path   string `json:"path"` // slash-separated, relative to the project
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// Render statuses
+const (
+	StatusRendered  = "rendered"
+	StatusUnchanged = "unchanged"
+	StatusFailed    = "failed"
+)
+
+// Result is what happened to one source
+type Result struct {
+	Source    string        `json:"source"`
+	Status    string        `json:"status"`
+	Reason    string        `json:"reason,omitempty"` // why it was rendered
+	Artifacts []Artifact    `json:"artifacts"`
+	Duration  time.Duration `json:"-"`
+	ExitCode  int           `json:"exit_code,omitempty"`
+	Output    string        `json:"output,omitempty"` // the renderer's output when it failed
+}
+
+// MarshalJSON reports the duration in milliseconds
+func (r Result) MarshalJSON() ([]byte, error) {
+	type plain Result
+	out := struct {
+		plain
+		Duration int64 `json:"duration_ms"`
+	}{plain(r), r.Duration.Milliseconds()}
+	return json.Marshal(out)
+}
+
+// Options controls a render
+type Options struct {
+	Command string   // renderer template; the configured one or DefaultCommand when empty
+	Sources []string // project paths to consider; all sources when empty
+	Force   bool     // render even when nothing changed
+
+	// Progress, when set, is called before each source is rendered
+	Progress func(source, reason string)
+}
+
+// Command returns the configured renderer template, or DefaultCommand
+func Command() string {
+	if c := config.GetRenderer(); c != "" {
+		return c
+	}
+	return DefaultCommand
+}
+
+// StatePath returns the state file's location in a project
+func StatePath(projectDir string) string {
+	return filepath.Join(projectDir, filepath.FromSlash(StateFile))
+}
+
+// LoadState reads a project's render state. A project that was never
+// rendered has an empty state.
+func LoadState(projectDir string) (*State, error) {
+	s := &State{Version: stateVersion, Sources: map[string]Record{}}
+	data, err := os.ReadFile(StatePath(projectDir))
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", StateFile, err)
+	}
+	if s.Version != stateVersion {
+		return nil, fmt.Errorf("%s has unsupported version %d", StateFile, s.Version)
+	}
+	if s.Sources == nil {
+		s.Sources = map[string]Record{}
+	}
+	return s, nil
+}
+
+// Save writes the state into the project
+func (s *State) Save(projectDir string) error {
+	path := StatePath(projectDir)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// Sources finds the renderable files in the project's 03-outputs, as
+// sorted slash-separated project paths. Names starting with _ or . are
+// skipped, as Quarto does, along with the folders renders write
+// (*_files, _site, _freeze).
+func Sources(projectDir string) ([]string, error) {
+	root := filepath.Join(projectDir, status.OutputsDir)
+	var sources []string
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if p == root && errors.Is(err, fs.ErrNotExist) {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		name := d.Name()
+		if p != root && (strings.HasPrefix(name, "_") || strings.HasPrefix(name, ".")) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			if strings.HasSuffix(name, "_files") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		if d.Type().IsRegular() && isSourcePath(filepath.ToSlash(rel)) {
+			sources = append(sources, filepath.ToSlash(filepath.Join(status.OutputsDir, rel)))
+		}
+		return nil
+	})
+	sort.Strings(sources)
+	return sources, err
+}
+
+// isSourcePath reports whether a slash-separated path below 03-outputs is
+// one Sources would return
+func isSourcePath(rel string) bool {
+	parts := strings.Split(rel, "/")
+	for i, part := range parts {
+		if strings.HasPrefix(part, "_") || strings.HasPrefix(part, ".") {
+			return false
+		}
+		if i < len(parts)-1 && strings.HasSuffix(part, "_files") {
+			return false
+		}
+	}
+	return isSource(parts[len(parts)-1])
+}
+
+func isSource(name string) bool {
+	ext := filepath.Ext(name)
+	for _, e := range Extensions {
+		if strings.EqualFold(ext, e) {
+			return true
+		}
+	}
+	return false
+}
+
+// Render renders the project's sources that changed since their last
+// render, or whose renderer or artifacts did. The state is saved after
+// every successful render, so an interrupted run keeps its progress. A
+// renderer that fails is reported in the results, not as an error.
+func Render(projectDir string, opts Options) ([]Result, error) {
+	projectDir, err := filepath.Abs(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	tmpl := opts.Command
+	if tmpl == "" {
+		tmpl = Command()
+	}
+	if strings.TrimSpace(tmpl) == "" {
+		return nil, errors.New("empty renderer command")
+	}
+
+	state, err := LoadState(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	sources := opts.Sources
+	if len(sources) == 0 {
+		if sources, err = Sources(projectDir); err != nil {
+			return nil, err
+		}
+		// Forget sources that were deleted or renamed
+		pruned := false
+		for src := range state.Sources {
+			if _, err := os.Stat(filepath.Join(projectDir, filepath.FromSlash(src))); os.IsNotExist(err) {
+				delete(state.Sources, src)
+				pruned = true
+			}
+		}
+		if pruned {
+			if err := state.Save(projectDir); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	results := []Result{}
+	for _, src := range sources {
+		sum, err := hashFile(filepath.Join(projectDir, filepath.FromSlash(src)))
+		if err != nil {
+			return results, err
+		}
+		rec, seen := state.Sources[src]
+		reason := staleReason(projectDir, rec, seen, sum, tmpl)
+		if reason == "" && opts.Force {
+			reason = "forced"
+		}
+		if reason == "" {
+			results = append(results, Result{Source: src, Status: StatusUnchanged, Artifacts: rec.Artifacts})
+			continue
+		}
+
+		if opts.Progress != nil {
+			opts.Progress(src, reason)
+		}
+		res, err := renderOne(projectDir, src, tmpl)
+		if err != nil {
+			return results, err
+		}
+		res.Reason = reason
+		results = append(results, res)
+		if res.Status != StatusRendered {
+			continue
+		}
+		state.Sources[src] = Record{SHA256: sum, Command: tmpl, Rendered: time.Now(), Artifacts: res.Artifacts}
+		if err := state.Save(projectDir); err != nil {
+			return results, err
+		}
+	}
+	return results, nil
+}
+
+// staleReason says why a source needs rendering, or "" when its last
+// render still stands
+func staleReason(projectDir string, rec Record, seen bool, sum, tmpl string) string {
+	switch {
+	case !seen:
+		return "new"
+	case rec.SHA256 != sum:
+		return "source changed"
+	case rec.Command != tmpl:
+		return "renderer changed"
+	}
+	for _, a := range rec.Artifacts {
+		got, err := hashFile(filepath.Join(projectDir, filepath.FromSlash(a.Path)))
+		if err != nil {
+			return "artifact missing"
+		}
+		if got != a.SHA256 {
+			return "artifact changed"
+		}
+	}
+	return ""
+}
+
+// renderOne runs the renderer on one source and works out its artifacts
+// from the files in 03-outputs it wrote. Sources it wrote, such as the
+// Markdown a gfm render leaves, are sources of their own and not counted.
+func renderOne(projectDir, src, tmpl string) (Result, error) {
+	res := Result{Source: src, Artifacts: []Artifact{}}
+	input := filepath.Join(projectDir, filepath.FromSlash(src))
+	args, err := agent.BuildArgs(tmpl, map[string]string{
+		"input":   input,
+		"name":    strings.TrimSuffix(filepath.Base(input), filepath.Ext(input)),
+		"dir":     filepath.Dir(input),
+		"project": projectDir,
+	})
+	if err != nil {
+		return res, err
+	}
+
+	outputs := filepath.Join(projectDir, status.OutputsDir)
+	before, err := snapshot(outputs)
+	if err != nil {
+		return res, err
+	}
+
+	var out bytes.Buffer
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Dir = filepath.Dir(input)
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	start := time.Now()
+	runErr := cmd.Run()
+	res.Duration = time.Since(start)
+
+	var exitErr *exec.ExitError
+	switch {
+	case runErr == nil:
+	case errors.As(runErr, &exitErr):
+		res.Status = StatusFailed
+		res.ExitCode = exitErr.ExitCode()
+		res.Output = out.String()
+		return res, nil
+	default:
+		return res, fmt.Errorf("failed to start renderer %s: %w", args[0], runErr)
+	}
+
+	after, err := snapshot(outputs)
+	if err != nil {
+		return res, err
+	}
+	for _, p := range sortedPaths(after) {
+		if prev, ok := before[p]; ok && prev == after[p] {
+			continue
+		}
+		if rel, err := filepath.Rel(outputs, p); err == nil && isSourcePath(filepath.ToSlash(rel)) {
+			continue
+		}
+		sum, err := hashFile(p)
+		if err != nil {
+			return res, err
+		}
+		rel, err := filepath.Rel(projectDir, p)
+		if err != nil {
+			return res, err
+		}
+		res.Artifacts = append(res.Artifacts, Artifact{Path: filepath.ToSlash(rel), Size: after[p].size, SHA256: sum})
+	}
+	res.Status = StatusRendered
+	return res, nil
+}
+
+// fileStamp is enough to tell whether a render rewrote a file
+type fileStamp struct {
+	size int64
+	mod  time.Time
+}
+
+// snapshot stamps every file below dir, skipping hidden folders
+func snapshot(dir string) (map[string]fileStamp, error) {
+	files := map[string]fileStamp{}
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if p == dir && errors.Is(err, fs.ErrNotExist) {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if d.IsDir() {
+			if p != dir && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		files[p] = fileStamp{size: info.Size(), mod: info.ModTime()}
+		return nil
+	})
+	return files, err
+}
+
+func sortedPaths(m map[string]fileStamp) []string {
+	paths := make([]string, 0, len(m))
+	for p := range m {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+func hashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,136 @@
+package render
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// fakeRenderer puts a script on PATH that renders to <name>.html and
+// <name>_files/. A .qmd also gets a Markdown summary, <name>-summary.md,
+// as a second gfm format would write. A source containing "fail" makes
+// it exit 2.
+func fakeRenderer(t *testing.T) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake renderer is a shell script")
+	}
+	bin := t.TempDir()
+	script := `#!/bin/sh
+grep -q fail "$1" && { echo "render failed" >&2; exit 2; }
+mkdir -p "$2_files"
+echo "<html>$(cat "$1")</html>" > "$2.html"
+echo "fig" > "$2_files/fig.png"
+case "$1" in *.qmd) cat "$1" > "$2-summary.md" ;; esac
+`
+	if err := os.WriteFile(filepath.Join(bin, "fakerender"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return "fakerender {input} {name}"
+}
+
+func writeSource(t *testing.T, projectDir, rel, body string) {
+	t.Helper()
+	p := filepath.Join(projectDir, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(p, []byte(body), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func artifactPaths(as []Artifact) string {
+	var paths []string
+	for _, a := range as {
+		paths = append(paths, a.Path)
+	}
+	return strings.Join(paths, " ")
+}
+
+func TestRenderSkipsSourcesWhenAttributingArtifacts(t *testing.T) {
+	cmd := fakeRenderer(t)
+	dir := t.TempDir()
+	writeSource(t, dir, "03-outputs/report.qmd", "results")
+
+	results, err := Render(dir, Options{Command: cmd})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].Status != StatusRendered || results[0].Reason != "new" {
+		t.Fatalf("first render: %+v", results)
+	}
+	// report-summary.md is a source of its own, not report.qmd's artifact
+	if got, want := artifactPaths(results[0].Artifacts), "03-outputs/report.html 03-outputs/report_files/fig.png"; got != want {
+		t.Errorf("artifacts = %s, want %s", got, want)
+	}
+
+	// The summary the render wrote is picked up as a source; rendering it
+	// again leaves report.qmd's record alone
+	results, err = Render(dir, Options{Command: cmd})
+	if err != nil {
+		t.Fatal(err)
+	}
+	byName := map[string]Result{}
+	for _, r := range results {
+		byName[r.Source] = r
+	}
+	if r := byName["03-outputs/report.qmd"]; r.Status != StatusUnchanged {
+		t.Errorf("report.qmd on the second render: %s (%s)", r.Status, r.Reason)
+	}
+	if r := byName["03-outputs/report-summary.md"]; r.Status != StatusRendered || r.Reason != "new" {
+		t.Errorf("report-summary.md on the second render: %s (%s)", r.Status, r.Reason)
+	}
+
+	// A third run has nothing to do
+	results, err = Render(dir, Options{Command: cmd})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range results {
+		if r.Status != StatusUnchanged {
+			t.Errorf("third render: %s was %s (%s)", r.Source, r.Status, r.Reason)
+		}
+	}
+}
+
+func TestRenderReportsStaleArtifactsAndFailures(t *testing.T) {
+	cmd := fakeRenderer(t)
+	dir := t.TempDir()
+	writeSource(t, dir, "03-outputs/report.qmd", "results")
+	if _, err := Render(dir, Options{Command: cmd, Sources: []string{"03-outputs/report.qmd"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	os.WriteFile(filepath.Join(dir, "03-outputs", "report.html"), []byte("hand edited"), 0644)
+	results, err := Render(dir, Options{Command: cmd, Sources: []string{"03-outputs/report.qmd"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results[0].Status != StatusRendered || results[0].Reason != "artifact changed" {
+		t.Errorf("after editing the HTML: %s (%s)", results[0].Status, results[0].Reason)
+	}
+	before, err := LoadState(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	writeSource(t, dir, "03-outputs/report.qmd", "this will fail")
+	results, err = Render(dir, Options{Command: cmd, Sources: []string{"03-outputs/report.qmd"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := results[0]; r.Status != StatusFailed || r.ExitCode != 2 || !strings.Contains(r.Output, "render failed") {
+		t.Errorf("failing render: %+v", r)
+	}
+	state, err := LoadState(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := state.Sources["03-outputs/report.qmd"].SHA256, before.Sources["03-outputs/report.qmd"].SHA256; got != want {
+		t.Errorf("the failed render replaced the last good record: %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,390 @@
+// Package search finds text in project plans, logs and metadata.
+package search
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/drpedapati/irl-template/pkg/loop"
+	"github.com/drpedapati/irl-template/pkg/plan"
+	"github.com/drpedapati/irl-template/pkg/projects"
+)
+
+// Kinds of searched files
+const (
+	KindPlan      = "plan"
+	KindLog       = "log"
+	KindDecisions = "decisions"
+	KindMeta      = "meta"
+)
+
+// Kinds lists the searched file kinds
+var Kinds = []string{KindPlan, KindLog, KindDecisions, KindMeta}
+
+// kindWeight ranks matches by where they're found: metadata describes the
+// whole project, the plan its intent, logs only what happened
+var kindWeight = map[string]float64{
+	KindMeta:      3,
+	KindPlan:      2,
+	KindDecisions: 1.5,
+	KindLog:       1,
+}
+
+const (
+	snippetWidth = 160     // longest snippet, in runes
+	maxFileSize  = 4 << 20 // larger files are skipped
+	workers      = 8
+)
+
+// Options controls a search
+type Options struct {
+	Kinds      []string // file kinds to search; all when empty
+	MaxMatches int      // matches kept per project; 5 when zero
+}
+
+// Match is one matching line
+type Match struct {
+	File   string   `json:"file"` // relative to the project
+	Kind   string   `json:"kind"`
+	Line   int      `json:"line"`
+	Text   string   `json:"text"`   // the line, trimmed to a snippet
+	Ranges [][2]int `json:"ranges"` // byte ranges of Text that matched
+	Score  float64  `json:"score"`
+}
+
+// Result is a project with matches, best first
+type Result struct {
+	Project string  `json:"project"`
+	Path    string  `json:"path"`
+	Root    string  `json:"root,omitempty"`
+	Score   float64 `json:"score"`
+	Total   int     `json:"total"` // matching lines, including those not kept
+	Matches []Match `json:"matches"`
+}
+
+// Terms splits a query into lowercase words, keeping "quoted phrases" whole
+func Terms(query string) []string {
+	var terms []string
+	for i, part := range strings.Split(query, `"`) {
+		if i%2 == 1 {
+			if p := strings.TrimSpace(part); p != "" {
+				terms = append(terms, strings.ToLower(p))
+			}
+			continue
+		}
+		for _, w := range strings.Fields(part) {
+			terms = append(terms, strings.ToLower(w))
+		}
+	}
+	return terms
+}
+
+// Search looks for query in each project's plan, logs, decision logs and
+// metadata. Terms match case-insensitively at the start of words.
+// Projects match when every term appears in their name or files; they're
+// ranked by how often and where the terms appear.
+func Search(list []projects.Project, query string, opts Options) ([]Result, error) {
+	terms := Terms(query)
+	if len(terms) == 0 {
+		return nil, fmt.Errorf("empty search query")
+	}
+	if opts.MaxMatches <= 0 {
+		opts.MaxMatches = 5
+	}
+	for _, k := range opts.Kinds {
+		if _, ok := kindWeight[k]; !ok {
+			return nil, fmt.Errorf("unknown file kind %q (use %s)", k, strings.Join(Kinds, ", "))
+		}
+	}
+
+	// Terms match at the start of a word, so "erp" finds "ERPs" but not "PowerPoint"
+	patterns := make([]string, len(terms))
+	termRes := make([]*regexp.Regexp, len(terms))
+	for i, t := range terms {
+		patterns[i] = wordStart(t)
+		termRes[i] = regexp.MustCompile(`(?i)` + patterns[i])
+	}
+	re := regexp.MustCompile(`(?i)` + strings.Join(patterns, "|"))
+	var phrase *regexp.Regexp
+	if len(terms) > 1 {
+		phrase = regexp.MustCompile(`(?i)` + wordStart(strings.Join(terms, " ")))
+	}
+
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		results []Result
+		sem     = make(chan struct{}, workers)
+	)
+	for _, p := range list {
+		wg.Add(1)
+		go func(p projects.Project) {
+			defer wg.Done()
+			sem <- struct{}{}
+			r, ok := searchProject(p, termRes, phrase, re, opts)
+			<-sem
+			if ok {
+				mu.Lock()
+				results = append(results, r)
+				mu.Unlock()
+			}
+		}(p)
+	}
+	wg.Wait()
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Project < results[j].Project
+	})
+	return results, nil
+}
+
+// wordStart returns a pattern matching text at the start of a word, with
+// the text itself in a group. \b only knows ASCII word characters, so the
+// boundary is spelled out to let queries like "étude" match.
+func wordStart(text string) string {
+	pattern := `(` + strings.Join(strings.Fields(regexp.QuoteMeta(text)), `\s+`) + `)`
+	if r, _ := utf8.DecodeRuneInString(text); r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) {
+		pattern = `(?:^|[^\p{L}\p{N}_])` + pattern
+	}
+	return pattern
+}
+
+// matchRanges returns where re's terms match in line, leaving out the
+// boundary character each match may start with
+func matchRanges(re *regexp.Regexp, line string) [][]int {
+	var locs [][]int
+	for _, m := range re.FindAllStringSubmatchIndex(line, -1) {
+		for g := 2; g+1 < len(m); g += 2 {
+			if m[g] >= 0 {
+				locs = append(locs, []int{m[g], m[g+1]})
+				break
+			}
+		}
+	}
+	return locs
+}
+
+func searchProject(p projects.Project, terms []*regexp.Regexp, phrase, re *regexp.Regexp, opts Options) (Result, bool) {
+	res := Result{Project: p.Name, Path: p.Path, Root: p.Root}
+	seen := make([]bool, len(terms))
+	for i, t := range terms {
+		if t.MatchString(p.Name) {
+			seen[i] = true
+			res.Score += 5
+		}
+	}
+
+	var matches []Match
+	for _, f := range files(p.Path, opts.Kinds) {
+		lines := f.lines
+		if lines == nil {
+			lines = readLines(f.path)
+		}
+		rel, _ := filepath.Rel(p.Path, f.path)
+		for i, line := range lines {
+			locs := matchRanges(re, line)
+			if len(locs) == 0 {
+				continue
+			}
+			score := 0.0
+			for i, t := range terms {
+				if t.MatchString(line) {
+					seen[i] = true
+					score++
+				}
+			}
+			if phrase != nil && phrase.MatchString(line) {
+				score += 3
+			}
+			score *= kindWeight[f.kind]
+
+			text, ranges := snippet(line, locs)
+			matches = append(matches, Match{
+				File:   filepath.ToSlash(rel),
+				Kind:   f.kind,
+				Line:   i + 1,
+				Text:   text,
+				Ranges: ranges,
+				Score:  score,
+			})
+		}
+	}
+
+	for _, ok := range seen {
+		if !ok {
+			return res, false
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].Score > matches[j].Score })
+	res.Total = len(matches)
+	for i, m := range matches {
+		if i < 10 {
+			res.Score += m.Score // A few strong matches count more than many weak ones
+		}
+	}
+	if len(matches) > opts.MaxMatches {
+		matches = matches[:opts.MaxMatches]
+	}
+	res.Matches = matches
+	if res.Matches == nil {
+		res.Matches = []Match{}
+	}
+	return res, true
+}
+
+type file struct {
+	path  string
+	kind  string
+	lines []string // set for generated content such as metadata
+}
+
+// files lists a project's searchable files of the given kinds
+func files(projectDir string, kinds []string) []file {
+	want := func(k string) bool {
+		if len(kinds) == 0 {
+			return true
+		}
+		for _, v := range kinds {
+			if v == k {
+				return true
+			}
+		}
+		return false
+	}
+
+	var list []file
+	if planPath, err := plan.Find(projectDir); err == nil {
+		if want(KindPlan) {
+			list = append(list, file{path: planPath, kind: KindPlan})
+		}
+		if want(KindLog) {
+			csvPath, activityPath := loop.LogPaths(planPath)
+			activityCSV := strings.TrimSuffix(activityPath, ".md") + ".csv"
+			for _, path := range []string{activityPath, activityCSV, csvPath} {
+				list = append(list, file{path: path, kind: KindLog})
+			}
+		}
+	}
+	if want(KindDecisions) {
+		for _, pattern := range []string{"04-logs/*decision*", "plans/*decision*"} {
+			matches, _ := filepath.Glob(filepath.Join(projectDir, filepath.FromSlash(pattern)))
+			for _, path := range matches {
+				list = append(list, file{path: path, kind: KindDecisions})
+			}
+		}
+	}
+	if want(KindMeta) {
+		if meta, err := projects.LoadMeta(projectDir); err == nil {
+			list = append(list, file{path: projects.MetaPath(projectDir), kind: KindMeta, lines: metaLines(meta)})
+		}
+	}
+	return list
+}
+
+// metaLines renders the descriptive metadata fields as searchable lines
+func metaLines(m *projects.Meta) []string {
+	var lines []string
+	add := func(key, value string) {
+		if value != "" {
+			lines = append(lines, key+": "+value)
+		}
+	}
+	add("purpose", m.Purpose)
+	add("status", m.Status)
+	add("tags", strings.Join(m.Tags, ", "))
+	add("owner", m.Owner)
+	add("collaborators", strings.Join(m.Collaborators, ", "))
+	add("template", m.Template)
+	return lines
+}
+
+func readLines(path string) []string {
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() || info.Size() > maxFileSize {
+		return nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), maxFileSize)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	return lines
+}
+
+// snippet trims a matching line to snippetWidth runes around its first
+// match and returns the match ranges within it
+func snippet(line string, locs [][]int) (string, [][2]int) {
+	start, end := 0, len(line)
+	// Skip leading whitespace
+	for start < locs[0][0] && (line[start] == ' ' || line[start] == '\t') {
+		start++
+	}
+	prefix, suffix := "", ""
+	if utf8.RuneCountInString(line[start:]) > snippetWidth {
+		// Keep some context before the first match
+		from := locs[0][0]
+		for n := 0; n < snippetWidth/4 && from > start; n++ {
+			_, size := utf8.DecodeLastRuneInString(line[start:from])
+			from -= size
+		}
+		if from > start {
+			start, prefix = from, "…"
+		}
+		end = start
+		for n := 0; n < snippetWidth && end < len(line); n++ {
+			_, size := utf8.DecodeRuneInString(line[end:])
+			end += size
+		}
+		if end < len(line) {
+			suffix = "…"
+		}
+	}
+
+	text := prefix + line[start:end] + suffix
+	var ranges [][2]int
+	for _, loc := range locs {
+		if loc[0] < start || loc[1] > end {
+			continue
+		}
+		ranges = append(ranges, [2]int{loc[0] - start + len(prefix), loc[1] - start + len(prefix)})
+	}
+	if ranges == nil {
+		ranges = [][2]int{}
+	}
+	return text, ranges
+}
+
+// Highlight wraps the matched ranges of a snippet with style
+func Highlight(m Match, style func(string) string) string {
+	var b strings.Builder
+	last := 0
+	for _, r := range m.Ranges {
+		if r[0] < last {
+			continue
+		}
+		b.WriteString(m.Text[last:r[0]])
+		b.WriteString(style(m.Text[r[0]:r[1]]))
+		last = r[1]
+	}
+	b.WriteString(m.Text[last:])
+	return b.String()
+}
@@ -0,0 +1,39 @@
+package search
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func TestWordStart(t *testing.T) {
+	for _, tc := range []struct {
+		query, line string
+		want        [][]int
+	}{
+		{"filter", "Bandpass filter, then refilter", [][]int{{9, 15}}},
+		{"étude", "Une étude pilote", [][]int{{4, 10}}},
+		{"étude", "Étude pilote", [][]int{{0, 6}}},
+		{"étude", "préétude", nil},
+		{"eeg", "EEG_eeg (eeg)", [][]int{{0, 3}, {9, 12}}},
+		{"mixed models", "use mixed\tmodels", [][]int{{4, 16}}},
+		{"1-40 hz", "a 1-40 Hz band", [][]int{{2, 9}}},
+		{".csv", "data.csv", [][]int{{4, 8}}}, // no boundary before punctuation
+	} {
+		t.Run(tc.query+"/"+tc.line, func(t *testing.T) {
+			re := regexp.MustCompile(`(?i)` + wordStart(tc.query))
+			if got := matchRanges(re, tc.line); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("ranges = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestMatchRangesPicksTheMatchingTerm(t *testing.T) {
+	re := regexp.MustCompile(`(?i)` + wordStart("ica") + "|" + wordStart("blink"))
+	got := matchRanges(re, "Blinks removed by ICA")
+	want := [][]int{{0, 5}, {18, 21}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ranges = %v, want %v", got, want)
+	}
+}
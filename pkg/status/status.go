@@ -0,0 +1,251 @@
+// Package status summarizes a project's state at a glance.
+package status
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/drpedapati/irl-template/pkg/gitutil"
+	"github.com/drpedapati/irl-template/pkg/loop"
+	"github.com/drpedapati/irl-template/pkg/plan"
+	"github.com/drpedapati/irl-template/pkg/projects"
+)
+
+// Project folders the report looks at
+const (
+	DataDir    = "02-data"
+	OutputsDir = "03-outputs"
+)
+
+// Whether the plan has been edited since irl last wrote it from its template
+const (
+	PlanAsWritten    = "as-written" // the plan is as irl init, adopt or upgrade-plan left it
+	PlanChanged      = "edited"     // someone has edited the plan since
+	PlanStateUnknown = "unknown"    // there's no record of what irl wrote to compare with
+)
+
+const workers = 8
+
+// Report summarizes one project
+type Report struct {
+	Project    string    `json:"project"`
+	Path       string    `json:"path"`
+	Git        Git       `json:"git"`
+	LastLoop   *Loop     `json:"last_loop,omitempty"`
+	LoopActive bool      `json:"loop_in_progress"`
+	PlanEdited time.Time `json:"plan_edited"`
+	DaysIdle   int       `json:"days_since_plan_edit"`
+	NewOutputs []string  `json:"new_outputs"` // files in 03-outputs changed since the last commit
+	DataBytes  int64     `json:"data_bytes"`  // size of 02-data
+	Template   string    `json:"template,omitempty"`
+	PlanState  string    `json:"plan_state,omitempty"` // as-written, edited or unknown
+	Err        string    `json:"error,omitempty"`
+}
+
+// Git is the state of a project's repository
+type Git struct {
+	Repo     bool   `json:"repo"`
+	Branch   string `json:"branch,omitempty"`
+	Clean    bool   `json:"clean"`
+	Changes  int    `json:"changes"`            // uncommitted changes, including untracked files
+	Upstream string `json:"upstream,omitempty"` // empty when the branch tracks nothing
+	Ahead    int    `json:"ahead"`
+	Behind   int    `json:"behind"`
+}
+
+// Loop is the last iteration recorded in the plan log
+type Loop struct {
+	Date    string `json:"date"`
+	Summary string `json:"summary"`
+	Hash    string `json:"git_hash"`
+}
+
+// Get builds the report for the project in dir
+func Get(dir string) (*Report, error) {
+	planPath, err := plan.Find(dir)
+	if err != nil {
+		return nil, err
+	}
+	r := &Report{Project: filepath.Base(dir), Path: dir, NewOutputs: []string{}}
+
+	if info, err := os.Stat(planPath); err == nil {
+		r.PlanEdited = info.ModTime()
+		r.DaysIdle = int(time.Since(r.PlanEdited).Hours() / 24)
+	}
+
+	csvPath, _ := loop.LogPaths(planPath)
+	r.LastLoop = lastLoop(csvPath)
+
+	if gitutil.IsRepo(dir) {
+		r.Git = gitState(dir)
+		r.NewOutputs = newOutputs(dir)
+		if cur, err := loop.Current(dir); err == nil && cur != nil {
+			r.LoopActive = true
+		}
+	}
+
+	r.DataBytes = dirSize(filepath.Join(dir, DataDir))
+
+	if meta, err := projects.LoadMeta(dir); err == nil && meta.Template != "" {
+		r.Template = meta.Template
+		r.PlanState = planState(dir, planPath)
+	}
+	return r, nil
+}
+
+// All builds reports for the projects concurrently, keeping their order.
+// Projects that can't be read get a report with Err set.
+func All(list []projects.Project) []*Report {
+	reports := make([]*Report, len(list))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, workers)
+	for i, p := range list {
+		wg.Add(1)
+		go func(i int, p projects.Project) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			r, err := Get(p.Path)
+			if err != nil {
+				r = &Report{Project: p.Name, Path: p.Path, NewOutputs: []string{}, Err: err.Error()}
+			}
+			r.Project = p.Name
+			reports[i] = r
+		}(i, p)
+	}
+	wg.Wait()
+	return reports
+}
+
+func gitState(dir string) Git {
+	g := Git{Repo: true}
+	g.Branch, _ = gitutil.Run(dir, "symbolic-ref", "--short", "-q", "HEAD")
+	if lines, err := gitutil.Status(dir); err == nil {
+		g.Changes = len(lines)
+		g.Clean = len(lines) == 0
+	}
+	if up, err := gitutil.Run(dir, "rev-parse", "--abbrev-ref", "--symbolic-full-name", "@{upstream}"); err == nil {
+		g.Upstream = up
+		if out, err := gitutil.Run(dir, "rev-list", "--left-right", "--count", "HEAD...@{upstream}"); err == nil {
+			if f := strings.Fields(out); len(f) == 2 {
+				g.Ahead, _ = strconv.Atoi(f[0])
+				g.Behind, _ = strconv.Atoi(f[1])
+			}
+		}
+	}
+	return g
+}
+
+// newOutputs lists the files in the outputs folder that are new or
+// modified since the last commit
+func newOutputs(dir string) []string {
+	files := []string{}
+	changes, err := gitutil.Changes(dir, OutputsDir)
+	if err != nil {
+		return files
+	}
+	for _, c := range changes {
+		if c.Code == " D" || c.Code == "D " {
+			continue
+		}
+		files = append(files, c.Path) // renamed: the new name
+	}
+	return files
+}
+
+// lastLoop returns the last row of a plan's CSV log
+func lastLoop(csvPath string) *Loop {
+	f, err := os.Open(csvPath)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	r.FieldsPerRecord = -1
+	var last []string
+	for {
+		rec, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil
+		}
+		if len(rec) >= 2 && rec[0] != "date" {
+			last = rec
+		}
+	}
+	if last == nil {
+		return nil
+	}
+	l := &Loop{Date: last[0], Summary: last[1]}
+	if len(last) > 2 {
+		l.Hash = last[2]
+	}
+	return l
+}
+
+// planState reports whether the plan is still as irl last wrote it. The
+// plan's recorded hash covers the profile and tailoring added on top of
+// the template, so a fresh plan isn't counted as edited; projects from
+// before the hash was recorded are compared with the rendered template.
+func planState(dir, planPath string) string {
+	data, err := os.ReadFile(planPath)
+	if err != nil {
+		return PlanStateUnknown
+	}
+	if meta, err := projects.LoadMeta(dir); err == nil && meta.PlanHash != "" {
+		if projects.HashPlan(string(data)) == meta.PlanHash {
+			return PlanAsWritten
+		}
+		return PlanChanged
+	}
+	base, err := projects.ReadBase(dir)
+	if err != nil {
+		return PlanStateUnknown
+	}
+	if strings.TrimSpace(string(data)) == strings.TrimSpace(base) {
+		return PlanAsWritten
+	}
+	return PlanChanged
+}
+
+// dirSize returns the total size of the regular files under dir
+func dirSize(dir string) int64 {
+	var total int64
+	filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.Type().IsRegular() {
+			if info, err := d.Info(); err == nil {
+				total += info.Size()
+			}
+		}
+		return nil
+	})
+	return total
+}
+
+// FormatSize renders a byte count as e.g. "1.2 GB"
+func FormatSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
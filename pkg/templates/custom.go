@@ -0,0 +1,244 @@
+package templates
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/drpedapati/irl-template/pkg/config"
+)
+
+const (
+	// CustomDirName is the workspace folder holding custom templates
+	CustomDirName = "_templates"
+	// PlanFile is the plan inside a custom template directory
+	PlanFile = "main-plan.md"
+	// IgnoreFile lists glob patterns of template files not to copy
+	IgnoreFile = ".irlignore"
+	// RenderSuffix marks files rendered with template variables; the
+	// suffix is stripped from the copied name
+	RenderSuffix = ".tmpl"
+)
+
+// CustomDir returns the workspace's custom template folder, or "" if no
+// workspace is configured
+func CustomDir() string {
+	baseDir := config.GetDefaultDirectory()
+	if baseDir == "" {
+		return ""
+	}
+	return filepath.Join(baseDir, CustomDirName)
+}
+
+// GetCustom loads a custom template from _templates/<name>/, resolving
+// any inheritance
+func GetCustom(name string) (Template, error) {
+	t, err := readCustom(name)
+	if err != nil {
+		return Template{}, err
+	}
+	return Resolve(t)
+}
+
+// readCustom loads a custom template's own content, unresolved
+func readCustom(name string) (Template, error) {
+	root := CustomDir()
+	if root == "" || name == "" || strings.ContainsAny(name, `/\`) {
+		return Template{}, fmt.Errorf("custom template '%s' not found", name)
+	}
+	dir := filepath.Join(root, name)
+	content, err := os.ReadFile(filepath.Join(dir, PlanFile))
+	if err != nil {
+		return Template{}, fmt.Errorf("custom template '%s' not found", name)
+	}
+	return Template{
+		Name:        name,
+		Description: customDescription(string(content)),
+		Content:     string(content),
+		Dir:         dir,
+		Source:      "custom",
+	}, nil
+}
+
+// ListCustom returns the workspace's custom templates sorted by name
+func ListCustom() []Template {
+	root := CustomDir()
+	if root == "" {
+		return nil
+	}
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil
+	}
+
+	var list []Template
+	for _, e := range entries {
+		if !e.IsDir() || strings.HasPrefix(e.Name(), ".") || strings.HasPrefix(e.Name(), "_") {
+			continue
+		}
+		if t, err := GetCustom(e.Name()); err == nil {
+			list = append(list, t)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+func customDescription(content string) string {
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "# ") {
+			return strings.TrimPrefix(line, "# ")
+		}
+	}
+	return "Custom template"
+}
+
+// File is one entry of a directory template, ready to write
+type File struct {
+	Path    string // slash-separated, relative to the project
+	Content []byte
+	Mode    fs.FileMode
+	IsDir   bool
+}
+
+// Files returns everything a directory template adds besides the plan:
+// folders, starter scripts, config files. Files ending in .tmpl are
+// rendered with values and lose the suffix; paths matching .irlignore are
+// skipped. Single-file templates have no extra files.
+func (t Template) Files(values map[string]string) ([]File, error) {
+	if t.Dir == "" {
+		return nil, nil
+	}
+	ignore := readIgnore(filepath.Join(t.Dir, IgnoreFile))
+
+	var files []File
+	err := filepath.WalkDir(t.Dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(t.Dir, p)
+		if err != nil || rel == "." {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		if skipTemplatePath(rel, d.IsDir(), ignore) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			files = append(files, File{Path: rel, Mode: info.Mode().Perm(), IsDir: true})
+			return nil
+		}
+		if !info.Mode().IsRegular() {
+			return nil // symlinks and devices aren't copied
+		}
+
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		if strings.HasSuffix(rel, RenderSuffix) {
+			out, err := Render(string(data), values)
+			if err != nil {
+				return fmt.Errorf("%s: %w", rel, err)
+			}
+			data = []byte(out)
+			rel = strings.TrimSuffix(rel, RenderSuffix)
+		}
+		files = append(files, File{Path: rel, Content: data, Mode: info.Mode().Perm()})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("template %s: %w", t.Name, err)
+	}
+	return files, nil
+}
+
+// WriteFiles writes template files into projectPath and returns the paths
+// written. Existing files are kept unless overwrite is set.
+func WriteFiles(projectPath string, files []File, overwrite bool) ([]string, error) {
+	var written []string
+	for _, f := range files {
+		dest := filepath.Join(projectPath, filepath.FromSlash(f.Path))
+		if f.IsDir {
+			if err := os.MkdirAll(dest, 0755); err != nil {
+				return written, fmt.Errorf("failed to create %s: %w", f.Path, err)
+			}
+			continue
+		}
+		if !overwrite {
+			if _, err := os.Lstat(dest); err == nil {
+				continue
+			}
+		}
+		if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+			return written, fmt.Errorf("failed to create %s: %w", path.Dir(f.Path), err)
+		}
+		if err := os.WriteFile(dest, f.Content, f.Mode); err != nil {
+			return written, fmt.Errorf("failed to write %s: %w", f.Path, err)
+		}
+		written = append(written, f.Path)
+	}
+	return written, nil
+}
+
+// skipTemplatePath reports whether a template path stays out of projects:
+// the plan (written separately), template metadata, VCS data, and
+// anything matched by .irlignore
+func skipTemplatePath(rel string, isDir bool, ignore []string) bool {
+	switch rel {
+	case PlanFile, IgnoreFile:
+		return !isDir
+	}
+	base := path.Base(rel)
+	if base == ".git" || base == ".DS_Store" {
+		return true
+	}
+	for _, pattern := range ignore {
+		dirOnly := strings.HasSuffix(pattern, "/")
+		pattern = strings.TrimSuffix(pattern, "/")
+		if dirOnly && !isDir {
+			continue
+		}
+		// Patterns with a slash match the full path, others any name
+		target := base
+		if strings.Contains(pattern, "/") {
+			target = rel
+			pattern = strings.TrimPrefix(pattern, "/")
+		}
+		if ok, _ := path.Match(pattern, target); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// readIgnore reads glob patterns from an .irlignore file, one per line
+func readIgnore(p string) []string {
+	data, err := os.ReadFile(p)
+	if err != nil {
+		return nil
+	}
+	var patterns []string
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		patterns = append(patterns, line)
+	}
+	return patterns
+}
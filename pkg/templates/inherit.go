@@ -0,0 +1,261 @@
+package templates
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/drpedapati/irl-template/pkg/plan"
+)
+
+const (
+	// KeyExtends names the parent template in front matter
+	KeyExtends = "extends"
+	// KeyBlocks lists comma-separated blocks to merge in from _templates/_blocks/
+	KeyBlocks = "blocks"
+	// BlocksDirName is the block library folder inside _templates/
+	BlocksDirName = "_blocks"
+	// appendMarker in a section body appends to the parent section instead
+	// of replacing it
+	appendMarker = "irl:append"
+)
+
+// Resolve computes a template's effective content. A template can declare
+// in its front matter:
+//
+//	extends: irl-basic
+//	blocks: pdf-skills, logging-policy
+//
+// Sections are matched to the parent's by heading title. A section with a
+// body replaces the parent's body, or is added to it when the body contains
+// <!-- irl:append -->; sections the parent lacks are added. Blocks are plan
+// fragments in _templates/_blocks/<name>.md, merged the same way but always
+// appended. Templates without either key are returned unchanged.
+func Resolve(t Template) (Template, error) {
+	content, err := resolve(t, nil)
+	if err != nil {
+		return Template{}, err
+	}
+	t.Content = content
+	return t, nil
+}
+
+// Inherits reports whether template content uses extends or blocks
+func Inherits(content string) bool {
+	p := plan.Parse(content)
+	if p.FrontMatter == nil {
+		return false
+	}
+	_, extends := p.FrontMatter.Get(KeyExtends)
+	_, blocks := p.FrontMatter.Get(KeyBlocks)
+	return extends || blocks
+}
+
+func resolve(t Template, chain []string) (string, error) {
+	p := plan.Parse(t.Content)
+	if p.FrontMatter == nil {
+		return t.Content, nil
+	}
+	extends, hasExtends := p.FrontMatter.Get(KeyExtends)
+	blocks, hasBlocks := p.FrontMatter.Get(KeyBlocks)
+	if !hasExtends && !hasBlocks {
+		return t.Content, nil
+	}
+
+	for _, name := range chain {
+		if name == t.Name {
+			return "", fmt.Errorf("template inheritance cycle: %s → %s", strings.Join(chain, " → "), t.Name)
+		}
+	}
+	chain = append(chain, t.Name)
+
+	p.FrontMatter.Delete(KeyExtends)
+	p.FrontMatter.Delete(KeyBlocks)
+
+	result := p
+	if extends = strings.TrimSpace(extends); extends != "" {
+		parent, err := getRaw(extends)
+		if err != nil {
+			return "", fmt.Errorf("%s extends %s: %w", t.Name, extends, err)
+		}
+		parentContent, err := resolve(parent, chain)
+		if err != nil {
+			return "", err
+		}
+		result = plan.Parse(parentContent)
+		merge(result, p, false)
+	}
+
+	for _, name := range strings.Split(blocks, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		block, err := readBlock(name)
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", t.Name, err)
+		}
+		merge(result, plan.Parse(block), true)
+	}
+
+	if result.FrontMatter != nil && len(result.FrontMatter.Fields) == 0 {
+		result.FrontMatter = nil
+		for len(result.Preamble) > 0 && result.Preamble[0].Kind == plan.NodeBlank {
+			result.Preamble = result.Preamble[1:]
+		}
+	}
+	return result.String(), nil
+}
+
+// getRaw loads a template without resolving it
+func getRaw(name string) (Template, error) {
+	if t, err := readCustom(name); err == nil {
+		return t, nil
+	}
+	return getStandard(name)
+}
+
+// readBlock loads _templates/_blocks/<name>.md
+func readBlock(name string) (string, error) {
+	root := CustomDir()
+	if root == "" || strings.ContainsAny(name, `/\`) {
+		return "", fmt.Errorf("block '%s' not found", name)
+	}
+	data, err := os.ReadFile(filepath.Join(root, BlocksDirName, name+".md"))
+	if err != nil {
+		return "", fmt.Errorf("block '%s' not found", name)
+	}
+	return string(data), nil
+}
+
+// merge applies src's front matter, preamble and sections onto dst
+func merge(dst, src *plan.Plan, appendAll bool) {
+	if src.FrontMatter != nil {
+		if dst.FrontMatter == nil {
+			dst.FrontMatter = plan.NewFrontMatter()
+			dst.Preamble = append([]*plan.Node{{Kind: plan.NodeBlank, Raw: "\n"}}, dst.Preamble...)
+		}
+		for _, f := range src.FrontMatter.Fields {
+			dst.FrontMatter.Set(f.Key, f.Value)
+		}
+	}
+
+	if !appendAll && !isBlank(src.Preamble) {
+		dst.Preamble = src.Preamble
+	}
+
+	for _, s := range src.Sections {
+		// A child's title heading renames the parent's instead of adding
+		// a second document title
+		if s.Level() == 1 && dst.SectionByTitle(s.Title()) == nil && len(dst.Sections) > 0 && dst.Sections[0].Level() == 1 {
+			target := dst.Sections[0]
+			target.Heading = s.Heading
+			mergeSection(dst, target, s, appendAll)
+			continue
+		}
+		mergeInto(dst, &dst.Sections, s, appendAll)
+	}
+}
+
+// mergeInto merges s into the matching section of dst, or adds it to
+// siblings when dst has no section with that title
+func mergeInto(dst *plan.Plan, siblings *[]*plan.Section, s *plan.Section, appendAll bool) {
+	target := dst.SectionByTitle(s.Title())
+	if target == nil {
+		ensureTrailingBlank(*siblings)
+		*siblings = append(*siblings, s)
+		return
+	}
+	mergeSection(dst, target, s, appendAll)
+}
+
+func mergeSection(dst *plan.Plan, target, s *plan.Section, appendAll bool) {
+	body, marked := stripAppendMarker(s.Body)
+	if !isBlank(body) {
+		if !appendAll && !marked {
+			// Replace the body, keeping the blank lines before the next heading
+			end := len(target.Body)
+			for end > 0 && target.Body[end-1].Kind == plan.NodeBlank {
+				end--
+			}
+			target.Body = target.Body[end:]
+		}
+		appendNodes(target, body)
+	}
+	for _, c := range s.Children {
+		mergeInto(dst, &target.Children, c, appendAll)
+	}
+}
+
+// appendNodes adds nodes to the end of a section body, keeping the blank
+// lines that separate it from the next heading
+func appendNodes(s *plan.Section, nodes []*plan.Node) {
+	end := len(s.Body)
+	for end > 0 && s.Body[end-1].Kind == plan.NodeBlank {
+		end--
+	}
+	trailing := append([]*plan.Node(nil), s.Body[end:]...)
+
+	start := 0
+	for start < len(nodes) && nodes[start].Kind == plan.NodeBlank {
+		start++
+	}
+	stop := len(nodes)
+	for stop > start && nodes[stop-1].Kind == plan.NodeBlank {
+		stop--
+	}
+
+	body := append([]*plan.Node(nil), s.Body[:end]...)
+	if end > 0 && !strings.HasSuffix(body[end-1].Raw, "\n") {
+		body[end-1].Raw += "\n"
+	} else if end == 0 && !strings.HasSuffix(s.Heading.Raw, "\n") {
+		s.Heading.Raw += "\n"
+	}
+	body = append(body, nodes[start:stop]...)
+	if stop > start && !strings.HasSuffix(nodes[stop-1].Raw, "\n") {
+		nodes[stop-1].Raw += "\n"
+	}
+	if len(trailing) == 0 {
+		trailing = []*plan.Node{{Kind: plan.NodeBlank, Raw: "\n"}}
+	}
+	s.Body = append(body, trailing...)
+}
+
+// ensureTrailingBlank makes sure the last section ends with a blank line
+// so a section added after it starts a new paragraph
+func ensureTrailingBlank(siblings []*plan.Section) {
+	if len(siblings) == 0 {
+		return
+	}
+	last := siblings[len(siblings)-1]
+	for len(last.Children) > 0 {
+		last = last.Children[len(last.Children)-1]
+	}
+	if len(last.Body) > 0 && last.Body[len(last.Body)-1].Kind == plan.NodeBlank {
+		return
+	}
+	appendNodes(last, nil)
+}
+
+func stripAppendMarker(nodes []*plan.Node) ([]*plan.Node, bool) {
+	var out []*plan.Node
+	marked := false
+	for _, n := range nodes {
+		if n.Kind == plan.NodeComment && strings.TrimSpace(n.Text) == appendMarker {
+			marked = true
+			continue
+		}
+		out = append(out, n)
+	}
+	return out, marked
+}
+
+func isBlank(nodes []*plan.Node) bool {
+	for _, n := range nodes {
+		if n.Kind != plan.NodeBlank {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,34 @@
+package templates
+
+import (
+	"testing"
+
+	"github.com/drpedapati/irl-template/pkg/plan"
+)
+
+func TestMergeKeepsSpacingAroundSections(t *testing.T) {
+	parent := "# Base\n\n## Funding\n\nTBD\n\n## 🔁 Instruction Loop\n\nLoop here\n"
+	tests := []struct {
+		name, child, want string
+	}{
+		{
+			"replace",
+			"## Funding\n- Funded by NIH\n",
+			"# Base\n\n## Funding\n- Funded by NIH\n\n## 🔁 Instruction Loop\n\nLoop here\n",
+		},
+		{
+			"append",
+			"## Funding\n<!-- irl:append -->\n- Funded by NIH\n",
+			"# Base\n\n## Funding\n\nTBD\n- Funded by NIH\n\n## 🔁 Instruction Loop\n\nLoop here\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dst := plan.Parse(parent)
+			merge(dst, plan.Parse(tt.child), false)
+			if got := dst.String(); got != tt.want {
+				t.Errorf("merged plan:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,432 @@
+package templates
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/drpedapati/irl-template/pkg/config"
+	"github.com/drpedapati/irl-template/pkg/gitutil"
+)
+
+// Source is a catalog of templates. Sources are queried in precedence
+// order: configured sources as listed, then the default GitHub catalog.
+// When two sources offer the same name, the earlier one wins.
+type Source interface {
+	Name() string
+	List() ([]Template, error)
+}
+
+// Source types accepted in config
+const (
+	SourceDir    = "dir"
+	SourceGit    = "git"
+	SourceHTTP   = "http"
+	SourceGitHub = "github"
+)
+
+// DefaultSourceName labels the built-in GitHub catalog
+const DefaultSourceName = "default"
+
+var httpClient = &http.Client{Timeout: 30 * time.Second}
+
+// Sources returns the configured sources followed by the default catalog
+func Sources() ([]Source, error) {
+	var sources []Source
+	for _, cfg := range config.GetTemplateSources() {
+		src, err := NewSource(cfg)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, src)
+	}
+	return append(sources, DefaultSource()), nil
+}
+
+// DefaultSource is the upstream irl-template catalog on GitHub
+func DefaultSource() Source {
+	return &GitHubSource{Label: DefaultSourceName, Repo: GitHubRepo, Path: TemplatesPath}
+}
+
+// NewSource builds a source from its config entry
+func NewSource(cfg config.TemplateSource) (Source, error) {
+	if err := ValidateSourceName(cfg.Name); err != nil {
+		return nil, err
+	}
+	switch cfg.Type {
+	case SourceDir:
+		return &DirSource{Label: cfg.Name, Path: filepath.Join(cfg.Location, cfg.Path)}, nil
+	case SourceGit:
+		return &GitSource{Label: cfg.Name, URL: cfg.Location, Ref: cfg.Ref, Subdir: cfg.Path}, nil
+	case SourceHTTP:
+		return &HTTPSource{Label: cfg.Name, URL: cfg.Location}, nil
+	case SourceGitHub:
+		return &GitHubSource{Label: cfg.Name, Repo: cfg.Location, Path: cfg.Path, Ref: cfg.Ref}, nil
+	}
+	return nil, fmt.Errorf("template source %s: unknown type %q", cfg.Name, cfg.Type)
+}
+
+// DetectSourceType guesses a source type from its location
+func DetectSourceType(location string) string {
+	switch {
+	case strings.HasSuffix(location, ".git"), strings.HasPrefix(location, "git@"),
+		strings.HasPrefix(location, "ssh://"), strings.HasPrefix(location, "git://"),
+		strings.HasPrefix(location, "file://"):
+		return SourceGit
+	case strings.HasPrefix(location, "http://"), strings.HasPrefix(location, "https://"):
+		return SourceHTTP
+	case githubRepoPattern.MatchString(location):
+		if _, err := os.Stat(location); err != nil {
+			return SourceGitHub
+		}
+	}
+	if out, err := gitutil.Run(location, "rev-parse", "--is-bare-repository"); err == nil && out == "true" {
+		return SourceGit
+	}
+	return SourceDir
+}
+
+var githubRepoPattern = regexp.MustCompile(`^[\w.-]+/[\w.-]+$`)
+
+var sourceNamePattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// ValidateSourceName checks that a source name is safe to use as a folder
+// name under ~/.irl/sources: lowercase letters, digits and hyphens
+func ValidateSourceName(name string) error {
+	if !sourceNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid template source name %q: use lowercase letters, digits and hyphens", name)
+	}
+	return nil
+}
+
+// validTemplateName reports whether a template name from a source is a
+// single file name, safe to cache as <name>.md. Remote indexes can name
+// templates anything.
+func validTemplateName(name string) bool {
+	return name != "." && filepath.IsLocal(name) && !strings.ContainsAny(name, `/\`)
+}
+
+// DirSource reads templates from a local folder: <name>.md files and
+// <name>/main-plan.md directory templates
+type DirSource struct {
+	Label string
+	Path  string
+}
+
+func (s *DirSource) Name() string { return s.Label }
+
+func (s *DirSource) List() ([]Template, error) {
+	entries, err := os.ReadDir(s.Path)
+	if err != nil {
+		return nil, fmt.Errorf("template source %s: %w", s.Label, err)
+	}
+
+	var list []Template
+	for _, e := range entries {
+		name := e.Name()
+		if strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") {
+			continue
+		}
+		if e.IsDir() {
+			dir := filepath.Join(s.Path, name)
+			content, err := os.ReadFile(filepath.Join(dir, PlanFile))
+			if err != nil {
+				continue
+			}
+			list = append(list, Template{
+				Name:        name,
+				Description: customDescription(string(content)),
+				Content:     string(content),
+				Dir:         dir,
+				Source:      s.Label,
+			})
+			continue
+		}
+		if !strings.HasSuffix(name, ".md") || strings.EqualFold(name, "README.md") {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(s.Path, name))
+		if err != nil {
+			continue
+		}
+		list = append(list, Template{
+			Name:        strings.TrimSuffix(name, ".md"),
+			Description: customDescription(string(content)),
+			Content:     string(content),
+			Source:      s.Label,
+		})
+	}
+	return list, nil
+}
+
+// Names lists the folder's template names
+func (s *DirSource) Names() ([]string, error) {
+	list, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(list))
+	for i, t := range list {
+		names[i] = t.Name
+	}
+	return names, nil
+}
+
+// GitSource reads templates from a git repository at a ref. The
+// repository is fetched into ~/.irl/sources/<name> and read like a
+// DirSource.
+type GitSource struct {
+	Label  string
+	URL    string
+	Ref    string // branch, tag or commit; the remote HEAD when empty
+	Subdir string // folder inside the repository holding the templates
+}
+
+func (s *GitSource) Name() string { return s.Label }
+
+// Checkout returns the local clone directory
+func (s *GitSource) Checkout() (string, error) {
+	if err := ValidateSourceName(s.Label); err != nil {
+		return "", err
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, CacheDir, "sources", s.Label), nil
+}
+
+// Sync fetches the configured ref into the local clone
+func (s *GitSource) Sync() (string, error) {
+	dir, err := s.Checkout()
+	if err != nil {
+		return "", err
+	}
+	if !fileExists(filepath.Join(dir, ".git")) {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return "", err
+		}
+		if _, err := gitutil.Run(dir, "init", "-q"); err != nil {
+			return "", err
+		}
+	}
+
+	ref := s.Ref
+	if ref == "" {
+		ref = "HEAD"
+	}
+	if _, err := gitutil.Run(dir, "fetch", "-q", "--depth", "1", s.URL, ref); err != nil {
+		return "", fmt.Errorf("template source %s: %w", s.Label, err)
+	}
+	if _, err := gitutil.Run(dir, "checkout", "-q", "--force", "FETCH_HEAD"); err != nil {
+		return "", fmt.Errorf("template source %s: %w", s.Label, err)
+	}
+	return dir, nil
+}
+
+func (s *GitSource) List() ([]Template, error) {
+	if s.Subdir != "" && !filepath.IsLocal(s.Subdir) {
+		return nil, fmt.Errorf("template source %s: path %q leaves the repository", s.Label, s.Subdir)
+	}
+	dir, err := s.Sync()
+	if err != nil {
+		return nil, err
+	}
+	return (&DirSource{Label: s.Label, Path: filepath.Join(dir, s.Subdir)}).List()
+}
+
+// HTTPSource reads a static JSON index:
+//
+//	{"templates": [{"name": "lab-basic", "description": "...", "url": "lab-basic.md"}]}
+//
+// A bare array of entries is accepted too. Relative URLs are resolved
+// against the index URL.
+type HTTPSource struct {
+	Label  string
+	URL    string
+	Client *http.Client // defaults to a client with a 30s timeout
+}
+
+type indexEntry struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	URL         string `json:"url"`
+}
+
+func (s *HTTPSource) Name() string { return s.Label }
+
+func (s *HTTPSource) index() ([]indexEntry, error) {
+	data, err := get(s.Client, s.URL)
+	if err != nil {
+		return nil, fmt.Errorf("template source %s: %w", s.Label, err)
+	}
+
+	var entries []indexEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		var wrapped struct {
+			Templates []indexEntry `json:"templates"`
+		}
+		if err := json.Unmarshal(data, &wrapped); err != nil {
+			return nil, fmt.Errorf("template source %s: invalid index: %w", s.Label, err)
+		}
+		entries = wrapped.Templates
+	}
+	return entries, nil
+}
+
+func (s *HTTPSource) List() ([]Template, error) {
+	entries, err := s.index()
+	if err != nil {
+		return nil, err
+	}
+	base, err := url.Parse(s.URL)
+	if err != nil {
+		return nil, fmt.Errorf("template source %s: %w", s.Label, err)
+	}
+
+	var list []Template
+	for _, e := range entries {
+		if !validTemplateName(e.Name) || e.URL == "" {
+			continue
+		}
+		ref, err := url.Parse(e.URL)
+		if err != nil {
+			continue
+		}
+		data, err := get(s.Client, base.ResolveReference(ref).String())
+		if err != nil {
+			continue
+		}
+		desc := e.Description
+		if desc == "" {
+			desc = customDescription(string(data))
+		}
+		list = append(list, Template{Name: e.Name, Description: desc, Content: string(data), Source: s.Label})
+	}
+	return list, nil
+}
+
+// Names lists template names from the index without downloading them
+func (s *HTTPSource) Names() ([]string, error) {
+	entries, err := s.index()
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, e := range entries {
+		if validTemplateName(e.Name) {
+			names = append(names, e.Name)
+		}
+	}
+	return names, nil
+}
+
+// GitHubSource reads *.md templates from a folder of a GitHub repository
+// through the contents API
+type GitHubSource struct {
+	Label   string
+	Repo    string // owner/name
+	Path    string // folder inside the repository
+	Ref     string // branch, tag or commit; the default branch when empty
+	APIBase string // defaults to https://api.github.com
+	Client  *http.Client
+}
+
+type githubFile struct {
+	Name        string `json:"name"`
+	DownloadURL string `json:"download_url"`
+}
+
+func (s *GitHubSource) Name() string { return s.Label }
+
+func (s *GitHubSource) files() ([]githubFile, error) {
+	base := s.APIBase
+	if base == "" {
+		base = "https://api.github.com"
+	}
+	u := fmt.Sprintf("%s/repos/%s/contents/%s", strings.TrimSuffix(base, "/"), s.Repo, s.Path)
+	if s.Ref != "" {
+		u += "?ref=" + url.QueryEscape(s.Ref)
+	}
+
+	data, err := get(s.Client, u)
+	if err != nil {
+		return nil, err
+	}
+	var files []githubFile
+	if err := json.Unmarshal(data, &files); err != nil {
+		return nil, err
+	}
+
+	var md []githubFile
+	for _, f := range files {
+		if strings.HasSuffix(f.Name, ".md") && validTemplateName(f.Name) {
+			md = append(md, f)
+		}
+	}
+	return md, nil
+}
+
+func (s *GitHubSource) List() ([]Template, error) {
+	files, err := s.files()
+	if err != nil {
+		return nil, err
+	}
+
+	var list []Template
+	for _, f := range files {
+		data, err := get(s.Client, f.DownloadURL)
+		if err != nil {
+			continue
+		}
+		name := strings.TrimSuffix(f.Name, ".md")
+		desc := getDescription(name)
+		if desc == "Template" {
+			desc = customDescription(string(data))
+		}
+		list = append(list, Template{Name: name, Description: desc, Content: string(data), Source: s.Label})
+	}
+	return list, nil
+}
+
+// Names lists template names without downloading them
+func (s *GitHubSource) Names() ([]string, error) {
+	files, err := s.files()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(files))
+	for i, f := range files {
+		names[i] = strings.TrimSuffix(f.Name, ".md")
+	}
+	return names, nil
+}
+
+// get fetches a URL, treating non-200 responses as errors
+func get(client *http.Client, u string) ([]byte, error) {
+	if client == nil {
+		client = httpClient
+	}
+	resp, err := client.Get(u)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s returned %d", u, resp.StatusCode)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
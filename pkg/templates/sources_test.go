@@ -0,0 +1,193 @@
+package templates
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/drpedapati/irl-template/pkg/config"
+)
+
+func TestValidateSourceName(t *testing.T) {
+	for _, name := range []string{"lab", "lab-2", "neuro-lab"} {
+		if err := ValidateSourceName(name); err != nil {
+			t.Errorf("ValidateSourceName(%q) = %v", name, err)
+		}
+	}
+	for _, name := range []string{"", "..", "../../.ssh", "a/b", "Lab", "-lab", "lab-", "lab name", `a\b`} {
+		if err := ValidateSourceName(name); err == nil {
+			t.Errorf("ValidateSourceName(%q) accepted", name)
+		}
+		if _, err := NewSource(config.TemplateSource{Name: name, Type: SourceGit, Location: "x.git"}); err == nil {
+			t.Errorf("NewSource accepted name %q", name)
+		}
+	}
+}
+
+func TestHTTPSource(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/irl/index.json":
+			fmt.Fprint(w, `{"templates": [
+				{"name": "lab-basic", "url": "lab-basic.md"},
+				{"name": "lab-full", "description": "Everything", "url": "/irl/full.md"},
+				{"name": "gone", "url": "missing.md"},
+				{"name": "../../pwned", "url": "lab-basic.md"},
+				{"name": "nested/name", "url": "lab-basic.md"}
+			]}`)
+		case "/irl/lab-basic.md":
+			fmt.Fprint(w, "<!-- Basic lab plan -->\n# Plan\n")
+		case "/irl/full.md":
+			fmt.Fprint(w, "# Full\n")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	src := &HTTPSource{Label: "lab", URL: srv.URL + "/irl/index.json", Client: srv.Client()}
+	names, err := src.Names()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(names) != "[lab-basic lab-full gone]" {
+		t.Errorf("Names() = %v, want the entries that are file names", names)
+	}
+
+	list, err := src.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 {
+		t.Fatalf("List() returned %v, want 2 (the missing one and the paths skipped)", templateNames(list))
+	}
+	if list[0].Name != "lab-basic" || list[0].Content != "<!-- Basic lab plan -->\n# Plan\n" || list[0].Source != "lab" {
+		t.Errorf("first template = %+v", list[0])
+	}
+	if list[1].Description != "Everything" || list[1].Content != "# Full\n" {
+		t.Errorf("second template = %+v", list[1])
+	}
+
+	if _, err := (&HTTPSource{Label: "lab", URL: srv.URL + "/nope.json", Client: srv.Client()}).List(); err == nil {
+		t.Error("List() of a missing index succeeded")
+	}
+}
+
+// bareRepo makes a bare repository holding files on its default branch,
+// plus a v2 tag that adds extra
+func bareRepo(t *testing.T, files, extra map[string]string) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	work := filepath.Join(dir, "work")
+	bare := filepath.Join(dir, "templates.git")
+	git := func(dir string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=Test", "-c", "user.email=test@example.org"}, args...)...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	write := func(files map[string]string) {
+		for name, body := range files {
+			p := filepath.Join(work, filepath.FromSlash(name))
+			os.MkdirAll(filepath.Dir(p), 0755)
+			if err := os.WriteFile(p, []byte(body), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	os.Mkdir(work, 0755)
+	git(work, "init", "-q")
+	write(files)
+	git(work, "add", "-A")
+	git(work, "commit", "-qm", "templates")
+	write(extra)
+	git(work, "add", "-A")
+	git(work, "commit", "-qm", "v2")
+	git(work, "tag", "v2")
+	git(work, "reset", "-q", "--hard", "HEAD~1")
+	git(dir, "clone", "-q", "--bare", work, bare)
+	git(work, "push", "-q", bare, "v2")
+	return bare
+}
+
+func TestGitSource(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	bare := bareRepo(t,
+		map[string]string{
+			"plans/lab-basic.md":       "# Basic\n",
+			"plans/grant/main-plan.md": "# Grant\n",
+			"plans/README.md":          "not a template",
+			"plans/_drafts/wip.md":     "# WIP\n",
+			"other/ignored.md":         "# Ignored\n",
+		},
+		map[string]string{"plans/lab-v2.md": "# V2\n"},
+	)
+
+	if got := DetectSourceType(bare); got != SourceGit {
+		t.Errorf("DetectSourceType(bare repo) = %q", got)
+	}
+
+	src := &GitSource{Label: "lab", URL: bare, Subdir: "plans"}
+	list, err := src.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names := templateNames(list); fmt.Sprint(names) != "[grant lab-basic]" {
+		t.Errorf("templates at HEAD = %v", names)
+	}
+	dir, _ := src.Checkout()
+	if want := filepath.Join(home, CacheDir, "sources", "lab"); dir != want {
+		t.Errorf("Checkout() = %s, want %s", dir, want)
+	}
+
+	src.Ref = "v2"
+	list, err = src.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names := templateNames(list); fmt.Sprint(names) != "[grant lab-basic lab-v2]" {
+		t.Errorf("templates at v2 = %v", names)
+	}
+
+	for _, bad := range []*GitSource{
+		{Label: "../../evil", URL: bare},
+		{Label: "lab", URL: bare, Subdir: "../../.."},
+	} {
+		if _, err := bad.List(); err == nil {
+			t.Errorf("List() of %+v succeeded", *bad)
+		}
+	}
+	if entries, _ := os.ReadDir(filepath.Join(home, CacheDir, "sources")); len(entries) != 1 {
+		t.Errorf("sources cache holds %d entries, want only lab", len(entries))
+	}
+}
+
+func templateNames(list []Template) []string {
+	names := make([]string, len(list))
+	for i, t := range list {
+		names[i] = t.Name
+	}
+	return names
+}
+
+func TestCachedIndexRejectsPaths(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "index.json"), []byte(`[{"name": "../../pwned"}]`), 0644)
+	if _, ok := loadCachedTemplates(dir); ok {
+		t.Error("loadCachedTemplates accepted a template named with a path")
+	}
+	if _, err := getStandard("../../pwned"); err == nil {
+		t.Error("getStandard accepted a template named with a path")
+	}
+}
@@ -3,7 +3,6 @@ package templates
 import (
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -22,6 +21,8 @@ type Template struct {
 	Name        string `json:"name"`
 	Description string `json:"description"`
 	Content     string `json:"-"`
+	Dir         string `json:"dir,omitempty"`    // template folder; extra files are copied from it
+	Source      string `json:"source,omitempty"` // name of the source it came from
 }
 
 // Embedded fallback templates (minimal set)
@@ -30,6 +31,7 @@ var EmbeddedTemplates = map[string]Template{
 		Name:        "irl-basic",
 		Description: "IRL basic template",
 		Content:     defaultIrlBasicTemplate,
+		Source:      "embedded",
 	},
 }
 
@@ -138,7 +140,7 @@ func ListTemplates() ([]Template, error) {
 		return templates, nil
 	}
 
-	// Try to fetch from GitHub
+	// Try to fetch from the template sources
 	if templates, err := FetchTemplates(); err == nil {
 		return templates, nil
 	}
@@ -179,6 +181,9 @@ func loadCachedTemplates(cacheDir string) ([]Template, bool) {
 
 	// Load content from cached files (since Content has json:"-")
 	for i := range templates {
+		if !validTemplateName(templates[i].Name) {
+			return nil, false
+		}
 		cachePath := filepath.Join(cacheDir, templates[i].Name+".md")
 		if content, err := os.ReadFile(cachePath); err == nil {
 			templates[i].Content = string(content)
@@ -188,57 +193,58 @@ func loadCachedTemplates(cacheDir string) ([]Template, bool) {
 	return templates, true
 }
 
-// FetchTemplates downloads latest templates from GitHub
+// FetchTemplates downloads the latest templates from every source, merges
+// them by precedence and caches the result
 func FetchTemplates() ([]Template, error) {
-	// Get template list from GitHub API
-	url := fmt.Sprintf("https://api.github.com/repos/%s/contents/%s", GitHubRepo, TemplatesPath)
-
-	resp, err := http.Get(url)
+	sources, err := Sources()
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("GitHub API returned %d", resp.StatusCode)
-	}
-
-	var files []struct {
-		Name        string `json:"name"`
-		DownloadURL string `json:"download_url"`
+	var templates []Template
+	seen := map[string]bool{}
+	var firstErr error
+	failed := 0
+	for _, src := range sources {
+		list, err := src.List()
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			failed++
+			continue
+		}
+		for _, t := range list {
+			if !validTemplateName(t.Name) || seen[t.Name] {
+				continue // unusable, or an earlier source takes precedence
+			}
+			seen[t.Name] = true
+			if t.Source == "" {
+				t.Source = src.Name()
+			}
+			templates = append(templates, t)
+		}
 	}
-
-	if err := json.NewDecoder(resp.Body).Decode(&files); err != nil {
-		return nil, err
+	if failed == len(sources) {
+		return nil, firstErr
 	}
 
 	cacheDir, _ := GetCacheDir()
 	os.MkdirAll(cacheDir, 0755)
 
-	var templates []Template
-	for _, f := range files {
-		if !strings.HasSuffix(f.Name, ".md") {
-			continue
-		}
-
-		name := strings.TrimSuffix(f.Name, ".md")
-		desc := getDescription(name)
-
-		// Download content
-		content, err := downloadFile(f.DownloadURL)
-		if err != nil {
-			continue
+	// Drop cached templates no source offers anymore
+	if entries, err := os.ReadDir(cacheDir); err == nil {
+		for _, e := range entries {
+			name := strings.TrimSuffix(e.Name(), ".md")
+			if !e.IsDir() && strings.HasSuffix(e.Name(), ".md") && !seen[name] {
+				os.Remove(filepath.Join(cacheDir, e.Name()))
+			}
 		}
+	}
 
-		// Cache it
-		cachePath := filepath.Join(cacheDir, f.Name)
-		os.WriteFile(cachePath, []byte(content), 0644)
-
-		templates = append(templates, Template{
-			Name:        name,
-			Description: desc,
-			Content:     content,
-		})
+	for _, t := range templates {
+		cachePath := filepath.Join(cacheDir, t.Name+".md")
+		os.WriteFile(cachePath, []byte(t.Content), 0644)
 	}
 
 	// Save index
@@ -249,21 +255,6 @@ func FetchTemplates() ([]Template, error) {
 	return templates, nil
 }
 
-func downloadFile(url string) (string, error) {
-	resp, err := http.Get(url)
-	if err != nil {
-		return "", err
-	}
-	defer resp.Body.Close()
-
-	data, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", err
-	}
-
-	return string(data), nil
-}
-
 func getDescription(name string) string {
 	descriptions := map[string]string{
 		"irl-basic": "IRL basic template",
@@ -274,18 +265,30 @@ func getDescription(name string) string {
 	return "Template"
 }
 
-// GetTemplate returns a specific template by name
+// GetTemplate returns a specific template by name. Custom templates in
+// the workspace take precedence; inheritance is resolved.
 func GetTemplate(name string) (Template, error) {
-	// Check cache first
+	t, err := getRaw(name)
+	if err != nil {
+		return Template{}, err
+	}
+	return Resolve(t)
+}
+
+// getStandard returns a cached, embedded or remote template
+func getStandard(name string) (Template, error) {
+	if !validTemplateName(name) {
+		return Template{}, fmt.Errorf("template '%s' not found", name)
+	}
+
+	// Check cache
 	cacheDir, _ := GetCacheDir()
 	cachePath := filepath.Join(cacheDir, name+".md")
 
 	if content, err := os.ReadFile(cachePath); err == nil {
-		return Template{
-			Name:        name,
-			Description: getDescription(name),
-			Content:     string(content),
-		}, nil
+		t := cachedEntry(cacheDir, name)
+		t.Content = string(content)
+		return t, nil
 	}
 
 	// Check embedded
@@ -294,29 +297,50 @@ func GetTemplate(name string) (Template, error) {
 	}
 
 	// Try to fetch
-	url := fmt.Sprintf("https://raw.githubusercontent.com/%s/main/%s/%s.md",
-		GitHubRepo, TemplatesPath, name)
+	list, err := FetchTemplates()
+	if err == nil {
+		for _, t := range list {
+			if t.Name == name {
+				return t, nil
+			}
+		}
+	}
+	return Template{}, fmt.Errorf("template '%s' not found", name)
+}
 
-	content, err := downloadFile(url)
+// cachedEntry returns a template's index entry, whatever the cache's age
+func cachedEntry(cacheDir, name string) Template {
+	t := Template{Name: name, Description: getDescription(name)}
+	data, err := os.ReadFile(filepath.Join(cacheDir, "index.json"))
 	if err != nil {
-		return Template{}, fmt.Errorf("template '%s' not found", name)
+		return t
 	}
-
-	return Template{
-		Name:        name,
-		Description: getDescription(name),
-		Content:     content,
-	}, nil
+	var index []Template
+	if json.Unmarshal(data, &index) != nil {
+		return t
+	}
+	for _, entry := range index {
+		if entry.Name == name {
+			return entry
+		}
+	}
+	return t
 }
 
-// Update forces a refresh of templates from GitHub
+// Update forces a refresh of templates from all sources
 func Update() error {
 	_, err := FetchTemplates()
 	return err
 }
 
-// CheckForNewTemplates returns the count of templates available on GitHub
-// that are not in the local cache (without downloading them)
+// namer is implemented by sources that can list names cheaply
+type namer interface {
+	Names() ([]string, error)
+}
+
+// CheckForNewTemplates returns the count of templates available from the
+// sources that are not in the local cache (without downloading them).
+// Git sources are skipped since listing them requires a fetch.
 func CheckForNewTemplates() (int, error) {
 	// Get cached template names
 	cachedNames := make(map[string]bool)
@@ -333,39 +357,43 @@ func CheckForNewTemplates() (int, error) {
 		}
 	}
 
-	// Query GitHub for current template list (lightweight - just metadata)
-	url := fmt.Sprintf("https://api.github.com/repos/%s/contents/%s", GitHubRepo, TemplatesPath)
-
-	client := &http.Client{Timeout: 5 * time.Second}
-	resp, err := client.Get(url)
+	sources, err := Sources()
 	if err != nil {
 		return 0, err
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != 200 {
-		return 0, fmt.Errorf("GitHub API returned %d", resp.StatusCode)
-	}
 
-	var files []struct {
-		Name string `json:"name"`
-	}
-
-	if err := json.NewDecoder(resp.Body).Decode(&files); err != nil {
-		return 0, err
-	}
-
-	// Count new templates
-	newCount := 0
-	for _, f := range files {
-		if !strings.HasSuffix(f.Name, ".md") {
+	// Count new templates (lightweight - just metadata). This runs at
+	// startup, so a slow source mustn't hold it up for long.
+	quick := &http.Client{Timeout: 5 * time.Second}
+	newNames := map[string]bool{}
+	var firstErr error
+	for _, src := range sources {
+		switch s := src.(type) {
+		case *HTTPSource:
+			s.Client = quick
+		case *GitHubSource:
+			s.Client = quick
+		}
+		n, ok := src.(namer)
+		if !ok {
+			continue
+		}
+		names, err := n.Names()
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
 			continue
 		}
-		name := strings.TrimSuffix(f.Name, ".md")
-		if !cachedNames[name] {
-			newCount++
+		for _, name := range names {
+			if !cachedNames[name] {
+				newNames[name] = true
+			}
 		}
 	}
+	if len(newNames) == 0 && firstErr != nil {
+		return 0, firstErr
+	}
 
-	return newCount, nil
+	return len(newNames), nil
 }
@@ -0,0 +1,175 @@
+package templates
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/drpedapati/irl-template/pkg/config"
+	"github.com/drpedapati/irl-template/pkg/plan"
+)
+
+// Variable is a custom prompt declared in a template's front matter:
+//
+//	---
+//	var.sponsor: Funding sponsor
+//	var.sponsor.default: NIH
+//	---
+//
+// Templates reference variables as {{sponsor}}. Built-in variables
+// (project.name, project.purpose, date, profile.*) need no declaration.
+type Variable struct {
+	Name    string
+	Prompt  string
+	Default string
+}
+
+const varPrefix = "var."
+
+// varPattern matches {{name}} and {{ name }}. Quarto shortcodes ({{< ... >}})
+// don't match because names must start with a letter or underscore.
+var varPattern = regexp.MustCompile(`\{\{\s*([A-Za-z_][\w.-]*)\s*\}\}`)
+
+// UndefinedError reports template variables that have no value
+type UndefinedError struct {
+	Names []string
+}
+
+func (e *UndefinedError) Error() string {
+	return fmt.Sprintf("undefined template variables: %s (set with --var name=value)",
+		strings.Join(e.Names, ", "))
+}
+
+// ParseVariables extracts the variable schema from template content. It
+// returns the declared variables in order and the content with the schema
+// removed; the front matter is dropped entirely if nothing else is in it.
+func ParseVariables(content string) ([]Variable, string) {
+	p := plan.Parse(content)
+	if p.FrontMatter == nil {
+		return nil, content
+	}
+
+	var vars []Variable
+	index := map[string]int{}
+	var schemaKeys []string
+
+	for _, f := range p.FrontMatter.Fields {
+		if !strings.HasPrefix(f.Key, varPrefix) {
+			continue
+		}
+		schemaKeys = append(schemaKeys, f.Key)
+
+		name := strings.TrimPrefix(f.Key, varPrefix)
+		isDefault := strings.HasSuffix(name, ".default")
+		name = strings.TrimSuffix(name, ".default")
+
+		i, ok := index[name]
+		if !ok {
+			i = len(vars)
+			index[name] = i
+			vars = append(vars, Variable{Name: name, Prompt: name})
+		}
+		if isDefault {
+			vars[i].Default = f.Value
+		} else if f.Value != "" {
+			vars[i].Prompt = f.Value
+		}
+	}
+
+	if len(schemaKeys) == 0 {
+		return nil, content
+	}
+
+	for _, k := range schemaKeys {
+		p.FrontMatter.Delete(k)
+	}
+	if len(p.FrontMatter.Fields) == 0 {
+		p.FrontMatter = nil
+		// Drop blank lines left between the removed block and the content
+		for len(p.Preamble) > 0 && p.Preamble[0].Kind == plan.NodeBlank {
+			p.Preamble = p.Preamble[1:]
+		}
+	}
+
+	return vars, p.String()
+}
+
+// BuiltinVars returns the variables every template can use
+func BuiltinVars(projectName, purpose string) map[string]string {
+	profile := config.GetProfile()
+	return map[string]string{
+		"project.name":        projectName,
+		"project.purpose":     purpose,
+		"date":                time.Now().Format("2006-01-02"),
+		"profile.name":        profile.Name,
+		"profile.title":       profile.Title,
+		"profile.institution": profile.Institution,
+		"profile.department":  profile.Department,
+		"profile.email":       profile.Email,
+	}
+}
+
+// Render replaces {{name}} references with values. Every referenced
+// variable must be defined, otherwise an *UndefinedError is returned.
+func Render(content string, values map[string]string) (string, error) {
+	missing := map[string]bool{}
+	out := varPattern.ReplaceAllStringFunc(content, func(m string) string {
+		name := varPattern.FindStringSubmatch(m)[1]
+		v, ok := values[name]
+		if !ok {
+			missing[name] = true
+			return m
+		}
+		return v
+	})
+
+	if len(missing) > 0 {
+		names := make([]string, 0, len(missing))
+		for n := range missing {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return "", &UndefinedError{Names: names}
+	}
+	return out, nil
+}
+
+// Values returns the variables for rendering a template: builtins,
+// overridden by the template's declared defaults, overridden by values
+func Values(content, projectName, purpose string, values map[string]string) map[string]string {
+	vars, _ := ParseVariables(content)
+
+	all := BuiltinVars(projectName, purpose)
+	for _, v := range vars {
+		if v.Default != "" {
+			all[v.Name] = v.Default
+		}
+	}
+	for k, v := range values {
+		all[k] = v
+	}
+	return all
+}
+
+// Apply renders template content: it strips the variable schema and
+// renders the rest with Values
+func Apply(content, projectName, purpose string, values map[string]string) (string, error) {
+	_, body := ParseVariables(content)
+	return Render(body, Values(content, projectName, purpose, values))
+}
+
+// ParseVarFlags parses "name=value" pairs from --var flags
+func ParseVarFlags(flags []string) (map[string]string, error) {
+	values := map[string]string{}
+	for _, f := range flags {
+		name, value, ok := strings.Cut(f, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid --var %q (expected name=value)", f)
+		}
+		values[name] = value
+	}
+	return values, nil
+}